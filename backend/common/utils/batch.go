@@ -0,0 +1,35 @@
+package utils
+
+// MaxBatchSizeEnv is the environment variable holding the largest number of emails of a single
+// BatchCategorizeEmails call. The API Gateway splits larger requests into batches of at most that
+// many emails and the Categorization service rejects larger batches, so both read the same variable.
+const MaxBatchSizeEnv = "CATEGORIZE_MAX_BATCH_SIZE"
+
+// DefaultMaxBatchSize is the largest number of emails of a batch when MaxBatchSizeEnv is not set.
+const DefaultMaxBatchSize = 1000
+
+// MaxBatchBytesEnv is the environment variable holding the largest encoded size, in bytes, of the
+// emails of a single BatchCategorizeEmails call.
+const MaxBatchBytesEnv = "CATEGORIZE_MAX_BATCH_BYTES"
+
+// DefaultMaxBatchBytes is the largest encoded size of a batch when MaxBatchBytesEnv is not set. It stays
+// well below the 4 MiB gRPC servers accept by default, leaving room for the rest of the request.
+const DefaultMaxBatchBytes = 3 << 20
+
+// MaxBatchSize returns the largest number of emails of a single BatchCategorizeEmails call, read from
+// MaxBatchSizeEnv. Unset or non-positive values fall back to DefaultMaxBatchSize.
+func MaxBatchSize() int {
+	if size := GetEnvAsInt(MaxBatchSizeEnv, DefaultMaxBatchSize); size > 0 {
+		return size
+	}
+	return DefaultMaxBatchSize
+}
+
+// MaxBatchBytes returns the largest encoded size of the emails of a single BatchCategorizeEmails call,
+// read from MaxBatchBytesEnv. Unset or non-positive values fall back to DefaultMaxBatchBytes.
+func MaxBatchBytes() int {
+	if size := GetEnvAsInt(MaxBatchBytesEnv, DefaultMaxBatchBytes); size > 0 {
+		return size
+	}
+	return DefaultMaxBatchBytes
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"os"
+	"strconv"
 )
 
 // GetEnv retrieves the value of the environment variable specified by `key`,
@@ -24,3 +25,59 @@ func GetEnv(key, fallback string) string {
 
 	return fallback
 }
+
+// GetEnvAsInt retrieves the value of the environment variable specified by `key`
+// and parses it as an integer.
+//
+// If the environment variable is not set, or its value is not a valid integer,
+// the provided `fallback` value is returned instead.
+//
+// Parameters:
+//
+//	-key: The name of the environment variable to look up.
+//	-fallback: The value to return if the environment variable is not set or invalid.
+//
+// Returns:
+//
+//	The integer value of the environment variable, or the `fallback` value.
+func GetEnvAsInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+
+	return parsed
+}
+
+// GetEnvAsFloat retrieves the value of the environment variable specified by `key`
+// and parses it as a floating-point number.
+//
+// If the environment variable is not set, or its value is not a valid number,
+// the provided `fallback` value is returned instead.
+//
+// Parameters:
+//
+//	-key: The name of the environment variable to look up.
+//	-fallback: The value to return if the environment variable is not set or invalid.
+//
+// Returns:
+//
+//	The float64 value of the environment variable, or the `fallback` value.
+func GetEnvAsFloat(key string, fallback float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+
+	return parsed
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// UserIDMetadataKey is the gRPC metadata key carrying the ID of the user a request is made for,
+// as authenticated by the API Gateway. Backend services trust it, so they must only be reachable
+// through the gateway.
+const UserIDMetadataKey = "x-user-id"
+
+// WithUserID returns a copy of ctx whose outgoing gRPC calls carry the given user ID as metadata.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, UserIDMetadataKey, userID)
+}
+
+// UserIDFromContext returns the user ID carried by the metadata of an incoming gRPC call,
+// or an empty string if the call does not carry one.
+func UserIDFromContext(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, UserIDMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
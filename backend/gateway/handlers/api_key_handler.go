@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// APIKeyHandler exposes the management of API keys, held by the auth gRPC service, to admins.
+// API keys authenticate scripts and server-side integrations, which cannot log in interactively.
+type APIKeyHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewAPIKeyHandler creates and returns a new instance of APIKeyHandler with a default
+// gRPC connection manager, the auth service address, and a timeout configured.
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: AUTH_SERVICE_ADDR,
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// List handles GET /admin/api-keys and returns the API keys of the user given by the user_id query
+// parameter, or of every user without it, newest first. Revoked and expired keys are included.
+func (h *APIKeyHandler) List(c *gin.Context) {
+	h.withClient(c, func(ctx context.Context, client pb.AuthServiceClient) {
+		response, err := client.ListApiKeys(ctx, &pb.ListApiKeysRequest{UserId: c.Query("user_id")})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to list API keys", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"data":   response.ApiKeys,
+		})
+	})
+}
+
+// Create handles POST /admin/api-keys and issues an API key to a user. The response carries the
+// full key, which is never shown again.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var requestData APIKeyRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	request := &pb.CreateApiKeyRequest{
+		UserId: requestData.UserID,
+		Name:   requestData.Name,
+		Scopes: requestData.Scopes,
+	}
+	if requestData.ExpiresAt != nil {
+		request.ExpiresAt = requestData.ExpiresAt.Unix()
+	}
+
+	h.withClient(c, func(ctx context.Context, client pb.AuthServiceClient) {
+		response, err := client.CreateApiKey(ctx, request)
+		if err != nil {
+			h.handleGRPCError(c, "Failed to create API key", err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   response,
+		})
+	})
+}
+
+// Revoke handles DELETE /admin/api-keys/:id and revokes an API key, which is rejected from then on.
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	h.withClient(c, func(ctx context.Context, client pb.AuthServiceClient) {
+		response, err := client.RevokeApiKey(ctx, &pb.RevokeApiKeyRequest{Id: c.Param("id")})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to revoke API key", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"data":   response.ApiKey,
+		})
+	})
+}
+
+// withClient establishes a gRPC connection to the auth service and calls fn with a client
+// and a context bounded by the handler's timeout. Connection failures are reported to the caller.
+func (h *APIKeyHandler) withClient(c *gin.Context, fn func(ctx context.Context, client pb.AuthServiceClient)) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	fn(ctx, pb.NewAuthServiceClient(conn))
+}
+
+// handleGRPCError maps a gRPC status error returned by the auth service
+// to the matching HTTP status code and reports it to the caller.
+func (h *APIKeyHandler) handleGRPCError(c *gin.Context, message string, err error) {
+	h.handleError(c, httpStatusFromGRPC(err), message, errors.New(status.Convert(err).Message()))
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *APIKeyHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in API key handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
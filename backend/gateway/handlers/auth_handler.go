@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+)
+
+// AuthHandler forwards registration, login, and token requests to the auth gRPC service,
+// which owns user accounts and issues the tokens authenticating them.
+type AuthHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewAuthHandler creates and returns a new instance of AuthHandler with a default
+// gRPC connection manager, the auth service address, and a timeout configured.
+func NewAuthHandler() *AuthHandler {
+	return &AuthHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: AUTH_SERVICE_ADDR,
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// Register handles POST /auth/register. It expects a JSON payload with the new user's email,
+// password, and optional name, and responds with the created user and a pair of tokens.
+func (h *AuthHandler) Register(c *gin.Context) {
+	var requestData RegisterRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.call(c, http.StatusCreated, "Failed to register", func(ctx context.Context, client pb.AuthServiceClient) (any, error) {
+		return client.Register(ctx, &pb.RegisterRequest{
+			Email:    requestData.Email,
+			Password: requestData.Password,
+			Name:     requestData.Name,
+		})
+	})
+}
+
+// Login handles POST /auth/login. It expects a JSON payload with the user's email and password,
+// and responds with the user and a new pair of tokens.
+func (h *AuthHandler) Login(c *gin.Context) {
+	var requestData LoginRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.call(c, http.StatusOK, "Failed to log in", func(ctx context.Context, client pb.AuthServiceClient) (any, error) {
+		return client.Login(ctx, &pb.LoginRequest{
+			Email:    requestData.Email,
+			Password: requestData.Password,
+		})
+	})
+}
+
+// Refresh handles POST /auth/refresh. It expects a JSON payload with a refresh token, and responds
+// with a new pair of tokens. Every refresh token can only be exchanged once.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var requestData RefreshTokenRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.call(c, http.StatusOK, "Failed to refresh tokens", func(ctx context.Context, client pb.AuthServiceClient) (any, error) {
+		return client.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: requestData.RefreshToken})
+	})
+}
+
+// Logout handles POST /auth/logout. It expects a JSON payload with the user's refresh token,
+// which is revoked along with every token renewed from the same login.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var requestData RefreshTokenRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.call(c, http.StatusOK, "Failed to log out", func(ctx context.Context, client pb.AuthServiceClient) (any, error) {
+		return client.RevokeToken(ctx, &pb.RevokeTokenRequest{RefreshToken: requestData.RefreshToken})
+	})
+}
+
+// PublicKeys handles GET /.well-known/jwks.json. It responds with the JSON Web Key Set of the auth
+// service, so clients and other services can verify access tokens themselves.
+func (h *AuthHandler) PublicKeys(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	response, err := pb.NewAuthServiceClient(conn).GetPublicKeys(ctx, &pb.GetPublicKeysRequest{})
+	if err != nil {
+		h.handleError(c, httpStatusFromGRPC(err), "Failed to get public keys", errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	keys := make([]jwt.JWK, len(response.Keys))
+	for i, key := range response.Keys {
+		keys[i] = jwt.JWK{
+			KeyType:   key.Kty,
+			KeyID:     key.Kid,
+			Use:       key.Use,
+			Algorithm: key.Alg,
+			Modulus:   key.N,
+			Exponent:  key.E,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// call connects to the auth service, makes a call with the request's timeout, and responds with the
+// call's response and the given status code, or with the call's error mapped to an HTTP status.
+func (h *AuthHandler) call(c *gin.Context, successStatus int, failureMessage string, rpc func(context.Context, pb.AuthServiceClient) (any, error)) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	response, err := rpc(ctx, pb.NewAuthServiceClient(conn))
+	if err != nil {
+		h.handleError(c, httpStatusFromGRPC(err), failureMessage, errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	c.JSON(successStatus, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *AuthHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in auth handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
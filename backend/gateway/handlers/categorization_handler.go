@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	utils "github.com/samiransarii/inboXpert/common/utils"
 	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+	"google.golang.org/protobuf/proto"
 )
 
 // CategorizationHandler coordinates the process of receiving incoming email data,
@@ -17,34 +19,54 @@ import (
 // It:
 // - Parses incoming JSON requests containing one or more emails.
 // - Connects to the email categorization service via gRPC.
-// - Sends emails for categorization and collects responses.
+// - Splits the emails into batches and sends them for categorization concurrently.
 // - Returns a consolidated response that includes both successful results and any failures.
 type CategorizationHandler struct {
-	grpcManager *utils.GRPCClientManager
-	serviceAddr string
-	grpcTimeout time.Duration
+	grpcManager   *utils.GRPCClientManager
+	serviceAddr   string
+	grpcTimeout   time.Duration
+	maxBatchSize  int
+	maxBatchBytes int
+	quota         EmailQuota
+}
+
+// EmailQuota counts the emails of categorization requests against the daily quota of their client.
+type EmailQuota interface {
+	// ConsumeEmails counts n emails against the quota of the client of a request. If the quota has
+	// no room left for them, it rejects the request itself and returns false.
+	ConsumeEmails(c *gin.Context, n int) bool
 }
 
 // NewCategorizationHandler creates and returns a new instance of CategorizationHandler with a default
-// gRPC connection manager, the service address, and a timeout configured.
+// gRPC connection manager, the service address, a timeout, and the maximum batch size and payload configured.
 func NewCategorizationHandler() *CategorizationHandler {
 	return &CategorizationHandler{
-		grpcManager: utils.GetGRPCClientManager(),
-		serviceAddr: "localhost:50051",
-		grpcTimeout: 15 * time.Second,
+		grpcManager:   utils.GetGRPCClientManager(),
+		serviceAddr:   "localhost:50051",
+		grpcTimeout:   15 * time.Second,
+		maxBatchSize:  CATEGORIZE_MAX_BATCH_SIZE,
+		maxBatchBytes: CATEGORIZE_MAX_BATCH_BYTES,
 	}
 }
 
+// SetQuota makes the handler count every email it categorizes against the daily quota of the
+// request's client, rejecting the requests exceeding it. Without one, emails are not counted.
+func (h *CategorizationHandler) SetQuota(quota EmailQuota) {
+	h.quota = quota
+}
+
 // Handle is the main entry point for categorizing emails. It expects a JSON payload containing
 // an array of emails. It then:
 //   - Parses and validates the incoming request.
+//   - Counts the emails against the daily quota of the request's client, if one is set.
 //   - Establishes a connection to the gRPC categorization service.
-//   - Iterates over each email, sends it for categorization, and records the result.
+//   - Splits the emails into batches no larger than the maximum batch size and payload, and
+//     sends every batch concurrently through BatchCategorizeEmails.
 //   - Responds with a JSON object reporting how many emails were processed, how many succeeded,
-//     how many failed, and the details of the results.
+//     how many failed, and the details of the results in the same order as the input.
 func (h *CategorizationHandler) Handle(c *gin.Context) {
-	// Create a context with a timeout for the gRPC call
-	ctx, cancel := context.WithTimeout(context.Background(), h.grpcTimeout)
+	// Create a context with a timeout for the gRPC call, carrying the authenticated user
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
 	defer cancel()
 
 	var requestData CategorizeServiceRequest
@@ -52,6 +74,9 @@ func (h *CategorizationHandler) Handle(c *gin.Context) {
 		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
+	if h.quota != nil && !h.quota.ConsumeEmails(c, len(requestData.Emails)) {
+		return
+	}
 
 	// Attempt to establish a gRPC connection to the categorization service
 	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
@@ -62,25 +87,30 @@ func (h *CategorizationHandler) Handle(c *gin.Context) {
 
 	client := pb.NewEmailCategorizationServiceClient(conn)
 
+	// Each outcome slot corresponds to the email at the same index in the request,
+	// which lets the batches complete in any order while the response keeps the input order.
+	outcomes := make([]emailOutcome, len(requestData.Emails))
+
+	var wg sync.WaitGroup
+	for _, batch := range h.splitBatches(requestData.Emails) {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			h.categorizeBatch(ctx, client, &requestData, requestData.Emails[start:end], outcomes[start:end])
+		}(batch.start, batch.end)
+	}
+	wg.Wait()
+
 	var responses []*pb.CategorizeResponse
 	var failedEmails []FailedEmail
 
-	// Process each email individually
-	for _, email := range requestData.Emails {
-		grpcRequest := h.createGRPCRequest(email)
-
-		// Send the email for categorization via gRPC
-		response, err := client.CategorizeEmail(ctx, grpcRequest)
-		if err != nil {
-			log.Printf("Error processing email %s: %v", email.ID, err)
-			failedEmails = append(failedEmails, FailedEmail{
-				ID:    email.ID,
-				Error: err.Error(),
-			})
+	// Merge the batch outcomes back into a single list of results and failures
+	for _, outcome := range outcomes {
+		if outcome.failure != nil {
+			failedEmails = append(failedEmails, *outcome.failure)
 			continue
 		}
-
-		responses = append(responses, response)
+		responses = append(responses, &pb.CategorizeResponse{Result: outcome.result})
 	}
 
 	// Construct a unified JSON response indicating the success/failure of each processed email
@@ -96,6 +126,43 @@ func (h *CategorizationHandler) Handle(c *gin.Context) {
 	})
 }
 
+// emailOutcome holds the result of categorizing a single email within a batch.
+// Exactly one of result or failure is set once the batch has completed.
+type emailOutcome struct {
+	result  *pb.CategoryResult
+	failure *FailedEmail
+}
+
+// categorizeBatch sends a single batch of emails to the categorization service and records
+// the outcome of every email into the matching slot of outcomes. If the whole batch fails,
+// every email in it is reported as failed with the same error. The service returns exactly one
+// result per email in request order, so results are matched to their emails by position; any
+// email the service did not return a result for is marked as failed.
+func (h *CategorizationHandler) categorizeBatch(ctx context.Context, client pb.EmailCategorizationServiceClient, request *CategorizeServiceRequest, emails []EmailRequest, outcomes []emailOutcome) {
+	response, err := client.BatchCategorizeEmails(ctx, h.createBatchGRPCRequest(request, emails))
+	if err != nil {
+		log.Printf("Error processing batch of %d emails: %v", len(emails), err)
+		for i, email := range emails {
+			outcomes[i].failure = &FailedEmail{ID: email.ID, Error: err.Error()}
+		}
+		return
+	}
+
+	for i, email := range emails {
+		if i >= len(response.Results) {
+			outcomes[i].failure = &FailedEmail{ID: email.ID, Error: "no result returned by categorization service"}
+			continue
+		}
+
+		result := response.Results[i]
+		if result.Error != "" {
+			outcomes[i].failure = &FailedEmail{ID: email.ID, Error: result.Error}
+			continue
+		}
+		outcomes[i].result = result
+	}
+}
+
 // FailedEmail represents information about an email that could not be categorized successfully.
 // It stores the email's unique identifier and the error message returned by the service.
 type FailedEmail struct {
@@ -105,19 +172,57 @@ type FailedEmail struct {
 	Error string `json:"error"`
 }
 
-// createGRPCRequest transforms an EmailRequest into a gRPC CategorizeRequest message
+// batchRange is the range [start, end) of the emails of a request sent in one batch.
+type batchRange struct {
+	start, end int
+}
+
+// splitBatches splits emails into consecutive batches holding at most maxBatchSize emails whose
+// encoded size stays within maxBatchBytes. An email larger than maxBatchBytes on its own is sent
+// alone, leaving it to the service to accept or reject it.
+func (h *CategorizationHandler) splitBatches(emails []EmailRequest) []batchRange {
+	var batches []batchRange
+	start, size := 0, 0
+	for i, email := range emails {
+		emailSize := proto.Size(h.createGRPCEmail(email))
+		if i > start && (i-start >= h.maxBatchSize || size+emailSize > h.maxBatchBytes) {
+			batches = append(batches, batchRange{start: start, end: i})
+			start, size = i, 0
+		}
+		size += emailSize
+	}
+	if start < len(emails) {
+		batches = append(batches, batchRange{start: start, end: len(emails)})
+	}
+	return batches
+}
+
+// createGRPCEmail transforms an EmailRequest into a gRPC Email message
 // to be sent to the categorization service.
-func (h *CategorizationHandler) createGRPCRequest(email EmailRequest) *pb.CategorizeRequest {
-	return &pb.CategorizeRequest{
-		Email: &pb.Email{
-			Id:         email.ID,
-			Subject:    email.Subject,
-			Body:       email.Body,
-			Sender:     email.Sender,
-			Recipients: email.Recipients,
-			Headers:    email.Headers,
-		},
+func (h *CategorizationHandler) createGRPCEmail(email EmailRequest) *pb.Email {
+	return &pb.Email{
+		Id:         email.ID,
+		Mailbox:    email.Mailbox,
+		Subject:    email.Subject,
+		Body:       email.Body,
+		Sender:     email.Sender,
+		Recipients: email.Recipients,
+		Headers:    email.Headers,
+	}
+}
+
+// createBatchGRPCRequest transforms a slice of EmailRequests into a gRPC BatchCategorizeRequest
+// message to be sent to the categorization service, carrying over the request-level options.
+func (h *CategorizationHandler) createBatchGRPCRequest(req *CategorizeServiceRequest, emails []EmailRequest) *pb.BatchCategorizeRequest {
+	request := &pb.BatchCategorizeRequest{
+		Emails:            make([]*pb.Email, 0, len(emails)),
+		ForceRecategorize: req.ForceRecategorize,
+		UserId:            req.UserID,
+	}
+	for _, email := range emails {
+		request.Emails = append(request.Emails, h.createGRPCEmail(email))
 	}
+	return request
 }
 
 // parseRequest attempts to parse the incoming JSON request body into a CategorizeServiceRequest.
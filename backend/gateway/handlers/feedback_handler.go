@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// FeedbackHandler receives users' corrections of predicted categories and forwards them to the
+// email categorization gRPC service, which stores them next to the original prediction.
+type FeedbackHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewFeedbackHandler creates and returns a new instance of FeedbackHandler with a default
+// gRPC connection manager, the service address, and a timeout configured.
+func NewFeedbackHandler() *FeedbackHandler {
+	return &FeedbackHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: "localhost:50051",
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// Handle handles POST /feedback. It expects a JSON payload identifying the email and the category
+// the user moved it to, and responds with the original prediction alongside the correction.
+func (h *FeedbackHandler) Handle(c *gin.Context) {
+	var requestData FeedbackRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	client := pb.NewEmailCategorizationServiceClient(conn)
+	response, err := client.SubmitFeedback(ctx, &pb.SubmitFeedbackRequest{
+		EmailId:           requestData.ID,
+		Mailbox:           requestData.Mailbox,
+		CorrectedCategory: requestData.CorrectedCategory,
+		UserId:            requestData.UserID,
+	})
+	if err != nil {
+		h.handleError(c, httpStatusFromGRPC(err), "Failed to submit feedback", errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response,
+	})
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *FeedbackHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in feedback handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -17,4 +17,18 @@ var (
 	// PRIORITY_FILTER_SERVICE_URL is the endpoint for the Priority service.
 	// It defaults to "https://localhost/3003" if the PRIORITY_FILTER_SERVICE environment variable is not set.
 	PRIORITY_FILTER_SERVICE_URL = utils.GetEnv("PRIORITY_FILTER_SERVICE", "https://localhost/3003")
+
+	// AUTH_SERVICE_ADDR is the address of the Auth gRPC service, which registers users and issues their tokens.
+	// It defaults to "localhost:50052" if the AUTH_SERVICE_ADDR environment variable is not set.
+	AUTH_SERVICE_ADDR = utils.GetEnv("AUTH_SERVICE_ADDR", "localhost:50052")
+
+	// CATEGORIZE_MAX_BATCH_SIZE is the largest number of emails sent to the Categorization service
+	// in a single BatchCategorizeEmails call. The service reads its MaxBatchSize from the same
+	// CATEGORIZE_MAX_BATCH_SIZE environment variable, so the two cannot disagree.
+	CATEGORIZE_MAX_BATCH_SIZE = utils.MaxBatchSize()
+
+	// CATEGORIZE_MAX_BATCH_BYTES is the largest encoded size, in bytes, of the emails sent in a single
+	// BatchCategorizeEmails call, keeping every call below gRPC's 4 MiB message limit. It defaults
+	// to 3 MiB if the CATEGORIZE_MAX_BATCH_BYTES environment variable is not set.
+	CATEGORIZE_MAX_BATCH_BYTES = utils.MaxBatchBytes()
 )
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// ReviewHandler lists the emails whose predicted category was not confident enough to file them
+// automatically, so the user can pick the right category for them.
+type ReviewHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewReviewHandler creates and returns a new instance of ReviewHandler with a default
+// gRPC connection manager, the service address, and a timeout configured.
+func NewReviewHandler() *ReviewHandler {
+	return &ReviewHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: "localhost:50051",
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// Handle handles GET /review. It accepts the mailbox and an optional limit as query parameters
+// and responds with the mailbox's emails awaiting review, newest first, each with its predicted
+// category and alternatives. Submitting feedback for an email removes it from the list.
+func (h *ReviewHandler) Handle(c *gin.Context) {
+	limit := 0
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed < 0 {
+			h.handleError(c, http.StatusBadRequest, "Invalid limit", errors.New("limit must be a non-negative integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	client := pb.NewEmailCategorizationServiceClient(conn)
+	response, err := client.ListReviewEmails(ctx, &pb.ListReviewEmailsRequest{
+		Mailbox: c.Query("mailbox"),
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		h.handleError(c, httpStatusFromGRPC(err), "Failed to list emails awaiting review", errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.Emails,
+	})
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *ReviewHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in review handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// TaxonomyHandler exposes the category taxonomy of the email categorization service, so clients can
+// render the categories as nested tabs.
+type TaxonomyHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewTaxonomyHandler creates and returns a new instance of TaxonomyHandler with a default
+// gRPC connection manager, the service address, and a timeout configured.
+func NewTaxonomyHandler() *TaxonomyHandler {
+	return &TaxonomyHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: "localhost:50051",
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// Handle handles GET /categories. It responds with the tree of categories, each with its ID, display
+// name, parent ID, and nested subcategories. For the mailbox given as a query parameter, every category
+// also carries the number of emails filed directly under it (count) and including its subcategories (total).
+func (h *TaxonomyHandler) Handle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	client := pb.NewEmailCategorizationServiceClient(conn)
+	response, err := client.GetTaxonomy(ctx, &pb.GetTaxonomyRequest{
+		Mailbox: c.Query("mailbox"),
+	})
+	if err != nil {
+		h.handleError(c, httpStatusFromGRPC(err), "Failed to load categories", errors.New(status.Convert(err).Message()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   response.Categories,
+	})
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *TaxonomyHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in taxonomy handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -1,9 +1,13 @@
 package handlers
 
+import "time"
+
 // EmailRequest represents the structure of an email being processed by the handlers.
 // It includes various metadata such as subject, sender, recipients, and headers.
+// The ID is the client's own identifier (e.g. the Gmail message ID), scoped by Mailbox.
 type EmailRequest struct {
 	ID         string            `json:"id"`
+	Mailbox    string            `json:"mailbox"`
 	Subject    string            `json:"subject"`
 	Body       string            `json:"body"`
 	Sender     string            `json:"sender"`
@@ -12,7 +16,67 @@ type EmailRequest struct {
 }
 
 // CategorizeServiceRequest represents the payload sent to a categorization service.
-// It contains a list of emails to be categorized.
+// It contains a list of emails to be categorized. Emails that were already categorized
+// with unchanged content return their stored result unless ForceRecategorize is set.
+// When UserID is set, the user's custom categories are applied to the results.
 type CategorizeServiceRequest struct {
-	Emails []EmailRequest `json:"emails"`
+	Emails            []EmailRequest `json:"emails"`
+	ForceRecategorize bool           `json:"force_recategorize"`
+	UserID            string         `json:"user_id"`
+}
+
+// CategoryRuleRequest represents a rule that assigns emails to a user's custom category.
+// An email matches the rule once the weighted score of its matching signals reaches MinScore.
+type CategoryRuleRequest struct {
+	Name             string   `json:"name"`
+	Senders          []string `json:"senders"`
+	StrongKeywords   []string `json:"strong_keywords"`
+	SubjectPatterns  []string `json:"subject_patterns"`
+	HeaderIndicators []string `json:"header_indicators"`
+	MinScore         int32    `json:"min_score"`
+}
+
+// UserCategoryRequest represents the payload for creating or updating a user's custom category.
+type UserCategoryRequest struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Rules       []CategoryRuleRequest `json:"rules"`
+}
+
+// FeedbackRequest represents a user's correction of an email's category, sent when the user
+// moves the email to a different tab in the extension. The email is identified by the same
+// ID and mailbox it was categorized with.
+type FeedbackRequest struct {
+	ID                string `json:"id" binding:"required"`
+	Mailbox           string `json:"mailbox"`
+	CorrectedCategory string `json:"corrected_category" binding:"required"`
+	UserID            string `json:"user_id"`
+}
+
+// RegisterRequest represents the payload for creating an account.
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Name     string `json:"name"`
+}
+
+// LoginRequest represents the payload for logging in with an email and password.
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshTokenRequest represents the payload for exchanging a refresh token for a new pair of
+// tokens, or for revoking it at logout.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// APIKeyRequest represents the payload for issuing an API key to a user. Scopes are any of "read",
+// "categorize", and "admin". A key without ExpiresAt never expires.
+type APIKeyRequest struct {
+	UserID    string     `json:"user_id" binding:"required"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
 }
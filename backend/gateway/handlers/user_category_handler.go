@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// UserCategoryHandler exposes REST routes for managing a user's custom categories and their rules.
+// Each route forwards to the matching CRUD method of the email categorization gRPC service.
+type UserCategoryHandler struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewUserCategoryHandler creates and returns a new instance of UserCategoryHandler with a default
+// gRPC connection manager, the service address, and a timeout configured.
+func NewUserCategoryHandler() *UserCategoryHandler {
+	return &UserCategoryHandler{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: "localhost:50051",
+		grpcTimeout: 5 * time.Second,
+	}
+}
+
+// List handles GET /users/:user_id/categories and returns all custom categories of the user.
+func (h *UserCategoryHandler) List(c *gin.Context) {
+	h.withClient(c, func(ctx context.Context, client pb.EmailCategorizationServiceClient) {
+		response, err := client.ListUserCategories(ctx, &pb.ListUserCategoriesRequest{
+			UserId: c.Param("user_id"),
+		})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to list categories", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"data":   response.Categories,
+		})
+	})
+}
+
+// Create handles POST /users/:user_id/categories and creates a new custom category for the user.
+func (h *UserCategoryHandler) Create(c *gin.Context) {
+	var requestData UserCategoryRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.withClient(c, func(ctx context.Context, client pb.EmailCategorizationServiceClient) {
+		response, err := client.CreateUserCategory(ctx, &pb.CreateUserCategoryRequest{
+			Category: h.createGRPCCategory(c.Param("user_id"), "", requestData),
+		})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to create category", err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   response.Category,
+		})
+	})
+}
+
+// Update handles PUT /users/:user_id/categories/:id and replaces the name, description,
+// and rules of one of the user's categories.
+func (h *UserCategoryHandler) Update(c *gin.Context) {
+	var requestData UserCategoryRequest
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		h.handleError(c, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+
+	h.withClient(c, func(ctx context.Context, client pb.EmailCategorizationServiceClient) {
+		response, err := client.UpdateUserCategory(ctx, &pb.UpdateUserCategoryRequest{
+			Category: h.createGRPCCategory(c.Param("user_id"), c.Param("id"), requestData),
+		})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to update category", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"data":   response.Category,
+		})
+	})
+}
+
+// Delete handles DELETE /users/:user_id/categories/:id and removes one of the user's categories.
+func (h *UserCategoryHandler) Delete(c *gin.Context) {
+	h.withClient(c, func(ctx context.Context, client pb.EmailCategorizationServiceClient) {
+		_, err := client.DeleteUserCategory(ctx, &pb.DeleteUserCategoryRequest{
+			UserId: c.Param("user_id"),
+			Id:     c.Param("id"),
+		})
+		if err != nil {
+			h.handleGRPCError(c, "Failed to delete category", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+		})
+	})
+}
+
+// withClient establishes a gRPC connection to the categorization service and calls fn with a client
+// and a context bounded by the handler's timeout. Connection failures are reported to the caller.
+func (h *UserCategoryHandler) withClient(c *gin.Context, fn func(ctx context.Context, client pb.EmailCategorizationServiceClient)) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.grpcTimeout)
+	defer cancel()
+
+	conn, err := h.grpcManager.GetConnection(ctx, h.serviceAddr)
+	if err != nil {
+		h.handleError(c, http.StatusServiceUnavailable, "Failed to connect to service", err)
+		return
+	}
+
+	fn(ctx, pb.NewEmailCategorizationServiceClient(conn))
+}
+
+// createGRPCCategory transforms a UserCategoryRequest into a gRPC UserCategory message.
+func (h *UserCategoryHandler) createGRPCCategory(userID, id string, req UserCategoryRequest) *pb.UserCategory {
+	category := &pb.UserCategory{
+		Id:          id,
+		UserId:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Rules:       make([]*pb.CategoryRule, 0, len(req.Rules)),
+	}
+	for _, rule := range req.Rules {
+		category.Rules = append(category.Rules, &pb.CategoryRule{
+			Name:             rule.Name,
+			Senders:          rule.Senders,
+			StrongKeywords:   rule.StrongKeywords,
+			SubjectPatterns:  rule.SubjectPatterns,
+			HeaderIndicators: rule.HeaderIndicators,
+			MinScore:         rule.MinScore,
+		})
+	}
+	return category
+}
+
+// handleGRPCError maps a gRPC status error returned by the categorization service
+// to the matching HTTP status code and reports it to the caller.
+func (h *UserCategoryHandler) handleGRPCError(c *gin.Context, message string, err error) {
+	h.handleError(c, httpStatusFromGRPC(err), message, errors.New(status.Convert(err).Message()))
+}
+
+// handleError logs the specified error and returns a JSON response with the provided status code
+// and a descriptive message, along with the error details.
+func (h *UserCategoryHandler) handleError(c *gin.Context, status int, message string, err error) {
+	log.Printf("Error in user category handler: %v", err)
+	c.JSON(status, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   err.Error(),
+	})
+}
+
+// httpStatusFromGRPC converts the code of a gRPC status error into the closest HTTP status code.
+func httpStatusFromGRPC(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,40 +1,108 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/joho/godotenv/autoload" // Automatically load environment variables from a .env file, if present.
 
 	utils "github.com/samiransarii/inboXpert/common/utils"
 	handlers "github.com/samiransarii/inboXpert/gateway/handlers"
+	middleware "github.com/samiransarii/inboXpert/gateway/middleware"
+	ratelimit "github.com/samiransarii/inboXpert/gateway/ratelimit"
 )
 
 // GATEWAY_PORT defines the port on which the API Gateway will listen.
 // If not provided as an environment variable, it defaults to "8080".
 var GATEWAY_PORT = utils.GetEnv("GATEWAY_PORT", "8080")
 
+// These variables configure how the API Gateway authenticates requests.
+var (
+	// AUTH_MODE selects how bearer tokens are verified: "jwks" verifies them locally against the public
+	// keys of the auth service, "remote" asks the auth service to validate every token, and "disabled"
+	// accepts anonymous requests, for local development only. It defaults to "jwks".
+	AUTH_MODE = utils.GetEnv("AUTH_MODE", "jwks")
+
+	// JWT_ISSUER and JWT_AUDIENCE are the claims every access token must carry under the "jwks" mode.
+	// They must match the configuration of the auth service.
+	JWT_ISSUER   = utils.GetEnv("JWT_ISSUER", "inboxpert-auth")
+	JWT_AUDIENCE = utils.GetEnv("JWT_AUDIENCE", "inboxpert")
+
+	// AUTH_KEYS_REFRESH_INTERVAL is how often the public keys of the auth service are fetched again
+	// under the "jwks" mode. It defaults to 5 minutes.
+	AUTH_KEYS_REFRESH_INTERVAL = utils.GetEnv("AUTH_KEYS_REFRESH_INTERVAL", "5m")
+)
+
+// These variables configure which browser origins may call the API Gateway.
+var (
+	// CORS_CONFIG_FILE is a YAML or JSON file holding the CORS policy, with overrides for some routes.
+	// Fields it leaves out of the default policy are taken from the variables below.
+	CORS_CONFIG_FILE = utils.GetEnv("CORS_CONFIG_FILE", "")
+
+	// CORS_ALLOWED_ORIGINS is a comma-separated list of the origins allowed to call the gateway, where "*"
+	// stands for any part of an origin, such as "chrome-extension://*" or "https://*.inboxpert.io".
+	// It defaults to the published Chrome extension.
+	CORS_ALLOWED_ORIGINS = utils.GetEnv("CORS_ALLOWED_ORIGINS", "chrome-extension://limgejhkljoadkclajoeijlojaanpebl")
+
+	// CORS_ALLOW_CREDENTIALS lets allowed origins send cookies and HTTP authentication with their requests.
+	// It defaults to "false", since the gateway authenticates requests by their headers.
+	CORS_ALLOW_CREDENTIALS = utils.GetEnv("CORS_ALLOW_CREDENTIALS", "false")
+
+	// CORS_MAX_AGE_SECONDS is how long browsers may cache the answer to a preflight request.
+	// It defaults to 600 seconds.
+	CORS_MAX_AGE_SECONDS = utils.GetEnvAsInt("CORS_MAX_AGE_SECONDS", 600)
+)
+
+// These variables configure how the API Gateway rate limits requests and enforces daily email quotas.
+// Requests authenticated with an API key are limited per key, other authenticated requests per user,
+// and anonymous requests, such as logins, per client IP.
+var (
+	// RATE_LIMIT_STORE selects where the limits are counted: "memory" counts them in every gateway replica
+	// on its own, "postgres" and "redis" share them between replicas, and "disabled" lets every request
+	// through. It defaults to "memory".
+	RATE_LIMIT_STORE = utils.GetEnv("RATE_LIMIT_STORE", "memory")
+
+	// RATE_LIMIT_DATABASE_URL is the PostgreSQL database of the "postgres" store. It defaults to DATABASE_URL.
+	RATE_LIMIT_DATABASE_URL = utils.GetEnv("RATE_LIMIT_DATABASE_URL", utils.GetEnv("DATABASE_URL", ""))
+
+	// RATE_LIMIT_REDIS_ADDR and RATE_LIMIT_REDIS_PASSWORD locate the Redis-compatible server of the
+	// "redis" store. The address defaults to "localhost:6379".
+	RATE_LIMIT_REDIS_ADDR     = utils.GetEnv("RATE_LIMIT_REDIS_ADDR", "localhost:6379")
+	RATE_LIMIT_REDIS_PASSWORD = utils.GetEnv("RATE_LIMIT_REDIS_PASSWORD", "")
+
+	// RATE_LIMIT_*_RPS are the sustained requests per second, and RATE_LIMIT_*_BURST the requests in a
+	// burst, allowed to every user, API key, and client IP. A rate of zero disables the limit.
+	RATE_LIMIT_USER_RPS      = utils.GetEnvAsFloat("RATE_LIMIT_USER_RPS", 5)
+	RATE_LIMIT_USER_BURST    = utils.GetEnvAsInt("RATE_LIMIT_USER_BURST", 20)
+	RATE_LIMIT_API_KEY_RPS   = utils.GetEnvAsFloat("RATE_LIMIT_API_KEY_RPS", 10)
+	RATE_LIMIT_API_KEY_BURST = utils.GetEnvAsInt("RATE_LIMIT_API_KEY_BURST", 50)
+	RATE_LIMIT_IP_RPS        = utils.GetEnvAsFloat("RATE_LIMIT_IP_RPS", 2)
+	RATE_LIMIT_IP_BURST      = utils.GetEnvAsInt("RATE_LIMIT_IP_BURST", 10)
+
+	// DAILY_EMAIL_QUOTA_* are how many emails every user, API key, and client IP may have categorized
+	// per day, starting over at midnight UTC. Zero disables the quota.
+	DAILY_EMAIL_QUOTA_USER    = utils.GetEnvAsInt("DAILY_EMAIL_QUOTA_USER", 5000)
+	DAILY_EMAIL_QUOTA_API_KEY = utils.GetEnvAsInt("DAILY_EMAIL_QUOTA_API_KEY", 20000)
+	DAILY_EMAIL_QUOTA_IP      = utils.GetEnvAsInt("DAILY_EMAIL_QUOTA_IP", 500)
+)
+
 func main() {
 	// Create a new Gin engine instance for routing HTTP requests.
 	gateway := gin.Default()
 
-	// Add a middleware to manage CORS (Cross-Origin Resource Sharing) headers,
-	// allowing requests from the specified Chrome extension.
-	gateway.Use(func(ctx *gin.Context) {
-		// Set CORS headers for requests originating from the Chrome extension.
-		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "chrome-extension://limgejhkljoadkclajoeijlojaanpebl")
-		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Authorization")
-
-		// Handle preflight OPTIONS requests by returning a 204 No Content status.
-		if ctx.Request.Method == "OPTIONS" {
-			ctx.AbortWithStatus(204)
-			return
-		}
-
-		ctx.Next()
-	})
+	// Add a middleware to manage CORS (Cross-Origin Resource Sharing) headers, allowing
+	// requests from the origins of the configured CORS policy.
+	cors, err := middleware.CORS(newCORSConfig())
+	if err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+	gateway.Use(cors)
 
 	// Specify that no specific trusted reverse proxy addresses are known.
 	// This is part of a security measure to avoid IP spoofing through proxy headers.
@@ -42,19 +110,188 @@ func main() {
 
 	// Create instances of request handlers for different services.
 	categorizationHandler := handlers.NewCategorizationHandler()
+	userCategoryHandler := handlers.NewUserCategoryHandler()
+	feedbackHandler := handlers.NewFeedbackHandler()
+	reviewHandler := handlers.NewReviewHandler()
+	taxonomyHandler := handlers.NewTaxonomyHandler()
+	authHandler := handlers.NewAuthHandler()
+	apiKeyHandler := handlers.NewAPIKeyHandler()
+
+	// Rate limit every route, and count categorized emails against the daily quotas.
+	limit := func(c *gin.Context) { c.Next() }
+	if limiter := newRateLimiter(); limiter != nil {
+		limit = limiter.Middleware()
+		categorizationHandler.SetQuota(limiter)
+	}
+
+	// /auth: Register and log users in, and renew or revoke the tokens they were issued.
+	// These routes, along with the public keys tokens are signed with, need no access token.
+	// They are rate limited per client IP, which also slows down guessing passwords.
+	gateway.POST("/auth/register", limit, authHandler.Register)
+	gateway.POST("/auth/login", limit, authHandler.Login)
+	gateway.POST("/auth/refresh", limit, authHandler.Refresh)
+	gateway.POST("/auth/logout", limit, authHandler.Logout)
+	gateway.GET("/.well-known/jwks.json", limit, authHandler.PublicKeys)
+
+	// Every other route requires a valid access token or API key, acts on behalf of the user it
+	// authenticates, and requires the scope the route is registered with.
+	api := gateway.Group("")
+	requireScope := middleware.RequireScope
+	if AUTH_MODE == "disabled" {
+		log.Println("Authentication is disabled: accepting anonymous requests")
+		requireScope = func(string) gin.HandlerFunc { return func(c *gin.Context) { c.Next() } }
+	} else {
+		api.Use(middleware.Auth(newAuthenticator(), middleware.NewAPIKeyAuthenticator(handlers.AUTH_SERVICE_ADDR, 5*time.Second)))
+	}
+	api.Use(limit)
+	read := requireScope(middleware.ScopeRead)
+	categorize := requireScope(middleware.ScopeCategorize)
 
 	// Define the routes exposed by the API Gateway.
 	// POST /categorize: Routes incoming categorization requests to the CategorizationHandler.
-	gateway.POST("/categorize", categorizationHandler.Handle)
+	api.POST("/categorize", categorize, categorizationHandler.Handle)
+
+	// POST /feedback: Records a user's correction of a predicted category.
+	api.POST("/feedback", categorize, feedbackHandler.Handle)
+
+	// GET /review: Lists the emails of a mailbox whose prediction was too unconfident to file them.
+	api.GET("/review", read, reviewHandler.Handle)
+
+	// GET /categories: Returns the category taxonomy as a tree, with per-category email counts.
+	api.GET("/categories", read, taxonomyHandler.Handle)
+
+	// /users/:user_id/categories: Manage a user's custom categories and the rules that fill them.
+	api.GET("/users/:user_id/categories", read, userCategoryHandler.List)
+	api.POST("/users/:user_id/categories", categorize, userCategoryHandler.Create)
+	api.PUT("/users/:user_id/categories/:id", categorize, userCategoryHandler.Update)
+	api.DELETE("/users/:user_id/categories/:id", categorize, userCategoryHandler.Delete)
+
+	// /admin/api-keys: Issue, list, and revoke the API keys of scripts and server-side integrations.
+	// These routes always require the admin scope, and are unreachable with authentication disabled.
+	admin := api.Group("/admin", middleware.RequireScope(middleware.ScopeAdmin))
+	admin.GET("/api-keys", apiKeyHandler.List)
+	admin.POST("/api-keys", apiKeyHandler.Create)
+	admin.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
 
 	// Future routes for spam filtering and priority filtering could be added here:
 	// gateway.GET("/spam-filter", spamFilterHandler)
 	// gateway.GET("/priority", priorityFilterHandler)
 
 	// Start the API Gateway server on the configured port, listening for incoming requests.
-	err := gateway.Run("localhost:" + GATEWAY_PORT)
+	err = gateway.Run("localhost:" + GATEWAY_PORT)
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 	fmt.Printf("API Gateway is running on port: %s\n", GATEWAY_PORT)
 }
+
+// newAuthenticator creates the authenticator verifying bearer tokens under the configured AUTH_MODE.
+func newAuthenticator() middleware.Authenticator {
+	switch AUTH_MODE {
+	case "jwks":
+		refreshInterval, err := time.ParseDuration(AUTH_KEYS_REFRESH_INTERVAL)
+		if err != nil || refreshInterval <= 0 {
+			log.Printf("Invalid AUTH_KEYS_REFRESH_INTERVAL %q, using 5m", AUTH_KEYS_REFRESH_INTERVAL)
+			refreshInterval = 5 * time.Minute
+		}
+		return middleware.NewJWKSAuthenticator(middleware.JWKSConfig{
+			ServiceAddr:     handlers.AUTH_SERVICE_ADDR,
+			Issuer:          JWT_ISSUER,
+			Audience:        JWT_AUDIENCE,
+			RefreshInterval: refreshInterval,
+			Timeout:         5 * time.Second,
+		})
+	case "remote":
+		return middleware.NewRemoteAuthenticator(handlers.AUTH_SERVICE_ADDR, 5*time.Second)
+	default:
+		log.Fatalf("Unknown AUTH_MODE %q: expected jwks, remote, or disabled", AUTH_MODE)
+		return nil
+	}
+}
+
+// newCORSConfig loads the CORS policy from CORS_CONFIG_FILE, if set, filling in what its default policy
+// leaves out from the CORS_* variables and the methods and headers the gateway's routes use.
+func newCORSConfig() middleware.CORSConfig {
+	var config middleware.CORSConfig
+	if CORS_CONFIG_FILE != "" {
+		loaded, err := middleware.LoadCORSFile(CORS_CONFIG_FILE)
+		if err != nil {
+			log.Fatalf("Failed to load CORS configuration: %v", err)
+		}
+		config = *loaded
+	}
+
+	if config.AllowedOrigins == nil {
+		config.AllowedOrigins = strings.Split(CORS_ALLOWED_ORIGINS, ",")
+	}
+	if config.AllowedMethods == nil {
+		config.AllowedMethods = []string{"POST", "GET", "OPTIONS", "PUT", "DELETE"}
+	}
+	if config.AllowedHeaders == nil {
+		config.AllowedHeaders = []string{"Accept", "Content-Type", "Content-Length", "Authorization", middleware.APIKeyHeader}
+	}
+	if config.ExposedHeaders == nil {
+		// Let clients read how long to back off when they are rate limited.
+		config.ExposedHeaders = []string{"Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Quota-Limit", "X-Quota-Remaining", "X-Quota-Reset"}
+	}
+	if config.AllowCredentials == nil {
+		allowCredentials, err := strconv.ParseBool(CORS_ALLOW_CREDENTIALS)
+		if err != nil {
+			log.Fatalf("Invalid CORS_ALLOW_CREDENTIALS %q: %v", CORS_ALLOW_CREDENTIALS, err)
+		}
+		config.AllowCredentials = &allowCredentials
+	}
+	if config.MaxAgeSeconds == nil {
+		config.MaxAgeSeconds = &CORS_MAX_AGE_SECONDS
+	}
+	return config
+}
+
+// newRateLimiter creates the rate limiter counting limits in the configured RATE_LIMIT_STORE,
+// or returns nil if rate limiting is disabled.
+func newRateLimiter() *ratelimit.Limiter {
+	config := ratelimit.Config{
+		User:              ratelimit.Limit{Rate: RATE_LIMIT_USER_RPS, Burst: RATE_LIMIT_USER_BURST},
+		APIKey:            ratelimit.Limit{Rate: RATE_LIMIT_API_KEY_RPS, Burst: RATE_LIMIT_API_KEY_BURST},
+		IP:                ratelimit.Limit{Rate: RATE_LIMIT_IP_RPS, Burst: RATE_LIMIT_IP_BURST},
+		UserDailyEmails:   DAILY_EMAIL_QUOTA_USER,
+		APIKeyDailyEmails: DAILY_EMAIL_QUOTA_API_KEY,
+		IPDailyEmails:     DAILY_EMAIL_QUOTA_IP,
+	}
+
+	var store ratelimit.Store
+	switch RATE_LIMIT_STORE {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "postgres":
+		if RATE_LIMIT_DATABASE_URL == "" {
+			log.Fatal("RATE_LIMIT_DATABASE_URL or DATABASE_URL must be set for the postgres rate limit store")
+		}
+		pool, err := pgxpool.New(context.Background(), RATE_LIMIT_DATABASE_URL)
+		if err != nil {
+			log.Fatalf("Unable to connect to rate limit database: %v", err)
+		}
+		postgresStore := ratelimit.NewPostgresStore(pool)
+		if err := postgresStore.EnsureSchema(context.Background()); err != nil {
+			log.Fatalf("Failed to prepare rate limit database: %v", err)
+		}
+		// Buckets idle for longer than the slowest limit takes to refill are full, and can be dropped.
+		idle := max(config.User.RefillTime(), config.APIKey.RefillTime(), config.IP.RefillTime()) + time.Minute
+		postgresStore.StartCleanup(10*time.Minute, idle)
+		store = postgresStore
+	case "redis":
+		store = ratelimit.NewRedisStore(ratelimit.RedisConfig{
+			Addr:     RATE_LIMIT_REDIS_ADDR,
+			Password: RATE_LIMIT_REDIS_PASSWORD,
+			Timeout:  time.Second,
+		})
+	case "disabled":
+		log.Println("Rate limiting is disabled")
+		return nil
+	default:
+		log.Fatalf("Unknown RATE_LIMIT_STORE %q: expected memory, postgres, redis, or disabled", RATE_LIMIT_STORE)
+	}
+
+	log.Printf("Rate limiting requests with the %s store", RATE_LIMIT_STORE)
+	return ratelimit.NewLimiter(store, config)
+}
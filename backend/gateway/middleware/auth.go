@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	authpb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// Keys under which the authenticated identity is stored in the Gin context.
+const (
+	// UserIDKey holds the authenticated user's ID.
+	UserIDKey = "user_id"
+
+	// IdentityKey holds the authenticated *Identity.
+	IdentityKey = "identity"
+)
+
+// APIKeyHeader is the header carrying an API key. API keys are also accepted as bearer tokens.
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyPrefix starts every API key, telling API keys sent as bearer tokens apart from access tokens.
+const apiKeyPrefix = "ixp_"
+
+// Scopes of the requests a credential may make. Access tokens of users who logged in carry
+// ScopeRead and ScopeCategorize, while API keys carry the scopes they were issued with.
+const (
+	// ScopeRead allows reading stored results: emails awaiting review, category counts, and custom categories.
+	ScopeRead = "read"
+
+	// ScopeCategorize allows categorizing emails, submitting feedback, and managing custom categories.
+	ScopeCategorize = "categorize"
+
+	// ScopeAdmin allows every request, including managing the API keys of every user.
+	ScopeAdmin = "admin"
+)
+
+// ErrUnauthenticated is returned by authenticators for missing, malformed, expired, or forged credentials.
+// Other errors mean the credential could not be checked, such as when the auth service is unreachable.
+var ErrUnauthenticated = errors.New("invalid or expired credentials")
+
+// Identity is the user a credential authenticates. KeyID is the ID of the API key the request
+// was authenticated with, or empty for access tokens.
+type Identity struct {
+	UserID    string
+	Email     string
+	KeyID     string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// HasScope reports whether the identity may make requests of the given scope. Admins may make any request.
+func (i *Identity) HasScope(scope string) bool {
+	for _, granted := range i.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator verifies credentials: access tokens or API keys.
+type Authenticator interface {
+	// Authenticate returns the identity a credential authenticates. It returns an error
+	// wrapping ErrUnauthenticated for credentials that are not valid.
+	Authenticate(ctx context.Context, credential string) (*Identity, error)
+}
+
+// Auth returns a middleware requiring every request to carry a valid credential: an access token as a
+// bearer token in its Authorization header, verified by tokens, or an API key in the X-API-Key header or
+// as a bearer token, verified by apiKeys. Requests without one are rejected with 401 Unauthorized, and
+// requests whose credential cannot be checked with 503 Service Unavailable. The authenticated identity is
+// stored in the Gin context under IdentityKey and the user's ID under UserIDKey, and the user's ID is added
+// to the request context as gRPC metadata, so every call the handlers make to backend services with the
+// request context is made on the user's behalf.
+func Auth(tokens, apiKeys Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticator := tokens
+		credential, ok := bearerToken(c.GetHeader("Authorization"))
+		if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+			credential, ok = key, true
+		}
+		if !ok {
+			abortUnauthenticated(c, errors.New("missing bearer token or API key"))
+			return
+		}
+		if strings.HasPrefix(credential, apiKeyPrefix) {
+			authenticator = apiKeys
+		}
+
+		identity, err := authenticator.Authenticate(c.Request.Context(), credential)
+		if errors.Is(err, ErrUnauthenticated) {
+			abortUnauthenticated(c, err)
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to authenticate request: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "error",
+				"message": "Failed to authenticate request",
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		c.Set(IdentityKey, identity)
+		c.Set(UserIDKey, identity.UserID)
+		c.Request = c.Request.WithContext(utils.WithUserID(c.Request.Context(), identity.UserID))
+		c.Next()
+	}
+}
+
+// UserID returns the ID of the user authenticated by the Auth middleware, or an empty string
+// if the request was not authenticated.
+func UserID(c *gin.Context) string {
+	return c.GetString(UserIDKey)
+}
+
+// RequireScope returns a middleware rejecting requests whose identity lacks the given scope with
+// 403 Forbidden. It must run after Auth, and rejects unauthenticated requests with 401 Unauthorized.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := CurrentIdentity(c)
+		if identity == nil {
+			abortUnauthenticated(c, errors.New("missing bearer token or API key"))
+			return
+		}
+		if !identity.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Insufficient scope",
+				"error":   fmt.Sprintf("this request requires the %s scope", scope),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentIdentity returns the identity authenticated by the Auth middleware, or nil if the request
+// was not authenticated.
+func CurrentIdentity(c *gin.Context) *Identity {
+	value, exists := c.Get(IdentityKey)
+	if !exists {
+		return nil
+	}
+	identity, _ := value.(*Identity)
+	return identity
+}
+
+// bearerToken extracts the token of an Authorization header using the Bearer scheme.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// abortUnauthenticated rejects a request with 401 Unauthorized, asking for a bearer token.
+func abortUnauthenticated(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="inboxpert"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"status":  "error",
+		"message": "Authentication required",
+		"error":   err.Error(),
+	})
+}
+
+// RemoteAuthenticator verifies access tokens by calling the ValidateToken RPC of the auth service,
+// so revocations and key rotations take effect immediately at the cost of a call per request.
+type RemoteAuthenticator struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewRemoteAuthenticator creates a RemoteAuthenticator calling the auth service at serviceAddr.
+func NewRemoteAuthenticator(serviceAddr string, grpcTimeout time.Duration) *RemoteAuthenticator {
+	return &RemoteAuthenticator{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: serviceAddr,
+		grpcTimeout: grpcTimeout,
+	}
+}
+
+// Authenticate asks the auth service for the identity an access token authenticates.
+func (a *RemoteAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.grpcTimeout)
+	defer cancel()
+
+	conn, err := a.grpcManager.GetConnection(ctx, a.serviceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to auth service: %w", err)
+	}
+
+	response, err := authpb.NewAuthServiceClient(conn).ValidateToken(ctx, &authpb.ValidateTokenRequest{AccessToken: token})
+	if status.Code(err) == codes.Unauthenticated {
+		return nil, fmt.Errorf("%w: %s", ErrUnauthenticated, status.Convert(err).Message())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	return &Identity{
+		UserID:    response.UserId,
+		Email:     response.Email,
+		Scopes:    []string{ScopeRead, ScopeCategorize},
+		ExpiresAt: time.Unix(response.ExpiresAt, 0),
+	}, nil
+}
+
+// APIKeyAuthenticator verifies API keys by calling the ValidateApiKey RPC of the auth service, which
+// also records when every key was last used. Keys cannot be verified locally, since only the auth
+// service knows their hashes and whether they were revoked.
+type APIKeyAuthenticator struct {
+	grpcManager *utils.GRPCClientManager
+	serviceAddr string
+	grpcTimeout time.Duration
+}
+
+// NewAPIKeyAuthenticator creates an APIKeyAuthenticator calling the auth service at serviceAddr.
+func NewAPIKeyAuthenticator(serviceAddr string, grpcTimeout time.Duration) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		grpcManager: utils.GetGRPCClientManager(),
+		serviceAddr: serviceAddr,
+		grpcTimeout: grpcTimeout,
+	}
+}
+
+// Authenticate asks the auth service for the user and scopes of an API key.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*Identity, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.grpcTimeout)
+	defer cancel()
+
+	conn, err := a.grpcManager.GetConnection(ctx, a.serviceAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to auth service: %w", err)
+	}
+
+	response, err := authpb.NewAuthServiceClient(conn).ValidateApiKey(ctx, &authpb.ValidateApiKeyRequest{Key: key})
+	if status.Code(err) == codes.Unauthenticated {
+		return nil, fmt.Errorf("%w: %s", ErrUnauthenticated, status.Convert(err).Message())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate API key: %w", err)
+	}
+
+	identity := &Identity{
+		UserID: response.UserId,
+		KeyID:  response.KeyId,
+		Scopes: response.Scopes,
+	}
+	if response.ExpiresAt != 0 {
+		identity.ExpiresAt = time.Unix(response.ExpiresAt, 0)
+	}
+	return identity, nil
+}
@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+)
+
+// fakeAuthenticator authenticates the credentials it knows, records every credential it is asked
+// to check, and fails with err if set.
+type fakeAuthenticator struct {
+	identities map[string]*Identity
+	err        error
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (a *fakeAuthenticator) Authenticate(ctx context.Context, credential string) (*Identity, error) {
+	a.mu.Lock()
+	a.calls = append(a.calls, credential)
+	a.mu.Unlock()
+
+	if a.err != nil {
+		return nil, a.err
+	}
+	identity, ok := a.identities[credential]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown credential", ErrUnauthenticated)
+	}
+	return identity, nil
+}
+
+func TestAuth(t *testing.T) {
+	user := &Identity{UserID: "user-1", Scopes: []string{ScopeRead, ScopeCategorize}}
+	keyOwner := &Identity{UserID: "user-2", KeyID: "key-1", Scopes: []string{ScopeRead}}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		// tokensErr and apiKeysErr make the authenticators fail, if set.
+		tokensErr  error
+		apiKeysErr error
+
+		wantStatus      int
+		wantUserID      string
+		wantTokenCalls  []string
+		wantAPIKeyCalls []string
+	}{
+		{
+			name:       "missing credentials",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "other authorization scheme",
+			headers:    map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "empty bearer token",
+			headers:    map[string]string{"Authorization": "Bearer  "},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "access token",
+			headers:        map[string]string{"Authorization": "Bearer access-token"},
+			wantStatus:     http.StatusOK,
+			wantUserID:     "user-1",
+			wantTokenCalls: []string{"access-token"},
+		},
+		{
+			name:           "bearer scheme in any case",
+			headers:        map[string]string{"Authorization": "bearer access-token"},
+			wantStatus:     http.StatusOK,
+			wantUserID:     "user-1",
+			wantTokenCalls: []string{"access-token"},
+		},
+		{
+			name:           "invalid access token",
+			headers:        map[string]string{"Authorization": "Bearer forged-token"},
+			wantStatus:     http.StatusUnauthorized,
+			wantTokenCalls: []string{"forged-token"},
+		},
+		{
+			name:            "API key header",
+			headers:         map[string]string{APIKeyHeader: "ixp_secret"},
+			wantStatus:      http.StatusOK,
+			wantUserID:      "user-2",
+			wantAPIKeyCalls: []string{"ixp_secret"},
+		},
+		{
+			name:            "API key as bearer token",
+			headers:         map[string]string{"Authorization": "Bearer ixp_secret"},
+			wantStatus:      http.StatusOK,
+			wantUserID:      "user-2",
+			wantAPIKeyCalls: []string{"ixp_secret"},
+		},
+		{
+			name:            "API key header takes precedence over the Authorization header",
+			headers:         map[string]string{APIKeyHeader: "ixp_secret", "Authorization": "Bearer access-token"},
+			wantStatus:      http.StatusOK,
+			wantUserID:      "user-2",
+			wantAPIKeyCalls: []string{"ixp_secret"},
+		},
+		{
+			name:            "invalid API key header does not fall back to the Authorization header",
+			headers:         map[string]string{APIKeyHeader: "ixp_revoked", "Authorization": "Bearer access-token"},
+			wantStatus:      http.StatusUnauthorized,
+			wantAPIKeyCalls: []string{"ixp_revoked"},
+		},
+		{
+			name:           "auth service unavailable for access tokens",
+			headers:        map[string]string{"Authorization": "Bearer access-token"},
+			tokensErr:      errors.New("failed to connect to auth service"),
+			wantStatus:     http.StatusServiceUnavailable,
+			wantTokenCalls: []string{"access-token"},
+		},
+		{
+			name:            "auth service unavailable for API keys",
+			headers:         map[string]string{APIKeyHeader: "ixp_secret"},
+			apiKeysErr:      errors.New("failed to validate API key"),
+			wantStatus:      http.StatusServiceUnavailable,
+			wantAPIKeyCalls: []string{"ixp_secret"},
+		},
+		{
+			name:           "rejected by the auth service",
+			headers:        map[string]string{"Authorization": "Bearer access-token"},
+			tokensErr:      fmt.Errorf("%w: token revoked", ErrUnauthenticated),
+			wantStatus:     http.StatusUnauthorized,
+			wantTokenCalls: []string{"access-token"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokens := &fakeAuthenticator{identities: map[string]*Identity{"access-token": user}, err: test.tokensErr}
+			apiKeys := &fakeAuthenticator{identities: map[string]*Identity{"ixp_secret": keyOwner}, err: test.apiKeysErr}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(Auth(tokens, apiKeys))
+			var userID, metadataUserID string
+			var identity *Identity
+			router.GET("/emails", func(c *gin.Context) {
+				userID = UserID(c)
+				identity = CurrentIdentity(c)
+				if md, ok := metadata.FromOutgoingContext(c.Request.Context()); ok {
+					metadataUserID = strings.Join(md.Get(utils.UserIDMetadataKey), ",")
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/emails", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, test.wantStatus)
+			}
+			if test.wantStatus == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 response without a WWW-Authenticate header")
+			}
+			if userID != test.wantUserID || metadataUserID != test.wantUserID {
+				t.Errorf("user ID = %q with %q in the gRPC metadata, want %q", userID, metadataUserID, test.wantUserID)
+			}
+			if test.wantUserID != "" && (identity == nil || identity.UserID != test.wantUserID) {
+				t.Errorf("identity = %+v, want that of %s", identity, test.wantUserID)
+			}
+			if !slices.Equal(tokens.calls, test.wantTokenCalls) {
+				t.Errorf("access token authenticator called with %v, want %v", tokens.calls, test.wantTokenCalls)
+			}
+			if !slices.Equal(apiKeys.calls, test.wantAPIKeyCalls) {
+				t.Errorf("API key authenticator called with %v, want %v", apiKeys.calls, test.wantAPIKeyCalls)
+			}
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   *Identity
+		scope      string
+		wantStatus int
+	}{
+		{name: "granted scope", identity: &Identity{Scopes: []string{ScopeRead}}, scope: ScopeRead, wantStatus: http.StatusOK},
+		{name: "missing scope", identity: &Identity{Scopes: []string{ScopeRead}}, scope: ScopeCategorize, wantStatus: http.StatusForbidden},
+		{name: "no scopes", identity: &Identity{}, scope: ScopeRead, wantStatus: http.StatusForbidden},
+		{name: "admin has every scope", identity: &Identity{Scopes: []string{ScopeAdmin}}, scope: ScopeCategorize, wantStatus: http.StatusOK},
+		{name: "admin scope itself", identity: &Identity{Scopes: []string{ScopeRead, ScopeCategorize}}, scope: ScopeAdmin, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", scope: ScopeRead, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if test.identity != nil {
+					c.Set(IdentityKey, test.identity)
+				}
+			})
+			router.GET("/emails", RequireScope(test.scope), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/emails", nil))
+			if recorder.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, test.wantStatus)
+			}
+		})
+	}
+}
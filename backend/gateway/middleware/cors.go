@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// CORSPolicy describes which origins may call the gateway from a browser, and how. Origins are either
+// exact, such as "https://app.inboxpert.io", or patterns where "*" stands for a non-empty part of the
+// origin without "/" or ":", such as "chrome-extension://*" for every Chrome extension or
+// "https://*.inboxpert.io" for every subdomain. A lone "*" allows every origin.
+type CORSPolicy struct {
+	AllowedOrigins   []string `json:"allowed_origins" yaml:"allowed_origins"`
+	AllowedMethods   []string `json:"allowed_methods" yaml:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers" yaml:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers" yaml:"exposed_headers"`
+	AllowCredentials *bool    `json:"allow_credentials" yaml:"allow_credentials"`
+
+	// MaxAgeSeconds is how long browsers may cache the answer to a preflight request.
+	MaxAgeSeconds *int `json:"max_age_seconds" yaml:"max_age_seconds"`
+}
+
+// CORSRoute overrides the default policy for the routes under Path. Fields left out of the
+// override are inherited from the default policy.
+type CORSRoute struct {
+	Path       string `json:"path" yaml:"path"`
+	CORSPolicy `yaml:",inline"`
+}
+
+// CORSConfig is the CORS policy of the gateway: a default policy, and overrides for some routes.
+// A request is served by the override with the longest path prefixing its path, or else by the default.
+type CORSConfig struct {
+	CORSPolicy `yaml:",inline"`
+	Routes     []CORSRoute `json:"routes" yaml:"routes"`
+}
+
+// LoadCORSFile reads a CORS configuration from a YAML or JSON file, chosen by the file extension.
+func LoadCORSFile(path string) (*CORSConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CORS file: %w", err)
+	}
+
+	var config CORSConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("unsupported CORS file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CORS file: %w", err)
+	}
+	return &config, nil
+}
+
+// corsPolicy is a CORSPolicy ready to answer requests: its origins compiled and its headers joined.
+type corsPolicy struct {
+	anyOrigin        bool
+	exactOrigins     map[string]bool
+	originPatterns   []*regexp.Regexp
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// corsRoute is the compiled policy of the routes under path.
+type corsRoute struct {
+	path   string
+	policy *corsPolicy
+}
+
+// CORS returns a middleware answering preflight requests and adding the CORS headers to the responses
+// of requests from allowed origins, following the policy of the request's route. Allowed origins are
+// echoed back rather than answered with "*" whenever credentials are allowed, and every response
+// carries "Vary: Origin", so caches do not serve the headers of one origin to another. It must be
+// registered on the engine, so preflight requests are answered for every route. It returns an error
+// for invalid configurations, such as allowing credentials for every origin.
+func CORS(config CORSConfig) (gin.HandlerFunc, error) {
+	defaults, err := compileCORSPolicy(config.CORSPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]corsRoute, 0, len(config.Routes))
+	for _, route := range config.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return nil, fmt.Errorf("cors: route path %q must start with /", route.Path)
+		}
+		policy, err := compileCORSPolicy(mergeCORSPolicy(config.CORSPolicy, route.CORSPolicy))
+		if err != nil {
+			return nil, fmt.Errorf("cors: route %s: %w", route.Path, err)
+		}
+		routes = append(routes, corsRoute{path: strings.TrimSuffix(route.Path, "/"), policy: policy})
+	}
+	// The longest matching path wins, so the routes are tried from the longest down.
+	sort.SliceStable(routes, func(i, j int) bool { return len(routes[i].path) > len(routes[j].path) })
+
+	return func(c *gin.Context) {
+		policy := defaults
+		for _, route := range routes {
+			if pathUnder(c.Request.URL.Path, route.path) {
+				policy = route.policy
+				break
+			}
+		}
+		policy.handle(c)
+	}, nil
+}
+
+// handle applies the policy to a request.
+func (p *corsPolicy) handle(c *gin.Context) {
+	header := c.Writer.Header()
+	header.Add("Vary", "Origin")
+
+	origin := c.GetHeader("Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+	if preflight {
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	if origin == "" {
+		// Not a cross-origin request.
+		c.Next()
+		return
+	}
+	if !p.allows(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		// Let the request through without CORS headers, so the browser hides the response.
+		c.Next()
+		return
+	}
+
+	if p.anyOrigin && !p.allowCredentials {
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if preflight {
+		header.Set("Access-Control-Allow-Methods", p.allowedMethods)
+		header.Set("Access-Control-Allow-Headers", p.allowedHeaders)
+		if p.maxAge != "" {
+			header.Set("Access-Control-Max-Age", p.maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	}
+	if p.exposedHeaders != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposedHeaders)
+	}
+	c.Next()
+}
+
+// allows reports whether the policy allows an origin.
+func (p *corsPolicy) allows(origin string) bool {
+	if p.anyOrigin || p.exactOrigins[origin] {
+		return true
+	}
+	for _, pattern := range p.originPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileCORSPolicy validates a policy and compiles its origin patterns.
+func compileCORSPolicy(policy CORSPolicy) (*corsPolicy, error) {
+	compiled := &corsPolicy{
+		exactOrigins:     make(map[string]bool),
+		allowedMethods:   strings.Join(policy.AllowedMethods, ", "),
+		allowedHeaders:   strings.Join(policy.AllowedHeaders, ", "),
+		exposedHeaders:   strings.Join(policy.ExposedHeaders, ", "),
+		allowCredentials: policy.AllowCredentials != nil && *policy.AllowCredentials,
+	}
+	if policy.MaxAgeSeconds != nil && *policy.MaxAgeSeconds > 0 {
+		compiled.maxAge = strconv.Itoa(*policy.MaxAgeSeconds)
+	}
+
+	for _, origin := range policy.AllowedOrigins {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			compiled.anyOrigin = true
+		case !strings.Contains(origin, "://"):
+			return nil, fmt.Errorf("cors: origin %q must include a scheme", origin)
+		case strings.Contains(origin, "*"):
+			parts := strings.Split(origin, "*")
+			for i, part := range parts {
+				parts[i] = regexp.QuoteMeta(part)
+			}
+			compiled.originPatterns = append(compiled.originPatterns, regexp.MustCompile("^"+strings.Join(parts, "[^/:]+")+"$"))
+		default:
+			compiled.exactOrigins[origin] = true
+		}
+	}
+
+	if compiled.anyOrigin && compiled.allowCredentials {
+		// Echoing every origin with credentials would let any website act as a logged-in user.
+		return nil, errors.New("cors: credentials cannot be allowed for every origin")
+	}
+	return compiled, nil
+}
+
+// mergeCORSPolicy returns the policy of a route: its override, with the fields it leaves out
+// taken from the default policy.
+func mergeCORSPolicy(defaults, override CORSPolicy) CORSPolicy {
+	if override.AllowedOrigins == nil {
+		override.AllowedOrigins = defaults.AllowedOrigins
+	}
+	if override.AllowedMethods == nil {
+		override.AllowedMethods = defaults.AllowedMethods
+	}
+	if override.AllowedHeaders == nil {
+		override.AllowedHeaders = defaults.AllowedHeaders
+	}
+	if override.ExposedHeaders == nil {
+		override.ExposedHeaders = defaults.ExposedHeaders
+	}
+	if override.AllowCredentials == nil {
+		override.AllowCredentials = defaults.AllowCredentials
+	}
+	if override.MaxAgeSeconds == nil {
+		override.MaxAgeSeconds = defaults.MaxAgeSeconds
+	}
+	return override
+}
+
+// pathUnder reports whether path is prefix itself or lies under it.
+func pathUnder(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/") || prefix == ""
+}
@@ -0,0 +1,279 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsRequest is a request sent through the CORS middleware, and the response headers it expects.
+type corsRequest struct {
+	name      string
+	method    string
+	path      string
+	origin    string
+	preflight bool
+
+	wantStatus      int
+	wantOrigin      string // Access-Control-Allow-Origin, empty if the origin is not allowed
+	wantCredentials bool
+}
+
+// serveCORS sends a request through a router protected by the CORS middleware of config.
+func serveCORS(t *testing.T, config CORSConfig, request corsRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	handler, err := CORS(config)
+	if err != nil {
+		t.Fatalf("CORS() error = %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler)
+	router.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	method := request.method
+	if method == "" {
+		method = http.MethodGet
+	}
+	path := request.path
+	if path == "" {
+		path = "/api/v1/emails"
+	}
+	req := httptest.NewRequest(method, path, nil)
+	if request.origin != "" {
+		req.Header.Set("Origin", request.origin)
+	}
+	if request.preflight {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// checkCORS checks the response to a request against what it expects.
+func checkCORS(t *testing.T, recorder *httptest.ResponseRecorder, request corsRequest) {
+	t.Helper()
+	wantStatus := request.wantStatus
+	if wantStatus == 0 {
+		wantStatus = http.StatusOK
+	}
+	if recorder.Code != wantStatus {
+		t.Errorf("status = %d, want %d", recorder.Code, wantStatus)
+	}
+
+	header := recorder.Header()
+	if got := header.Get("Access-Control-Allow-Origin"); got != request.wantOrigin {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, request.wantOrigin)
+	}
+	if got := header.Get("Access-Control-Allow-Credentials") == "true"; got != request.wantCredentials {
+		t.Errorf("credentials allowed = %t, want %t", got, request.wantCredentials)
+	}
+	if !slices.Contains(header.Values("Vary"), "Origin") {
+		t.Errorf("Vary = %v, want it to include Origin", header.Values("Vary"))
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	config := CORSConfig{CORSPolicy: CORSPolicy{
+		AllowedOrigins: []string{"https://app.inboxpert.io/", "https://*.inboxpert.dev", "chrome-extension://*"},
+	}}
+
+	tests := []corsRequest{
+		{name: "exact origin", origin: "https://app.inboxpert.io", wantOrigin: "https://app.inboxpert.io"},
+		{name: "exact origin with another scheme", origin: "http://app.inboxpert.io"},
+		{name: "subdomain pattern", origin: "https://preview-42.inboxpert.dev", wantOrigin: "https://preview-42.inboxpert.dev"},
+		{name: "nested subdomain pattern", origin: "https://a.b.inboxpert.dev", wantOrigin: "https://a.b.inboxpert.dev"},
+		{name: "pattern requires a subdomain", origin: "https://inboxpert.dev"},
+		{name: "pattern does not match a port", origin: "https://evil.com:443.inboxpert.dev"},
+		{name: "pattern does not match a suffix", origin: "https://app.inboxpert.dev.evil.com"},
+		{name: "pattern matches a scheme", origin: "chrome-extension://abcdefghijklmnop", wantOrigin: "chrome-extension://abcdefghijklmnop"},
+		{name: "unknown origin", origin: "https://evil.com"},
+		{name: "same-origin request", origin: ""},
+		{name: "preflight of an allowed origin", method: http.MethodOptions, origin: "https://app.inboxpert.io", preflight: true, wantStatus: http.StatusNoContent, wantOrigin: "https://app.inboxpert.io"},
+		{name: "preflight of an unknown origin", method: http.MethodOptions, origin: "https://evil.com", preflight: true, wantStatus: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkCORS(t, serveCORS(t, config, test), test)
+		})
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	allow := true
+	tests := []struct {
+		name    string
+		config  CORSConfig
+		request corsRequest
+		wantErr bool
+	}{
+		{
+			name:    "answers with a wildcard",
+			config:  CORSConfig{CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}}},
+			request: corsRequest{origin: "https://anything.example.com", wantOrigin: "*"},
+		},
+		{
+			name:    "rejects credentials for every origin",
+			config:  CORSConfig{CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: &allow}},
+			wantErr: true,
+		},
+		{
+			name: "rejects credentials for every origin in a route",
+			config: CORSConfig{
+				CORSPolicy: CORSPolicy{AllowedOrigins: []string{"https://app.inboxpert.io"}, AllowCredentials: &allow},
+				Routes:     []CORSRoute{{Path: "/api/v1/public", CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a route allowing credentials for the default wildcard",
+			config: CORSConfig{
+				CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}},
+				Routes:     []CORSRoute{{Path: "/api/v1/emails", CORSPolicy: CORSPolicy{AllowCredentials: &allow}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rejects origins without a scheme",
+			config:  CORSConfig{CORSPolicy: CORSPolicy{AllowedOrigins: []string{"app.inboxpert.io"}}},
+			wantErr: true,
+		},
+		{
+			name:    "rejects relative route paths",
+			config:  CORSConfig{Routes: []CORSRoute{{Path: "api/v1/public"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantErr {
+				if _, err := CORS(test.config); err == nil {
+					t.Fatal("CORS() error = nil, want an error")
+				}
+				return
+			}
+			checkCORS(t, serveCORS(t, test.config, test.request), test.request)
+		})
+	}
+}
+
+func TestCORSRoutes(t *testing.T) {
+	allow := true
+	maxAge := 600
+	config := CORSConfig{
+		CORSPolicy: CORSPolicy{
+			AllowedOrigins: []string{"https://app.inboxpert.io"},
+			AllowedMethods: []string{"GET", "POST"},
+			AllowedHeaders: []string{"Authorization", "Content-Type"},
+			MaxAgeSeconds:  &maxAge,
+		},
+		Routes: []CORSRoute{
+			{Path: "/api", CORSPolicy: CORSPolicy{AllowCredentials: &allow}},
+			{Path: "/api/v1/public/", CORSPolicy: CORSPolicy{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}},
+			{Path: "/api/v1/public/admin", CORSPolicy: CORSPolicy{AllowedOrigins: []string{"https://admin.inboxpert.io"}}},
+		},
+	}
+
+	tests := []corsRequest{
+		{name: "default policy", path: "/health", origin: "https://app.inboxpert.io", wantOrigin: "https://app.inboxpert.io"},
+		{name: "route override", path: "/api/v1/emails", origin: "https://app.inboxpert.io", wantOrigin: "https://app.inboxpert.io", wantCredentials: true},
+		{name: "route override of the path itself", path: "/api/v1/public", origin: "https://evil.com", wantOrigin: "*"},
+		{name: "longer route override", path: "/api/v1/public/categories", origin: "https://evil.com", wantOrigin: "*"},
+		{name: "longest route override", path: "/api/v1/public/admin/keys", origin: "https://admin.inboxpert.io", wantOrigin: "https://admin.inboxpert.io"},
+		{name: "longest route override does not inherit shorter routes", path: "/api/v1/public/admin/keys", origin: "https://app.inboxpert.io"},
+		{name: "route paths only match whole segments", path: "/api/v1/publicity", origin: "https://evil.com"},
+		{name: "route paths only match whole segments of shorter routes", path: "/api/v1/publicity", origin: "https://app.inboxpert.io", wantOrigin: "https://app.inboxpert.io", wantCredentials: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkCORS(t, serveCORS(t, config, test), test)
+		})
+	}
+
+	t.Run("preflight of a route override", func(t *testing.T) {
+		request := corsRequest{method: http.MethodOptions, path: "/api/v1/public/categories", origin: "https://evil.com", preflight: true, wantStatus: http.StatusNoContent, wantOrigin: "*"}
+		recorder := serveCORS(t, config, request)
+		checkCORS(t, recorder, request)
+
+		header := recorder.Header()
+		if got := header.Get("Access-Control-Allow-Methods"); got != "GET" {
+			t.Errorf("Access-Control-Allow-Methods = %q, want the route's GET", got)
+		}
+		if got := header.Get("Access-Control-Allow-Headers"); got != "Authorization, Content-Type" {
+			t.Errorf("Access-Control-Allow-Headers = %q, want the default headers", got)
+		}
+		if got := header.Get("Access-Control-Max-Age"); got != "600" {
+			t.Errorf("Access-Control-Max-Age = %q, want the default 600", got)
+		}
+		for _, vary := range []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"} {
+			if !slices.Contains(header.Values("Vary"), vary) {
+				t.Errorf("Vary = %v, want it to include %s", header.Values("Vary"), vary)
+			}
+		}
+	})
+}
+
+func TestLoadCORSFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "YAML",
+			file: "cors.yaml",
+			data: `
+allowed_origins: ["https://app.inboxpert.io"]
+allow_credentials: true
+routes:
+  - path: /api/v1/public
+    allowed_origins: ["*"]
+    allow_credentials: false
+`,
+		},
+		{
+			name: "JSON",
+			file: "cors.json",
+			data: `{
+				"allowed_origins": ["https://app.inboxpert.io"],
+				"allow_credentials": true,
+				"routes": [{"path": "/api/v1/public", "allowed_origins": ["*"], "allow_credentials": false}]
+			}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), test.file)
+			if err := os.WriteFile(path, []byte(test.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := LoadCORSFile(path)
+			if err != nil {
+				t.Fatalf("LoadCORSFile() error = %v", err)
+			}
+
+			for _, request := range []corsRequest{
+				{path: "/api/v1/emails", origin: "https://app.inboxpert.io", wantOrigin: "https://app.inboxpert.io", wantCredentials: true},
+				{path: "/api/v1/public", origin: "https://evil.com", wantOrigin: "*"},
+			} {
+				checkCORS(t, serveCORS(t, *config, request), request)
+			}
+		})
+	}
+
+	if _, err := LoadCORSFile(filepath.Join(t.TempDir(), "cors.toml")); err == nil {
+		t.Error("LoadCORSFile() of a missing file error = nil, want an error")
+	}
+}
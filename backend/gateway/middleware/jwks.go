@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	utils "github.com/samiransarii/inboXpert/common/utils"
+	authpb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+)
+
+// refetchThrottle bounds how often a token signed with an unknown key triggers a fetch of the public
+// keys, so tokens with forged key IDs cannot make every request call the auth service.
+const refetchThrottle = 10 * time.Second
+
+// JWKSConfig configures a JWKSAuthenticator.
+type JWKSConfig struct {
+	// ServiceAddr is the address of the auth service publishing the public keys.
+	ServiceAddr string
+
+	// Issuer and Audience are the claims every accepted token must carry.
+	Issuer   string
+	Audience string
+
+	// RefreshInterval is how long fetched keys are used before they are fetched again.
+	RefreshInterval time.Duration
+
+	// Timeout bounds every call to the auth service.
+	Timeout time.Duration
+}
+
+// JWKSAuthenticator verifies access tokens locally against the JSON Web Key Set of the auth service,
+// so requests do not wait on the auth service. The keys are fetched again once they are older than
+// the refresh interval, or when a token names a key not fetched yet, since the auth service may have
+// rotated its keys. Revoking a refresh token does not revoke the access tokens already issued, which
+// stay valid until they expire.
+type JWKSAuthenticator struct {
+	config      JWKSConfig
+	grpcManager *utils.GRPCClientManager
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+// NewJWKSAuthenticator creates a JWKSAuthenticator. The keys are fetched on the first request.
+func NewJWKSAuthenticator(config JWKSConfig) *JWKSAuthenticator {
+	return &JWKSAuthenticator{
+		config:      config,
+		grpcManager: utils.GetGRPCClientManager(),
+		keys:        make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Authenticate verifies an access token against the public keys of the auth service.
+func (a *JWKSAuthenticator) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if a.stale() {
+		if err := a.fetch(ctx); err != nil {
+			log.Printf("Failed to refresh public keys: %v", err)
+		}
+	}
+
+	expected := jwt.Expected{Issuer: a.config.Issuer, Audience: a.config.Audience, Type: jwt.AccessToken}
+	claims, err := jwt.Verify(token, a.publicKey, expected, time.Now())
+	if errors.Is(err, jwt.ErrUnknownKey) && a.refetchDue() {
+		if err := a.fetch(ctx); err != nil {
+			return nil, err
+		}
+		claims, err = jwt.Verify(token, a.publicKey, expected, time.Now())
+	}
+	if errors.Is(err, jwt.ErrUnknownKey) && a.empty() {
+		// Without any key, tokens cannot be checked at all rather than being invalid.
+		return nil, errors.New("no public keys available to verify tokens")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	return &Identity{
+		UserID:    claims.Subject,
+		Email:     claims.Email,
+		Scopes:    []string{ScopeRead, ScopeCategorize},
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// fetch replaces the cached keys with the public keys currently published by the auth service.
+func (a *JWKSAuthenticator) fetch(ctx context.Context) error {
+	a.mu.Lock()
+	a.triedAt = time.Now()
+	a.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, a.config.Timeout)
+	defer cancel()
+
+	conn, err := a.grpcManager.GetConnection(ctx, a.config.ServiceAddr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to auth service: %w", err)
+	}
+	response, err := authpb.NewAuthServiceClient(conn).GetPublicKeys(ctx, &authpb.GetPublicKeysRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch public keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(response.Keys))
+	for _, key := range response.Keys {
+		jwk := jwt.JWK{
+			KeyType:   key.Kty,
+			KeyID:     key.Kid,
+			Use:       key.Use,
+			Algorithm: key.Alg,
+			Modulus:   key.N,
+			Exponent:  key.E,
+		}
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			log.Printf("Skipping public key %s: %v", key.Kid, err)
+			continue
+		}
+		keys[key.Kid] = publicKey
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	a.mu.Unlock()
+	return nil
+}
+
+// publicKey returns the cached public key with the given key ID.
+func (a *JWKSAuthenticator) publicKey(keyID string) (*rsa.PublicKey, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	key, ok := a.keys[keyID]
+	if !ok {
+		return nil, jwt.ErrUnknownKey
+	}
+	return key, nil
+}
+
+// stale reports whether the cached keys are older than the refresh interval, and were not
+// tried to be fetched again within the throttle.
+func (a *JWKSAuthenticator) stale() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return time.Since(a.fetchedAt) >= a.config.RefreshInterval && time.Since(a.triedAt) >= refetchThrottle
+}
+
+// refetchDue reports whether the keys were last fetched long enough ago to fetch them again.
+func (a *JWKSAuthenticator) refetchDue() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return time.Since(a.triedAt) >= refetchThrottle
+}
+
+// empty reports whether no key is cached.
+func (a *JWKSAuthenticator) empty() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.keys) == 0
+}
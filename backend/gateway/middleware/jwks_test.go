@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authpb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+)
+
+// fakeKeyService is an in-process auth service publishing the public keys the test sets,
+// and counting how often they are fetched.
+type fakeKeyService struct {
+	authpb.UnimplementedAuthServiceServer
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	err     error
+	fetches int
+}
+
+// startKeyService starts a fakeKeyService on a local port, stopped when the test ends.
+func startKeyService(t *testing.T) (*fakeKeyService, string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	service := &fakeKeyService{}
+	server := grpc.NewServer()
+	authpb.RegisterAuthServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return service, listener.Addr().String()
+}
+
+func (s *fakeKeyService) GetPublicKeys(ctx context.Context, req *authpb.GetPublicKeysRequest) (*authpb.GetPublicKeysResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	response := &authpb.GetPublicKeysResponse{}
+	for keyID, key := range s.keys {
+		jwk := jwt.NewJWK(keyID, &key.PublicKey)
+		response.Keys = append(response.Keys, &authpb.PublicKey{
+			Kty: jwk.KeyType,
+			Kid: jwk.KeyID,
+			Use: jwk.Use,
+			Alg: jwk.Algorithm,
+			N:   jwk.Modulus,
+			E:   jwk.Exponent,
+		})
+	}
+	return response, nil
+}
+
+// publish replaces the published keys, or makes fetching them fail with err if set.
+func (s *fakeKeyService) publish(keys map[string]*rsa.PrivateKey, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+	s.err = err
+}
+
+// fetchCount returns how often the keys were fetched.
+func (s *fakeKeyService) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func TestJWKSAuthenticator(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service, addr := startKeyService(t)
+	authenticator := NewJWKSAuthenticator(JWKSConfig{
+		ServiceAddr:     addr,
+		Issuer:          "inboxpert-auth",
+		Audience:        "inboxpert-api",
+		RefreshInterval: time.Hour,
+		Timeout:         5 * time.Second,
+	})
+
+	sign := func(t *testing.T, keyID string, key *rsa.PrivateKey) string {
+		t.Helper()
+		now := time.Now()
+		token, err := jwt.Sign(jwt.Claims{
+			Issuer:    "inboxpert-auth",
+			Subject:   "user-1",
+			Audience:  "inboxpert-api",
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Minute).Unix(),
+			Email:     "user@example.com",
+			Type:      jwt.AccessToken,
+		}, keyID, key)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name string
+		// change updates the published keys and the authenticator's state before the step.
+		change func()
+		token  func(t *testing.T) string
+
+		wantErr     bool
+		wantUnauth  bool // whether the error wraps ErrUnauthenticated, rather than being a 503
+		wantFetches int
+	}{
+		{
+			name:        "fails without reaching the auth service",
+			change:      func() { service.publish(nil, status.Error(codes.Internal, "auth service failed")) },
+			token:       func(t *testing.T) string { return sign(t, "old", oldKey) },
+			wantErr:     true,
+			wantFetches: 1,
+		},
+		{
+			name: "fetches the keys on the first request",
+			change: func() {
+				service.publish(map[string]*rsa.PrivateKey{"old": oldKey}, nil)
+				authenticator.triedAt = time.Time{}
+			},
+			token:       func(t *testing.T) string { return sign(t, "old", oldKey) },
+			wantFetches: 2,
+		},
+		{
+			name:        "uses the fetched keys",
+			change:      func() {},
+			token:       func(t *testing.T) string { return sign(t, "old", oldKey) },
+			wantFetches: 2,
+		},
+		{
+			name:        "rejects tokens signed by another key",
+			change:      func() {},
+			token:       func(t *testing.T) string { return sign(t, "old", newKey) },
+			wantErr:     true,
+			wantUnauth:  true,
+			wantFetches: 2,
+		},
+		{
+			name:        "throttles fetches for unknown key IDs",
+			change:      func() { service.publish(map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey}, nil) },
+			token:       func(t *testing.T) string { return sign(t, "new", newKey) },
+			wantErr:     true,
+			wantUnauth:  true,
+			wantFetches: 2,
+		},
+		{
+			name:        "fetches the keys again for an unknown key ID",
+			change:      func() { authenticator.triedAt = time.Now().Add(-refetchThrottle) },
+			token:       func(t *testing.T) string { return sign(t, "new", newKey) },
+			wantFetches: 3,
+		},
+		{
+			name:        "rejects unknown key IDs once fetched again",
+			change:      func() { authenticator.triedAt = time.Now().Add(-refetchThrottle) },
+			token:       func(t *testing.T) string { return sign(t, "forged", newKey) },
+			wantErr:     true,
+			wantUnauth:  true,
+			wantFetches: 4,
+		},
+		{
+			name: "keeps the cached keys when a refresh fails",
+			change: func() {
+				service.publish(nil, status.Error(codes.Internal, "auth service failed"))
+				authenticator.fetchedAt = time.Now().Add(-time.Hour)
+				authenticator.triedAt = time.Now().Add(-refetchThrottle)
+			},
+			token:       func(t *testing.T) string { return sign(t, "new", newKey) },
+			wantFetches: 5,
+		},
+		{
+			name: "drops rotated keys on the next refresh",
+			change: func() {
+				service.publish(map[string]*rsa.PrivateKey{"new": newKey}, nil)
+				authenticator.fetchedAt = time.Now().Add(-time.Hour)
+				authenticator.triedAt = time.Now().Add(-refetchThrottle)
+			},
+			token:       func(t *testing.T) string { return sign(t, "old", oldKey) },
+			wantErr:     true,
+			wantUnauth:  true,
+			wantFetches: 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.change()
+			identity, err := authenticator.Authenticate(context.Background(), test.token(t))
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("Authenticate() error = nil, want an error")
+				}
+				if unauth := errors.Is(err, ErrUnauthenticated); unauth != test.wantUnauth {
+					t.Errorf("Authenticate() error = %v, want ErrUnauthenticated %t", err, test.wantUnauth)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Authenticate() error = %v", err)
+				}
+				if identity.UserID != "user-1" || identity.Email != "user@example.com" {
+					t.Errorf("Authenticate() = %+v, want the identity of user-1", identity)
+				}
+			}
+			if fetches := service.fetchCount(); fetches != test.wantFetches {
+				t.Errorf("keys fetched %d times, want %d", fetches, test.wantFetches)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	middleware "github.com/samiransarii/inboXpert/gateway/middleware"
+)
+
+// Config sets the limits of every kind of client. Requests authenticated with an API key are limited
+// per key, other authenticated requests per user, and anonymous requests per client IP.
+type Config struct {
+	// User, APIKey, and IP are the request rates of every user, API key, and client IP.
+	User   Limit
+	APIKey Limit
+	IP     Limit
+
+	// UserDailyEmails, APIKeyDailyEmails, and IPDailyEmails are how many emails every user, API key, and
+	// client IP may have categorized per day, starting over at midnight UTC. Zero or less disables the quota.
+	UserDailyEmails   int
+	APIKeyDailyEmails int
+	IPDailyEmails     int
+}
+
+// Limiter rate limits requests and enforces the daily email quotas, keeping its counters in a Store.
+// Rejected requests get 429 Too Many Requests with a Retry-After header, and every limited response
+// reports the state of its limit in X-RateLimit-* headers and of its quota in X-Quota-* headers.
+// Requests are let through when the store fails, so an outage of the store does not take the
+// gateway down with it.
+type Limiter struct {
+	store  Store
+	config Config
+}
+
+// NewLimiter creates a Limiter keeping its counters in store.
+func NewLimiter(store Store, config Config) *Limiter {
+	return &Limiter{store: store, config: config}
+}
+
+// Middleware returns a middleware taking a token from the bucket of the client of every request.
+// It must run after the Auth middleware, if any, so authenticated requests are limited by user or
+// API key rather than by IP.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, limit, _ := l.client(c)
+		if limit.Unlimited() {
+			c.Next()
+			return
+		}
+
+		decision, err := l.store.Take(c.Request.Context(), "rate:"+key, limit, 1, time.Now())
+		if err != nil {
+			log.Printf("Failed to rate limit %s, letting the request through: %v", key, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(unixCeil(decision.Reset), 10))
+		if !decision.Allowed {
+			abortTooManyRequests(c, decision.RetryAfter, "Rate limit exceeded", "too many requests, slow down")
+			return
+		}
+		c.Next()
+	}
+}
+
+// ConsumeEmails counts n emails against the daily quota of the client of a request, before they are
+// categorized. If the quota has no room left for all of them, it rejects the request and returns false,
+// and none of them count against the quota.
+func (l *Limiter) ConsumeEmails(c *gin.Context, n int) bool {
+	key, _, quota := l.client(c)
+	if quota <= 0 {
+		return true
+	}
+
+	decision, err := l.store.Consume(c.Request.Context(), "emails:"+key, quota, n, time.Now())
+	if err != nil {
+		log.Printf("Failed to count %d emails against the quota of %s, letting the request through: %v", n, key, err)
+		return true
+	}
+
+	c.Header("X-Quota-Limit", strconv.Itoa(decision.Limit))
+	c.Header("X-Quota-Remaining", strconv.Itoa(decision.Remaining()))
+	c.Header("X-Quota-Reset", strconv.FormatInt(decision.Reset.Unix(), 10))
+	if !decision.Allowed {
+		abortTooManyRequests(c, time.Until(decision.Reset), "Daily email quota exceeded",
+			"categorizing "+strconv.Itoa(n)+" emails would exceed the daily quota of "+strconv.Itoa(decision.Limit)+
+				" emails, "+strconv.Itoa(decision.Remaining())+" remaining")
+		return false
+	}
+	return true
+}
+
+// client returns the key identifying the client of a request, along with its rate limit and daily quota.
+func (l *Limiter) client(c *gin.Context) (string, Limit, int) {
+	identity := middleware.CurrentIdentity(c)
+	switch {
+	case identity != nil && identity.KeyID != "":
+		return "key:" + identity.KeyID, l.config.APIKey, l.config.APIKeyDailyEmails
+	case identity != nil && identity.UserID != "":
+		return "user:" + identity.UserID, l.config.User, l.config.UserDailyEmails
+	default:
+		return "ip:" + c.ClientIP(), l.config.IP, l.config.IPDailyEmails
+	}
+}
+
+// abortTooManyRequests rejects a request with 429 Too Many Requests, telling the client how many
+// whole seconds to wait before retrying.
+func abortTooManyRequests(c *gin.Context, retryAfter time.Duration, message, reason string) {
+	c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1)))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"status":  "error",
+		"message": message,
+		"error":   reason,
+	})
+}
+
+// unixCeil returns the Unix time of t in seconds, rounded up so clients waiting until then are not early.
+func unixCeil(t time.Time) int64 {
+	return int64(math.Ceil(float64(t.UnixNano()) / float64(time.Second)))
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the in-memory store drops full buckets and past days' usage,
+// so clients seen once do not hold memory forever.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps the token buckets and quota counters in memory. It is the default store: it needs
+// nothing else to run, but every gateway replica limits clients on its own, and restarting the gateway
+// resets every limit and quota.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	usage     map[string]*memoryUsage
+	lastSweep time.Time
+}
+
+// memoryBucket is a token bucket, holding tokens as of updatedAt. fullAt is when it refills completely.
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time
+}
+
+// memoryUsage is the usage of a quota on a given day.
+type memoryUsage struct {
+	day  time.Time
+	used int
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		usage:   make(map[string]*memoryUsage),
+	}
+}
+
+// Take takes cost tokens from the bucket of key.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, cost float64, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Burst), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, decision := take(bucket.tokens, now.Sub(bucket.updatedAt), limit, cost, now)
+	bucket.tokens = tokens
+	bucket.updatedAt = now
+	bucket.fullAt = decision.Reset
+	return decision, nil
+}
+
+// Consume adds n to today's usage of key, if it stays within quota.
+func (s *MemoryStore) Consume(ctx context.Context, key string, quota, n int, now time.Time) (QuotaDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	today := day(now)
+	usage, ok := s.usage[key]
+	if !ok || !usage.day.Equal(today) {
+		usage = &memoryUsage{day: today}
+		s.usage[key] = usage
+	}
+
+	decision := QuotaDecision{Limit: quota, Used: usage.used, Reset: nextReset(now)}
+	if usage.used+n <= quota {
+		usage.used += n
+		decision.Used = usage.used
+		decision.Allowed = true
+	}
+	return decision, nil
+}
+
+// Close does nothing, since the store holds no resources.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// sweep drops the buckets that have refilled completely, which are no different from buckets not seen
+// yet, and the usage of past days. It runs at most once every sweep interval, with the lock held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	today := day(now)
+	for key, usage := range s.usage {
+		if usage.day.Before(today) {
+			delete(s.usage, key)
+		}
+	}
+}
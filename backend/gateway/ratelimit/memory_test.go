@@ -0,0 +1,226 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 3}
+
+	type step struct {
+		at         time.Duration
+		key        string
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "drains the burst then rejects",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, reset: time.Second},
+				{key: "a", allowed: true, remaining: 1, reset: 2 * time.Second},
+				{key: "a", allowed: true, remaining: 0, reset: 3 * time.Second},
+				{key: "a", remaining: 0, retryAfter: time.Second, reset: 3 * time.Second},
+			},
+		},
+		{
+			name: "refills after elapsed time",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, reset: time.Second},
+				{key: "a", allowed: true, remaining: 1, reset: 2 * time.Second},
+				{key: "a", allowed: true, remaining: 0, reset: 3 * time.Second},
+				{at: 500 * time.Millisecond, key: "a", remaining: 0, retryAfter: 500 * time.Millisecond, reset: 2500 * time.Millisecond},
+				{at: 2 * time.Second, key: "a", allowed: true, remaining: 1, reset: 2 * time.Second},
+			},
+		},
+		{
+			name: "keeps keys apart",
+			steps: []step{
+				{key: "a", allowed: true, remaining: 2, reset: time.Second},
+				{key: "a", allowed: true, remaining: 1, reset: 2 * time.Second},
+				{key: "b", allowed: true, remaining: 2, reset: time.Second},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			for i, step := range test.steps {
+				at := now.Add(step.at)
+				decision, err := store.Take(context.Background(), step.key, limit, 1, at)
+				if err != nil {
+					t.Fatalf("step %d: Take() error = %v", i, err)
+				}
+
+				want := Decision{
+					Allowed:    step.allowed,
+					Limit:      limit.Burst,
+					Remaining:  step.remaining,
+					RetryAfter: step.retryAfter,
+					Reset:      at.Add(step.reset),
+				}
+				if decision != want {
+					t.Errorf("step %d: decision = %+v, want %+v", i, decision, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreConsume(t *testing.T) {
+	midnight := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		at      time.Time
+		key     string
+		n       int
+		allowed bool
+		used    int
+		reset   time.Time
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "counts within the quota",
+			steps: []step{
+				{at: now, key: "a", n: 4, allowed: true, used: 4, reset: midnight},
+				{at: now, key: "a", n: 6, allowed: true, used: 10, reset: midnight},
+			},
+		},
+		{
+			name: "does not consume rejected amounts",
+			steps: []step{
+				{at: now, key: "a", n: 8, allowed: true, used: 8, reset: midnight},
+				{at: now, key: "a", n: 3, used: 8, reset: midnight},
+				{at: now, key: "a", n: 2, allowed: true, used: 10, reset: midnight},
+				{at: now, key: "a", n: 1, used: 10, reset: midnight},
+			},
+		},
+		{
+			name: "rejects amounts above the quota on their own",
+			steps: []step{
+				{at: now, key: "a", n: 11, used: 0, reset: midnight},
+			},
+		},
+		{
+			name: "starts over at midnight UTC",
+			steps: []step{
+				{at: now, key: "a", n: 10, allowed: true, used: 10, reset: midnight},
+				{at: midnight.Add(-time.Nanosecond), key: "a", n: 1, used: 10, reset: midnight},
+				{at: midnight, key: "a", n: 1, allowed: true, used: 1, reset: midnight.AddDate(0, 0, 1)},
+			},
+		},
+		{
+			name: "keeps keys apart",
+			steps: []step{
+				{at: now, key: "a", n: 10, allowed: true, used: 10, reset: midnight},
+				{at: now, key: "b", n: 5, allowed: true, used: 5, reset: midnight},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			for i, step := range test.steps {
+				decision, err := store.Consume(context.Background(), step.key, 10, step.n, step.at)
+				if err != nil {
+					t.Fatalf("step %d: Consume() error = %v", i, err)
+				}
+
+				want := QuotaDecision{Allowed: step.allowed, Limit: 10, Used: step.used, Reset: step.reset}
+				if decision != want {
+					t.Errorf("step %d: decision = %+v, want %+v", i, decision, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 120}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		at      time.Duration
+		buckets []string
+		usage   []string
+	}{
+		{
+			name:    "keeps everything before the sweep interval",
+			at:      sweepInterval / 2,
+			buckets: []string{"drained", "full"},
+			usage:   []string{"today"},
+		},
+		{
+			name:    "drops full buckets",
+			at:      sweepInterval,
+			buckets: []string{"drained"},
+			usage:   []string{"today"},
+		},
+		{
+			name: "drops refilled buckets and past days' usage",
+			at:   9 * time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			if _, err := store.Take(ctx, "full", Limit{Rate: 1, Burst: 1}, 0, now); err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if _, err := store.Take(ctx, "drained", limit, 120, now); err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if _, err := store.Consume(ctx, "today", 10, 1, now); err != nil {
+				t.Fatalf("Consume() error = %v", err)
+			}
+
+			store.mu.Lock()
+			store.sweep(now.Add(test.at))
+			buckets, usage := keys(store.buckets), keys(store.usage)
+			store.mu.Unlock()
+
+			if !sameKeys(buckets, test.buckets) {
+				t.Errorf("buckets = %v, want %v", buckets, test.buckets)
+			}
+			if !sameKeys(usage, test.usage) {
+				t.Errorf("usage = %v, want %v", usage, test.usage)
+			}
+		})
+	}
+}
+
+// keys returns the keys of a map.
+func keys[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for key := range m {
+		set[key] = true
+	}
+	return set
+}
+
+// sameKeys reports whether a set of keys holds exactly the wanted keys.
+func sameKeys(got map[string]bool, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for _, key := range want {
+		if !got[key] {
+			return false
+		}
+	}
+	return true
+}
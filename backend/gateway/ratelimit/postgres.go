@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// rateLimitSchema creates the tables holding the token buckets and the daily quota usage if they do
+// not exist yet. Buckets are kept as the tokens they held when last updated, and refilled on every take.
+const rateLimitSchema = `
+	CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+		key TEXT PRIMARY KEY,
+		tokens DOUBLE PRECISION NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS rate_limit_quotas (
+		key TEXT NOT NULL,
+		day DATE NOT NULL,
+		used BIGINT NOT NULL,
+		PRIMARY KEY (key, day)
+	)
+`
+
+// PostgresStore keeps the token buckets and quota counters in PostgreSQL, so every gateway replica
+// shares the same limits. Taking from a bucket locks its row for the duration of a short transaction,
+// which costs a few round trips per request: prefer the Redis store under heavy traffic.
+type PostgresStore struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewPostgresStore creates a PostgresStore with the given database connection pool.
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{DB: db, stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+// StartCleanup drops idle buckets and past days' usage every interval in the background, until Close
+// is called. Buckets are idle once not updated for idle, which should be at least the time the
+// slowest limit takes to refill completely.
+func (s *PostgresStore) StartCleanup(interval, idle time.Duration) {
+	s.started = true
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				deleted, err := s.DeleteExpired(context.Background(), now.Add(-idle), now)
+				if err != nil {
+					log.Printf("Failed to clean up rate limits: %v", err)
+				} else if deleted > 0 {
+					log.Printf("Dropped %d idle rate limit rows", deleted)
+				}
+			}
+		}
+	}()
+}
+
+// EnsureSchema creates the rate_limit_buckets and rate_limit_quotas tables if they do not exist yet.
+func (s *PostgresStore) EnsureSchema(ctx context.Context) error {
+	if _, err := s.DB.Exec(ctx, rateLimitSchema); err != nil {
+		return fmt.Errorf("failed to create rate limit tables: %w", err)
+	}
+	return nil
+}
+
+// Take takes cost tokens from the bucket of key, locking its row so concurrent requests of the
+// same client, through any replica, take from the bucket one after the other.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, cost float64, now time.Time) (Decision, error) {
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// A bucket not seen before starts full.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING
+	`, key, float64(limit.Burst), now)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	var tokens float64
+	var updatedAt time.Time
+	err = tx.QueryRow(ctx, `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`, key).Scan(&tokens, &updatedAt)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to lock bucket: %w", err)
+	}
+
+	tokens, decision := take(tokens, now.Sub(updatedAt), limit, cost, now)
+	_, err = tx.Exec(ctx, `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`, key, tokens, now)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to update bucket: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Decision{}, fmt.Errorf("failed to commit bucket: %w", err)
+	}
+	return decision, nil
+}
+
+// Consume adds n to today's usage of key in a single statement, which only updates the usage
+// if it stays within quota.
+func (s *PostgresStore) Consume(ctx context.Context, key string, quota, n int, now time.Time) (QuotaDecision, error) {
+	today := day(now)
+	decision := QuotaDecision{Limit: quota, Reset: nextReset(now)}
+
+	if n <= quota {
+		err := s.DB.QueryRow(ctx, `
+			INSERT INTO rate_limit_quotas AS q (key, day, used)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (key, day) DO UPDATE SET used = q.used + EXCLUDED.used
+			WHERE q.used + EXCLUDED.used <= $4
+			RETURNING used
+		`, key, today, n, quota).Scan(&decision.Used)
+		if err == nil {
+			decision.Allowed = true
+			return decision, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return QuotaDecision{}, fmt.Errorf("failed to consume quota: %w", err)
+		}
+	}
+
+	// The quota has no room left: report today's usage without consuming it.
+	err := s.DB.QueryRow(ctx, `SELECT used FROM rate_limit_quotas WHERE key = $1 AND day = $2`, key, today).Scan(&decision.Used)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return QuotaDecision{}, fmt.Errorf("failed to retrieve quota usage: %w", err)
+	}
+	return decision, nil
+}
+
+// Close stops the cleanup, if it was started, and closes the database connection pool.
+func (s *PostgresStore) Close() error {
+	close(s.stop)
+	if s.started {
+		<-s.done
+	}
+	s.DB.Close()
+	return nil
+}
+
+// DeleteExpired drops the buckets not updated since before, which have refilled completely if before is
+// at least a full refill ago, and the usage of the days before today. It returns how many rows were dropped.
+func (s *PostgresStore) DeleteExpired(ctx context.Context, before, now time.Time) (int64, error) {
+	buckets, err := s.DB.Exec(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idle buckets: %w", err)
+	}
+	quotas, err := s.DB.Exec(ctx, `DELETE FROM rate_limit_quotas WHERE day < $1`, day(now))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete past quota usage: %w", err)
+	}
+	return buckets.RowsAffected() + quotas.RowsAffected(), nil
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is the rate of a token bucket: it holds up to Burst tokens, refilled at Rate tokens per
+// second, and every request takes one token. A limit with a Rate of zero or less does not limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether the limit lets every request through.
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// RefillTime returns how long an empty bucket takes to refill completely.
+func (l Limit) RefillTime() time.Duration {
+	if l.Unlimited() {
+		return 0
+	}
+	return secondsDuration(float64(l.Burst) / l.Rate)
+}
+
+// Decision is the outcome of taking tokens from a bucket.
+type Decision struct {
+	// Allowed reports whether the bucket held enough tokens for the request.
+	Allowed bool
+
+	// Limit is the capacity of the bucket, and Remaining the whole tokens left in it.
+	Limit     int
+	Remaining int
+
+	// RetryAfter is how long a rejected request must wait for the bucket to hold enough tokens.
+	RetryAfter time.Duration
+
+	// Reset is when the bucket will be full again.
+	Reset time.Time
+}
+
+// QuotaDecision is the outcome of consuming a daily quota.
+type QuotaDecision struct {
+	// Allowed reports whether the quota had room for the whole amount. Rejected amounts are not consumed.
+	Allowed bool
+
+	// Limit is the daily quota, and Used how much of it was consumed today.
+	Limit int
+	Used  int
+
+	// Reset is when the quota starts over, at the next midnight UTC.
+	Reset time.Time
+}
+
+// Remaining returns how much of the quota is left today.
+func (d QuotaDecision) Remaining() int {
+	return max(d.Limit-d.Used, 0)
+}
+
+// Store keeps the token buckets and quota counters. The in-memory store limits every gateway replica
+// on its own, while the Postgres and Redis stores share the limits between replicas. Every method must
+// be atomic, since concurrent requests of the same client take from the same bucket.
+type Store interface {
+	// Take takes cost tokens from the bucket of key, if it holds enough of them at now.
+	// A bucket not seen before starts full.
+	Take(ctx context.Context, key string, limit Limit, cost float64, now time.Time) (Decision, error)
+
+	// Consume adds n to today's usage of key, if the usage stays within quota.
+	Consume(ctx context.Context, key string, quota, n int, now time.Time) (QuotaDecision, error)
+
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// take refills a bucket holding tokens for the time elapsed since it was last updated, then takes cost
+// tokens from it if it holds enough of them. It returns the tokens left in the bucket and the decision.
+// Stores keeping buckets in Go share it, so every store refills buckets alike.
+func take(tokens float64, elapsed time.Duration, limit Limit, cost float64, now time.Time) (float64, Decision) {
+	burst := float64(limit.Burst)
+	tokens = math.Min(burst, tokens+math.Max(elapsed.Seconds(), 0)*limit.Rate)
+
+	decision := Decision{Limit: limit.Burst}
+	if tokens >= cost {
+		tokens -= cost
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsDuration((cost - tokens) / limit.Rate)
+	}
+	return tokens, decide(decision, tokens, limit, now)
+}
+
+// decide fills in the remaining tokens and the reset time of a decision on a bucket left holding tokens.
+func decide(decision Decision, tokens float64, limit Limit, now time.Time) Decision {
+	decision.Remaining = int(math.Floor(tokens))
+	decision.Reset = now.Add(secondsDuration((float64(limit.Burst) - tokens) / limit.Rate))
+	return decision
+}
+
+// nextReset returns the next midnight UTC after now, when daily quotas start over.
+func nextReset(now time.Time) time.Time {
+	return day(now).AddDate(0, 0, 1)
+}
+
+// day returns the midnight UTC starting the day of now, which quota usage is counted by.
+func day(now time.Time) time.Time {
+	return now.UTC().Truncate(24 * time.Hour)
+}
+
+// secondsDuration converts a number of seconds into a duration.
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,190 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// now is the fixed time the tests decide at.
+var now = time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+
+func TestTake(t *testing.T) {
+	limit := Limit{Rate: 2, Burst: 10}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		cost       float64
+		allowed    bool
+		left       float64
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Time
+	}{
+		{
+			name:      "full bucket",
+			tokens:    10,
+			cost:      1,
+			allowed:   true,
+			left:      9,
+			remaining: 9,
+			reset:     now.Add(500 * time.Millisecond),
+		},
+		{
+			name:      "refills for the elapsed time",
+			tokens:    0,
+			elapsed:   2 * time.Second,
+			cost:      1,
+			allowed:   true,
+			left:      3,
+			remaining: 3,
+			reset:     now.Add(3500 * time.Millisecond),
+		},
+		{
+			name:      "refills up to the burst",
+			tokens:    5,
+			elapsed:   time.Hour,
+			cost:      1,
+			allowed:   true,
+			left:      9,
+			remaining: 9,
+			reset:     now.Add(500 * time.Millisecond),
+		},
+		{
+			name:      "ignores time going backwards",
+			tokens:    4,
+			elapsed:   -time.Second,
+			cost:      1,
+			allowed:   true,
+			left:      3,
+			remaining: 3,
+			reset:     now.Add(3500 * time.Millisecond),
+		},
+		{
+			name:       "rejects when empty",
+			tokens:     0.5,
+			cost:       1,
+			left:       0.5,
+			remaining:  0,
+			retryAfter: 250 * time.Millisecond,
+			reset:      now.Add(4750 * time.Millisecond),
+		},
+		{
+			name:       "rejects costs above the tokens held",
+			tokens:     2,
+			cost:       5,
+			left:       2,
+			remaining:  2,
+			retryAfter: 1500 * time.Millisecond,
+			reset:      now.Add(4 * time.Second),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			left, decision := take(test.tokens, test.elapsed, limit, test.cost, now)
+
+			if left != test.left {
+				t.Errorf("tokens left = %v, want %v", left, test.left)
+			}
+			want := Decision{
+				Allowed:    test.allowed,
+				Limit:      limit.Burst,
+				Remaining:  test.remaining,
+				RetryAfter: test.retryAfter,
+				Reset:      test.reset,
+			}
+			if decision != want {
+				t.Errorf("decision = %+v, want %+v", decision, want)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      Limit
+		unlimited  bool
+		refillTime time.Duration
+	}{
+		{name: "limited", limit: Limit{Rate: 0.5, Burst: 30}, refillTime: time.Minute},
+		{name: "zero rate", limit: Limit{Rate: 0, Burst: 30}, unlimited: true},
+		{name: "zero burst", limit: Limit{Rate: 1, Burst: 0}, unlimited: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.limit.Unlimited(); got != test.unlimited {
+				t.Errorf("Unlimited() = %v, want %v", got, test.unlimited)
+			}
+			if got := test.limit.RefillTime(); got != test.refillTime {
+				t.Errorf("RefillTime() = %v, want %v", got, test.refillTime)
+			}
+		})
+	}
+}
+
+func TestNextReset(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "afternoon",
+			now:  now,
+			want: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "midnight",
+			now:  time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "just before midnight",
+			now:  time.Date(2024, time.March, 10, 23, 59, 59, 0, time.UTC),
+			want: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end of year",
+			now:  time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "other time zone",
+			now:  time.Date(2024, time.March, 11, 0, 30, 0, 0, time.FixedZone("CET", 3600)),
+			want: time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := nextReset(test.now); !got.Equal(test.want) {
+				t.Errorf("nextReset(%v) = %v, want %v", test.now, got, test.want)
+			}
+		})
+	}
+}
+
+func TestQuotaDecisionRemaining(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision QuotaDecision
+		want     int
+	}{
+		{name: "unused", decision: QuotaDecision{Limit: 100}, want: 100},
+		{name: "partly used", decision: QuotaDecision{Limit: 100, Used: 40}, want: 60},
+		{name: "used up", decision: QuotaDecision{Limit: 100, Used: 100}, want: 0},
+		{name: "lowered below usage", decision: QuotaDecision{Limit: 50, Used: 80}, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.decision.Remaining(); got != test.want {
+				t.Errorf("Remaining() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
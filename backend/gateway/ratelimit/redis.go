@@ -0,0 +1,298 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// takeScript takes tokens from a token bucket kept as a hash of its tokens and the time, in
+// milliseconds, it was last updated, refilling it the same way as take. It returns whether the
+// tokens were taken and the tokens left, as a string since Redis truncates Lua numbers to integers.
+// The bucket expires once it would have refilled completely, so idle clients do not hold memory.
+const takeScript = `
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1]) or burst
+local updated_at = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(now - updated_at, 0) / 1000 * rate)
+local allowed = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// consumeScript adds ARGV[1] to the usage counter of a quota if it stays within the quota ARGV[2],
+// and expires the counter at the Unix time ARGV[3], when the quota starts over. It returns whether
+// the usage was added and the usage afterwards.
+const consumeScript = `
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+local n = tonumber(ARGV[1])
+if used + n > tonumber(ARGV[2]) then
+	return {0, used}
+end
+used = redis.call('INCRBY', KEYS[1], n)
+redis.call('EXPIREAT', KEYS[1], ARGV[3])
+return {1, used}
+`
+
+// redisKeyPrefix namespaces the keys of the Redis store, so it can share a database with other data.
+const redisKeyPrefix = "inboxpert:ratelimit:"
+
+// maxIdleRedisConns is the most connections the Redis store keeps open between requests.
+const maxIdleRedisConns = 16
+
+// RedisConfig configures a RedisStore.
+type RedisConfig struct {
+	// Addr is the host and port of the server.
+	Addr string
+
+	// Password authenticates every connection, if set.
+	Password string
+
+	// Timeout bounds dialing the server and every command.
+	Timeout time.Duration
+}
+
+// RedisStore keeps the token buckets and quota counters in Redis, or any server speaking its protocol
+// and running Lua scripts, such as Valkey or KeyDB, so every gateway replica shares the same limits.
+// Every take and consume is a single script, which the server runs atomically. It speaks the protocol
+// itself over a small pool of connections, since it only ever needs EVAL.
+type RedisStore struct {
+	config RedisConfig
+	idle   chan *redisConn
+}
+
+// redisConn is a connection to the server, with a buffered reader for its replies.
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisStore creates a RedisStore. Connections are opened on the first requests.
+func NewRedisStore(config RedisConfig) *RedisStore {
+	return &RedisStore{
+		config: config,
+		idle:   make(chan *redisConn, maxIdleRedisConns),
+	}
+}
+
+// Take takes cost tokens from the bucket of key.
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit, cost float64, now time.Time) (Decision, error) {
+	reply, err := s.eval(ctx, takeScript, redisKeyPrefix+"bucket:"+key,
+		strconv.Itoa(limit.Burst),
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64),
+		strconv.FormatFloat(cost, 'f', -1, 64),
+		strconv.FormatInt(now.UnixMilli(), 10),
+	)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	allowed, tokensReply, err := pairReply(reply)
+	if err != nil {
+		return Decision{}, err
+	}
+	tokensText, ok := tokensReply.(string)
+	if !ok {
+		return Decision{}, fmt.Errorf("unexpected reply %v for bucket tokens", tokensReply)
+	}
+	tokens, err := strconv.ParseFloat(tokensText, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("invalid bucket tokens %q: %w", tokensText, err)
+	}
+
+	decision := Decision{Allowed: allowed == 1, Limit: limit.Burst}
+	if !decision.Allowed {
+		decision.RetryAfter = secondsDuration((cost - tokens) / limit.Rate)
+	}
+	return decide(decision, tokens, limit, now), nil
+}
+
+// Consume adds n to today's usage of key, if it stays within quota.
+func (s *RedisStore) Consume(ctx context.Context, key string, quota, n int, now time.Time) (QuotaDecision, error) {
+	reset := nextReset(now)
+	reply, err := s.eval(ctx, consumeScript, redisKeyPrefix+"quota:"+key+":"+day(now).Format(time.DateOnly),
+		strconv.Itoa(n),
+		strconv.Itoa(quota),
+		strconv.FormatInt(reset.Unix(), 10),
+	)
+	if err != nil {
+		return QuotaDecision{}, err
+	}
+
+	allowed, usedReply, err := pairReply(reply)
+	if err != nil {
+		return QuotaDecision{}, err
+	}
+	used, ok := usedReply.(int64)
+	if !ok {
+		return QuotaDecision{}, fmt.Errorf("unexpected reply %v for quota usage", usedReply)
+	}
+	return QuotaDecision{Allowed: allowed == 1, Limit: quota, Used: int(used), Reset: reset}, nil
+}
+
+// Close closes the idle connections.
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.idle:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// eval runs a script on the server with a single key and the given arguments, returning its reply.
+func (s *RedisStore) eval(ctx context.Context, script, key string, args ...string) (any, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(ctx, s.config.Timeout, append([]string{"EVAL", script, "1", key}, args...)...)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		// The connection may be left mid-reply, so it cannot be reused.
+		conn.Close()
+		return nil, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	s.release(conn)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit script failed: %w", err)
+	}
+	return reply, nil
+}
+
+// conn returns an idle connection, or dials a new one.
+func (s *RedisStore) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: s.config.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if s.config.Password != "" {
+		if _, err := conn.do(ctx, s.config.Timeout, "AUTH", s.config.Password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// release returns a connection to the pool, or closes it if the pool is full.
+func (s *RedisStore) release(conn *redisConn) {
+	select {
+	case s.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// do sends a command and reads its reply, within timeout or the deadline of ctx, whichever comes first.
+func (c *redisConn) do(ctx context.Context, timeout time.Duration, args ...string) (any, error) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// Commands are sent as arrays of bulk strings.
+	command := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		command = append(command, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	if _, err := c.Write(command); err != nil {
+		return nil, err
+	}
+	return readReply(c.reader)
+}
+
+// redisError is an error reply of the server. The connection stays usable after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readReply reads a reply of the server: a simple or bulk string as a string, an integer as an int64,
+// an array as a []any, a null as nil, and an error as a redisError.
+func readReply(reader *bufio.Reader) (any, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(payload)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = readReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line)
+	}
+}
+
+// pairReply splits the reply of the scripts, a flag followed by a value.
+func pairReply(reply any) (int64, any, error) {
+	items, ok := reply.([]any)
+	if !ok || len(items) != 2 {
+		return 0, nil, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	flag, ok := items[0].(int64)
+	if !ok {
+		return 0, nil, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	return flag, items[1], nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/apikeys"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/config"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/handlers"
+)
+
+// apikey issues an API key for a registered user directly in the database. Admin keys are otherwise
+// only issued through the admin routes of the API Gateway, which themselves require an admin key,
+// so the first one is issued with this command.
+//
+// Example:
+//
+//	go run ./cmd/apikey -email admin@example.com -scopes admin -name bootstrap
+//	go run ./cmd/apikey -email ci@example.com -scopes read,categorize -name ci -ttl 2160h
+func main() {
+	email := flag.String("email", "", "email of the user the key acts on behalf of")
+	name := flag.String("name", "", "label of the key")
+	scopes := flag.String("scopes", "read", "comma-separated scopes of the key: read, categorize, admin")
+	ttl := flag.Duration("ttl", 0, "lifetime of the key, or 0 for a key that never expires")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	cfg := config.New()
+	defer cfg.DBPool.Close()
+	ctx := context.Background()
+
+	apiKeyRepo := handlers.NewAPIKeyRepository(cfg.DBPool)
+	if err := apiKeyRepo.EnsureSchema(ctx); err != nil {
+		log.Fatalf("Failed to prepare API keys table: %v", err)
+	}
+
+	user, err := handlers.NewUserRepository(cfg.DBPool).GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(*email)))
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+
+	var expiresAt *time.Time
+	if *ttl > 0 {
+		expiry := time.Now().Add(*ttl)
+		expiresAt = &expiry
+	}
+	key, fullKey, err := apikeys.New(user.ID, *name, strings.Split(*scopes, ","), expiresAt)
+	if err != nil {
+		log.Fatalf("Failed to create API key: %v", err)
+	}
+	if err := apiKeyRepo.CreateKey(ctx, key); err != nil {
+		log.Fatalf("Failed to save API key: %v", err)
+	}
+
+	log.Printf("Created API key %s for user %s with scopes %v", key.ID, user.Email, key.Scopes)
+	fmt.Println(fullKey)
+}
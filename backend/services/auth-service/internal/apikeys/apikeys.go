@@ -0,0 +1,119 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+)
+
+// ErrInvalid is returned by New for keys that cannot be issued as requested.
+var ErrInvalid = errors.New("invalid API key")
+
+// keyPrefix starts every API key, so keys are recognizable in configuration files and by secret scanners,
+// and the gateway tells them apart from access tokens.
+const keyPrefix = "ixp_"
+
+// Key is a newly generated API key. Key is the full key handed to the user, Prefix its public part
+// identifying it, and SecretHash the hash of its secret part, which is stored instead of the key.
+type Key struct {
+	Key        string
+	Prefix     string
+	SecretHash string
+}
+
+// Generate generates a new API key of the form ixp_<prefix>_<secret>, where prefix is 12 random hex
+// characters and secret 256 random bits.
+func Generate() (Key, error) {
+	prefix := make([]byte, 6)
+	if _, err := rand.Read(prefix); err != nil {
+		return Key{}, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+
+	encodedPrefix := hex.EncodeToString(prefix)
+	encodedSecret := base64.RawURLEncoding.EncodeToString(secret)
+	return Key{
+		Key:        keyPrefix + encodedPrefix + "_" + encodedSecret,
+		Prefix:     encodedPrefix,
+		SecretHash: hash(encodedSecret),
+	}, nil
+}
+
+// New generates an API key of a user with the given name and scopes, expiring at expiresAt, or never
+// if it is nil. It returns the key to store along with the full key, which is only ever handed to the
+// user. Duplicate scopes are dropped. It returns an error wrapping ErrInvalid if no scope or an
+// unknown one is requested, or the key would already be expired.
+func New(userID, name string, scopes []string, expiresAt *time.Time) (models.APIKey, string, error) {
+	if userID == "" {
+		return models.APIKey{}, "", fmt.Errorf("%w: user_id is required", ErrInvalid)
+	}
+	if len(scopes) == 0 {
+		return models.APIKey{}, "", fmt.Errorf("%w: at least one scope is required", ErrInvalid)
+	}
+	for _, scope := range scopes {
+		if !models.ValidScope(scope) {
+			return models.APIKey{}, "", fmt.Errorf("%w: unknown scope %q", ErrInvalid, scope)
+		}
+	}
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return models.APIKey{}, "", fmt.Errorf("%w: expiry must be in the future", ErrInvalid)
+	}
+
+	generated, err := Generate()
+	if err != nil {
+		return models.APIKey{}, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return models.APIKey{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Name:       strings.TrimSpace(name),
+		Prefix:     generated.Prefix,
+		SecretHash: generated.SecretHash,
+		Scopes:     slices.Compact(scopes),
+		CreatedAt:  now,
+		ExpiresAt:  expiresAt,
+	}, generated.Key, nil
+}
+
+// Parse splits an API key into its prefix and secret parts.
+func Parse(key string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(key, keyPrefix)
+	if !found {
+		return "", "", false
+	}
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || prefix == "" || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+// Matches reports whether secret is the secret part of the key whose secret hashes to secretHash,
+// comparing in constant time.
+func Matches(secret, secretHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(secretHash)) == 1
+}
+
+// hash returns the hex-encoded SHA-256 hash of a secret. Secrets are random and long enough that
+// a fast hash cannot be brute-forced, unlike passwords, so keys can be checked on every request.
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
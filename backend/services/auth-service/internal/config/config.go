@@ -0,0 +1,72 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/samiransarii/inboXpert/common/utils"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// New creates a new Config instance with initialized settings for the auth service.
+// This includes gRPC server settings, token claims and lifetimes, signing key rotation, and password hashing.
+// It also establishes a database connection pool for use by other parts of the application.
+func New() *models.Config {
+	// Create a database connection pool to handle database interactions.
+	dbPool := connectDB()
+
+	// Read the lifetimes of issued tokens, falling back to the defaults for malformed values.
+	accessTokenTTL := parseDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+	refreshTokenTTL := parseDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	keyRotationInterval := parseDuration("KEY_ROTATION_INTERVAL", 7*24*time.Hour)
+	keyRefreshInterval := parseDuration("KEY_REFRESH_INTERVAL", time.Minute)
+
+	// Keep the bcrypt cost within the range the library accepts.
+	bcryptCost := utils.GetEnvAsInt("BCRYPT_COST", 12)
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		log.Printf("Invalid BCRYPT_COST %d, using %d", bcryptCost, 12)
+		bcryptCost = 12
+	}
+
+	// Return a new Config instance populated with essential parameters.
+	return &models.Config{
+		// GRPCPort defines the network address and port on which the gRPC server will listen.
+		GRPCPort: utils.GetEnv("AUTH_GRPC_PORT", ":50052"),
+
+		// Issuer and Audience are written into every token and required of every validated token,
+		// so tokens issued for another deployment are rejected.
+		Issuer:   utils.GetEnv("JWT_ISSUER", "inboxpert-auth"),
+		Audience: utils.GetEnv("JWT_AUDIENCE", "inboxpert"),
+
+		// AccessTokenTTL and RefreshTokenTTL decide how long users stay logged in: access tokens
+		// are short-lived, and refresh tokens renew them until the user is inactive for too long.
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+
+		// KeyRotationInterval decides how often a new signing key is generated. Retired keys keep
+		// verifying the tokens they signed until those expire. KeyRefreshInterval decides how quickly
+		// every replica picks up a key generated by another one.
+		KeyRotationInterval: keyRotationInterval,
+		KeyRefreshInterval:  keyRefreshInterval,
+
+		// BcryptCost and MinPasswordLength decide how passwords are hashed and which are accepted.
+		// Longer passwords than bcrypt's 72 bytes are always rejected.
+		BcryptCost:        bcryptCost,
+		MinPasswordLength: max(utils.GetEnvAsInt("MIN_PASSWORD_LENGTH", 8), 1),
+
+		// DBPool is the connection pool to the underlying database.
+		DBPool: dbPool,
+	}
+}
+
+// parseDuration reads a positive duration from the environment variable key, falling back to
+// the default for missing or malformed values.
+func parseDuration(key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(utils.GetEnv(key, fallback.String()))
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s, using %s: %v", key, fallback, err)
+		return fallback
+	}
+	return duration
+}
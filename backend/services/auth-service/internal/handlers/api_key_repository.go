@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+)
+
+// ErrAPIKeyNotFound is returned when no API key has the given ID or prefix.
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// apiKeysSchema creates the table holding API keys if it does not exist yet. Keys are looked up by
+// their unique prefix, and only the hash of their secret is stored.
+const apiKeysSchema = `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+		name TEXT NOT NULL DEFAULT '',
+		prefix TEXT NOT NULL UNIQUE,
+		secret_hash TEXT NOT NULL,
+		scopes TEXT[] NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		expires_at TIMESTAMPTZ,
+		last_used_at TIMESTAMPTZ,
+		revoked_at TIMESTAMPTZ
+	);
+	CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id)
+`
+
+// apiKeyColumns lists the columns of an API key, in the order scanAPIKey reads them.
+const apiKeyColumns = `id, user_id, name, prefix, secret_hash, scopes, created_at, expires_at, last_used_at, revoked_at`
+
+// lastUsedResolution is how stale the last-used time of an API key may get, so a key used for
+// many requests in a row is not written to the database on every one of them.
+const lastUsedResolution = time.Minute
+
+// APIKeyRepository provides methods to manage API keys in the database.
+type APIKeyRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository with the given database connection pool.
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+// EnsureSchema creates the api_keys table if it does not exist yet. It depends on the users table.
+func (r *APIKeyRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, apiKeysSchema); err != nil {
+		log.Printf("Failed to create api_keys table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CreateKey inserts a new API key. The key's ID, prefix, and secret hash must already be set.
+// It returns ErrUserNotFound if the key's user does not exist.
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key models.APIKey) error {
+	keyDB := converter.ToAPIKeyDB(key)
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, secret_hash, scopes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.DB.Exec(ctx, query,
+		keyDB.ID,
+		keyDB.UserID,
+		keyDB.Name,
+		keyDB.Prefix,
+		keyDB.SecretHash,
+		keyDB.Scopes,
+		keyDB.CreatedAt,
+		keyDB.ExpiresAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") { // foreign_key_violation, invalid_text_representation
+			return ErrUserNotFound
+		}
+		log.Printf("Failed to save API key: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListKeys retrieves the API keys of a user, or of every user if userID is empty, newest first.
+// Revoked and expired keys are included, so their last use can still be audited.
+func (r *APIKeyRepository) ListKeys(ctx context.Context, userID string) ([]models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE $1 = '' OR user_id::text = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Failed to retrieve API keys: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Printf("Failed to scan API key: %v", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetKeyByPrefix retrieves the API key with the given prefix. It returns ErrAPIKeyNotFound if there is none.
+func (r *APIKeyRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE prefix = $1
+	`
+
+	key, err := scanAPIKey(r.DB.QueryRow(ctx, query, prefix))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to retrieve API key: %v", err)
+		return nil, err
+	}
+	return &key, nil
+}
+
+// RevokeKey revokes an API key, returning it. Revoking a key that was already revoked keeps its
+// original revocation time. It returns ErrAPIKeyNotFound if there is no key with the given ID.
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id string) (*models.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE id::text = $1
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.DB.QueryRow(ctx, query, id, time.Now()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to revoke API key: %v", err)
+		return nil, err
+	}
+	return &key, nil
+}
+
+// TouchKey records that an API key was just used. The last-used time is only written once it is
+// older than lastUsedResolution.
+func (r *APIKeyRepository) TouchKey(ctx context.Context, id string, now time.Time) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+
+	if _, err := r.DB.Exec(ctx, query, id, now, now.Add(-lastUsedResolution)); err != nil {
+		log.Printf("Failed to record API key use: %v", err)
+		return err
+	}
+	return nil
+}
+
+// scanAPIKey scans a row of apiKeyColumns into an API key.
+func scanAPIKey(row pgx.Row) (models.APIKey, error) {
+	var keyDB db.APIKeyDB
+	err := row.Scan(
+		&keyDB.ID,
+		&keyDB.UserID,
+		&keyDB.Name,
+		&keyDB.Prefix,
+		&keyDB.SecretHash,
+		&keyDB.Scopes,
+		&keyDB.CreatedAt,
+		&keyDB.ExpiresAt,
+		&keyDB.LastUsedAt,
+		&keyDB.RevokedAt,
+	)
+	if err != nil {
+		return models.APIKey{}, err
+	}
+	return converter.FromAPIKeyDB(&keyDB), nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/apikeys"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// CreateApiKey issues a new API key for a user, with the requested scopes and an optional expiry
+// as Unix seconds. The full key is only returned by this call and cannot be retrieved later.
+// Callers are trusted to be allowed to issue keys for the user, which the API Gateway restricts
+// to admins.
+func (h *AuthHandler) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.CreateApiKeyResponse, error) {
+	var expiresAt *time.Time
+	if req.ExpiresAt != 0 {
+		expiry := time.Unix(req.ExpiresAt, 0)
+		expiresAt = &expiry
+	}
+
+	key, fullKey, err := apikeys.New(req.UserId, req.Name, req.Scopes, expiresAt)
+	if errors.Is(err, apikeys.ErrInvalid) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %v", err)
+	}
+
+	if err := h.apiKeyRepo.CreateKey(ctx, key); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user %s not found", req.UserId)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to save API key: %v", err)
+	}
+	log.Printf("Created API key %s for user %s with scopes %v", key.ID, key.UserID, key.Scopes)
+
+	return &pb.CreateApiKeyResponse{
+		ApiKey: converter.ToProtoAPIKey(&key),
+		Key:    fullKey,
+	}, nil
+}
+
+// ListApiKeys returns the API keys of a user, or of every user if no user is given, newest first.
+// Revoked and expired keys are included.
+func (h *AuthHandler) ListApiKeys(ctx context.Context, req *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) {
+	keys, err := h.apiKeyRepo.ListKeys(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list API keys: %v", err)
+	}
+
+	response := &pb.ListApiKeysResponse{ApiKeys: make([]*pb.ApiKey, len(keys))}
+	for i := range keys {
+		response.ApiKeys[i] = converter.ToProtoAPIKey(&keys[i])
+	}
+	return response, nil
+}
+
+// RevokeApiKey revokes an API key, which is rejected from then on.
+func (h *AuthHandler) RevokeApiKey(ctx context.Context, req *pb.RevokeApiKeyRequest) (*pb.RevokeApiKeyResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	key, err := h.apiKeyRepo.RevokeKey(ctx, req.Id)
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil, status.Errorf(codes.NotFound, "API key %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke API key: %v", err)
+	}
+	log.Printf("Revoked API key %s of user %s", key.ID, key.UserID)
+
+	return &pb.RevokeApiKeyResponse{ApiKey: converter.ToProtoAPIKey(key)}, nil
+}
+
+// ValidateApiKey checks an API key and returns the user it acts on behalf of and its scopes, recording
+// that the key was used. Unknown, malformed, revoked, and expired keys fail alike.
+func (h *AuthHandler) ValidateApiKey(ctx context.Context, req *pb.ValidateApiKeyRequest) (*pb.ValidateApiKeyResponse, error) {
+	invalid := status.Error(codes.Unauthenticated, "invalid or expired API key")
+
+	prefix, secret, ok := apikeys.Parse(req.Key)
+	if !ok {
+		return nil, invalid
+	}
+	key, err := h.apiKeyRepo.GetKeyByPrefix(ctx, prefix)
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to retrieve API key: %v", err)
+	}
+
+	now := time.Now()
+	if !apikeys.Matches(secret, key.SecretHash) || !key.Active(now) {
+		return nil, invalid
+	}
+
+	// Failing to record the use must not fail the request the key authenticates
+	_ = h.apiKeyRepo.TouchKey(ctx, key.ID, now)
+
+	response := &pb.ValidateApiKeyResponse{
+		UserId: key.UserID,
+		KeyId:  key.ID,
+		Scopes: key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		response.ExpiresAt = key.ExpiresAt.Unix()
+	}
+	return response, nil
+}
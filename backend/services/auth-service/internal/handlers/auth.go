@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/tokens"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// maxPasswordLength is the longest password bcrypt hashes in full. Longer passwords are rejected
+// rather than silently truncated.
+const maxPasswordLength = 72
+
+// AuthHandler implements the AuthService: it registers users, logs them in by issuing tokens,
+// exchanges and revokes refresh tokens, manages API keys, and validates access tokens and API keys
+// for other services.
+type AuthHandler struct {
+	config     *models.Config
+	userRepo   *UserRepository
+	tokenRepo  *TokenRepository
+	apiKeyRepo *APIKeyRepository
+	tokens     *tokens.Manager
+
+	// dummyHash is compared against the password of unknown users at login, so logging in
+	// takes as long whether the email is registered or not.
+	dummyHash []byte
+
+	pb.UnimplementedAuthServiceServer
+}
+
+// NewAuthHandler creates a new AuthHandler given the configuration, the repositories of users, refresh
+// tokens, and API keys, and the token manager issuing and verifying tokens.
+func NewAuthHandler(config *models.Config, userRepo *UserRepository, tokenRepo *TokenRepository, apiKeyRepo *APIKeyRepository, manager *tokens.Manager) (*AuthHandler, error) {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), config.BcryptCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash dummy password: %w", err)
+	}
+	return &AuthHandler{
+		config:     config,
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		apiKeyRepo: apiKeyRepo,
+		tokens:     manager,
+		dummyHash:  dummyHash,
+	}, nil
+}
+
+// Register creates an account for a new email and logs the user in.
+func (h *AuthHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.AuthResponse, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Password) < h.config.MinPasswordLength {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at least %d characters long", h.config.MinPasswordLength)
+	}
+	if len(req.Password) > maxPasswordLength {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at most %d bytes long", maxPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), h.config.BcryptCost)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %v", err)
+	}
+
+	now := time.Now()
+	user := models.User{
+		ID:           uuid.New().String(),
+		Email:        email,
+		Name:         strings.TrimSpace(req.Name),
+		PasswordHash: string(hash),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := h.userRepo.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, ErrUserExists) {
+			return nil, status.Error(codes.AlreadyExists, "an account with this email already exists")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+	}
+	log.Printf("Registered user %s", user.ID)
+
+	return h.login(ctx, &user)
+}
+
+// Login checks a user's email and password and issues a new pair of tokens. Unknown emails and wrong
+// passwords fail alike, so logging in does not reveal which emails are registered.
+func (h *AuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		return nil, status.Errorf(codes.Internal, "failed to retrieve user: %v", err)
+	}
+
+	hash := h.dummyHash
+	if user != nil {
+		hash = []byte(user.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || user == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid email or password")
+	}
+
+	return h.login(ctx, user)
+}
+
+// login issues a new pair of tokens for a user who just registered or logged in, starting a new
+// family of refresh tokens.
+func (h *AuthHandler) login(ctx context.Context, user *models.User) (*pb.AuthResponse, error) {
+	pair, err := h.tokens.Issue(*user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to issue tokens: %v", err)
+	}
+
+	err = h.tokenRepo.SaveToken(ctx, models.RefreshToken{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		FamilyID:  uuid.New().String(),
+		ExpiresAt: pair.RefreshTokenExpiresAt,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save refresh token: %v", err)
+	}
+
+	return &pb.AuthResponse{
+		User:   converter.ToProtoUser(user),
+		Tokens: converter.ToProtoTokenPair(&pair),
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens. Every refresh token can be exchanged
+// once. Presenting one a second time means it was stolen, by whoever presented it first or now, so
+// every token descending from the same login is revoked and the user has to log in again.
+func (h *AuthHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
+	claims, err := h.tokens.Verify(req.RefreshToken, jwt.RefreshToken)
+	if err != nil {
+		return nil, tokenStatusError(err)
+	}
+
+	user, err := h.userRepo.GetUserByID(ctx, claims.Subject)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, status.Error(codes.Unauthenticated, "user no longer exists")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to retrieve user: %v", err)
+	}
+
+	pair, err := h.tokens.Issue(*user)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to issue tokens: %v", err)
+	}
+
+	err = h.tokenRepo.RotateToken(ctx, claims.ID, models.RefreshToken{
+		ID:        pair.RefreshTokenID,
+		UserID:    user.ID,
+		ExpiresAt: pair.RefreshTokenExpiresAt,
+		CreatedAt: time.Now(),
+	})
+	if errors.Is(err, ErrTokenReused) {
+		if revoked, err := h.tokenRepo.RevokeFamily(ctx, claims.ID); err == nil && revoked > 0 {
+			log.Printf("Refresh token %s of user %s was reused, revoked %d tokens of its family", claims.ID, user.ID, revoked)
+		}
+		return nil, status.Error(codes.Unauthenticated, "refresh token was already used or revoked")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rotate refresh token: %v", err)
+	}
+
+	return &pb.AuthResponse{
+		User:   converter.ToProtoUser(user),
+		Tokens: converter.ToProtoTokenPair(&pair),
+	}, nil
+}
+
+// RevokeToken logs a user out by revoking the given refresh token along with every token of its family.
+// Access tokens are not tracked, so those already issued stay valid until they expire, shortly after.
+// Revoking an expired or already revoked token succeeds, since the user is logged out either way.
+func (h *AuthHandler) RevokeToken(ctx context.Context, req *pb.RevokeTokenRequest) (*pb.RevokeTokenResponse, error) {
+	claims, err := h.tokens.Verify(req.RefreshToken, jwt.RefreshToken)
+	if errors.Is(err, jwt.ErrExpired) {
+		return &pb.RevokeTokenResponse{}, nil
+	}
+	if err != nil {
+		return nil, tokenStatusError(err)
+	}
+
+	if _, err := h.tokenRepo.RevokeFamily(ctx, claims.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke refresh token: %v", err)
+	}
+	return &pb.RevokeTokenResponse{}, nil
+}
+
+// ValidateToken verifies an access token and returns the user it authenticates, for services
+// that do not verify tokens themselves against the public keys.
+func (h *AuthHandler) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
+	claims, err := h.tokens.Verify(req.AccessToken, jwt.AccessToken)
+	if err != nil {
+		return nil, tokenStatusError(err)
+	}
+
+	return &pb.ValidateTokenResponse{
+		UserId:    claims.Subject,
+		Email:     claims.Email,
+		ExpiresAt: claims.ExpiresAt,
+		TokenId:   claims.ID,
+	}, nil
+}
+
+// GetPublicKeys returns the public keys of the signing keys still verifying tokens, so other services
+// can verify access tokens locally. They should be fetched again when a token names an unknown key.
+func (h *AuthHandler) GetPublicKeys(ctx context.Context, req *pb.GetPublicKeysRequest) (*pb.GetPublicKeysResponse, error) {
+	keys := h.tokens.PublicKeys()
+	response := &pb.GetPublicKeysResponse{Keys: make([]*pb.PublicKey, len(keys))}
+	for i, key := range keys {
+		response.Keys[i] = converter.ToProtoPublicKey(key)
+	}
+	return response, nil
+}
+
+// normalizeEmail trims and lower-cases an email, so the same address always maps to the same account,
+// and checks that it is a bare address.
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", status.Error(codes.InvalidArgument, "email is required")
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return "", status.Error(codes.InvalidArgument, "email is not a valid address")
+	}
+	return email, nil
+}
+
+// tokenStatusError maps token verification errors to gRPC status errors. Every invalid token is
+// reported as Unauthenticated, with the reason in the message.
+func tokenStatusError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrMalformed),
+		errors.Is(err, jwt.ErrUnknownKey),
+		errors.Is(err, jwt.ErrSignature),
+		errors.Is(err, jwt.ErrExpired),
+		errors.Is(err, jwt.ErrClaims):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "failed to verify token: %v", err)
+	}
+}
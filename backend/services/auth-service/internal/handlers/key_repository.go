@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+)
+
+// signingKeysSchema creates the table holding the keys signing tokens if it does not exist yet.
+// Keys are shared through it by every replica of the auth service.
+const signingKeysSchema = `
+	CREATE TABLE IF NOT EXISTS signing_keys (
+		id TEXT PRIMARY KEY,
+		private_key TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	)
+`
+
+// KeyRepository provides methods to store and load the keys signing tokens. It implements tokens.KeyStore.
+type KeyRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewKeyRepository creates a new instance of KeyRepository with the given database connection pool.
+func NewKeyRepository(db *pgxpool.Pool) *KeyRepository {
+	return &KeyRepository{DB: db}
+}
+
+// EnsureSchema creates the signing_keys table if it does not exist yet.
+func (r *KeyRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, signingKeysSchema); err != nil {
+		log.Printf("Failed to create signing_keys table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SaveKey stores a newly generated signing key.
+func (r *KeyRepository) SaveKey(ctx context.Context, key models.SigningKey) error {
+	keyDB, err := converter.ToSigningKeyDB(key)
+	if err != nil {
+		log.Printf("Failed to encode signing key: %v", err)
+		return err
+	}
+
+	query := `
+		INSERT INTO signing_keys (id, private_key, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.DB.Exec(ctx, query, keyDB.ID, keyDB.PrivateKey, keyDB.CreatedAt, keyDB.ExpiresAt); err != nil {
+		log.Printf("Failed to save signing key: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetKeys retrieves the signing keys that have not expired at now, newest first.
+// Expired keys are deleted, since no token they signed is valid anymore.
+func (r *KeyRepository) GetKeys(ctx context.Context, now time.Time) ([]models.SigningKey, error) {
+	if _, err := r.DB.Exec(ctx, `DELETE FROM signing_keys WHERE expires_at <= $1`, now); err != nil {
+		log.Printf("Failed to delete expired signing keys: %v", err)
+	}
+
+	query := `
+		SELECT id, private_key, created_at, expires_at
+		FROM signing_keys
+		WHERE expires_at > $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, now)
+	if err != nil {
+		log.Printf("Failed to retrieve signing keys: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var keyDB db.SigningKeyDB
+		if err := rows.Scan(&keyDB.ID, &keyDB.PrivateKey, &keyDB.CreatedAt, &keyDB.ExpiresAt); err != nil {
+			log.Printf("Failed to scan signing key: %v", err)
+			continue
+		}
+		key, err := converter.FromSigningKeyDB(&keyDB)
+		if err != nil {
+			log.Printf("Skipping signing key %s: %v", keyDB.ID, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
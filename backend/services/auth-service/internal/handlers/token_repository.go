@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+)
+
+// ErrTokenReused is returned when a refresh token that was already exchanged or revoked is presented.
+var ErrTokenReused = errors.New("refresh token was already used or revoked")
+
+// refreshTokensSchema creates the table tracking issued refresh tokens if it does not exist yet.
+// Tokens are looked up by ID, which is the jti claim of the token, and revoked by family.
+const refreshTokensSchema = `
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id UUID PRIMARY KEY,
+		user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+		family_id UUID NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		revoked_at TIMESTAMPTZ,
+		replaced_by UUID
+	);
+	CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx ON refresh_tokens (family_id)
+`
+
+// saveRefreshTokenQuery inserts a newly issued refresh token.
+const saveRefreshTokenQuery = `
+	INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at, revoked_at, replaced_by)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+// TokenRepository provides methods to track issued refresh tokens in the database, so they can be
+// exchanged only once and revoked before they expire.
+type TokenRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewTokenRepository creates a new instance of TokenRepository with the given database connection pool.
+func NewTokenRepository(db *pgxpool.Pool) *TokenRepository {
+	return &TokenRepository{DB: db}
+}
+
+// EnsureSchema creates the refresh_tokens table if it does not exist yet. It depends on the users table.
+func (r *TokenRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, refreshTokensSchema); err != nil {
+		log.Printf("Failed to create refresh_tokens table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SaveToken starts tracking a newly issued refresh token.
+func (r *TokenRepository) SaveToken(ctx context.Context, token models.RefreshToken) error {
+	tokenDB := converter.ToRefreshTokenDB(token)
+	_, err := r.DB.Exec(ctx, saveRefreshTokenQuery,
+		tokenDB.ID,
+		tokenDB.UserID,
+		tokenDB.FamilyID,
+		tokenDB.ExpiresAt,
+		tokenDB.CreatedAt,
+		tokenDB.RevokedAt,
+		tokenDB.ReplacedBy,
+	)
+	if err != nil {
+		log.Printf("Failed to save refresh token: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RotateToken exchanges the refresh token with the given ID for the next one, within one transaction:
+// the old token is revoked and replaced by the new one, which joins its family. The old token is only
+// revoked if it still is valid, so of concurrent exchanges of the same token exactly one succeeds.
+// It returns ErrTokenReused if the old token was already exchanged, revoked, or has expired.
+func (r *TokenRepository) RotateToken(ctx context.Context, oldID string, next models.RefreshToken) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		log.Printf("Failed to start transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $3, replaced_by = $2
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > $3
+		RETURNING family_id
+	`
+
+	err = tx.QueryRow(ctx, query, oldID, next.ID, next.CreatedAt).Scan(&next.FamilyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrTokenReused
+	}
+	if err != nil {
+		log.Printf("Failed to revoke refresh token: %v", err)
+		return err
+	}
+
+	tokenDB := converter.ToRefreshTokenDB(next)
+	_, err = tx.Exec(ctx, saveRefreshTokenQuery,
+		tokenDB.ID,
+		tokenDB.UserID,
+		tokenDB.FamilyID,
+		tokenDB.ExpiresAt,
+		tokenDB.CreatedAt,
+		tokenDB.RevokedAt,
+		tokenDB.ReplacedBy,
+	)
+	if err != nil {
+		log.Printf("Failed to save refresh token: %v", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeFamily revokes every token of the family of the refresh token with the given ID, ending
+// the login it descends from. It returns the number of tokens revoked.
+func (r *TokenRepository) RevokeFamily(ctx context.Context, id string) (int64, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE id = $1)
+			AND revoked_at IS NULL
+	`
+
+	tag, err := r.DB.Exec(ctx, query, id, time.Now())
+	if err != nil {
+		log.Printf("Failed to revoke refresh tokens: %v", err)
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/utils/converter"
+)
+
+var (
+	// ErrUserNotFound is returned when no user has the given ID or email.
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserExists is returned when registering an email that already has an account.
+	ErrUserExists = errors.New("user already exists")
+)
+
+// usersSchema creates the table holding user accounts if it does not exist yet.
+// Emails are stored normalized and are unique, since they identify users at login.
+const usersSchema = `
+	CREATE TABLE IF NOT EXISTS users (
+		id UUID PRIMARY KEY,
+		email TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL DEFAULT '',
+		password_hash TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	)
+`
+
+// UserRepository provides methods to manage user accounts in the database.
+type UserRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewUserRepository creates a new instance of UserRepository with the given database connection pool.
+func NewUserRepository(db *pgxpool.Pool) *UserRepository {
+	return &UserRepository{DB: db}
+}
+
+// EnsureSchema creates the users table if it does not exist yet.
+func (r *UserRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, usersSchema); err != nil {
+		log.Printf("Failed to create users table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CreateUser inserts a new user. The user's ID and timestamps must already be set.
+// It returns ErrUserExists if a user with the same email is already registered.
+func (r *UserRepository) CreateUser(ctx context.Context, user models.User) error {
+	userDB := converter.ToUserDB(user)
+
+	query := `
+		INSERT INTO users (id, email, name, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.DB.Exec(ctx, query,
+		userDB.ID,
+		userDB.Email,
+		userDB.Name,
+		userDB.PasswordHash,
+		userDB.CreatedAt,
+		userDB.UpdatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ErrUserExists
+		}
+		log.Printf("Failed to save user: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUserByEmail retrieves the user registered with a normalized email.
+// It returns ErrUserNotFound if there is none.
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.getUser(ctx, "email", email)
+}
+
+// GetUserByID retrieves a user by ID. It returns ErrUserNotFound if there is none.
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	return r.getUser(ctx, "id::text", id)
+}
+
+// getUser retrieves the user whose column equals value. The column is never user input.
+func (r *UserRepository) getUser(ctx context.Context, column, value string) (*models.User, error) {
+	query := `
+		SELECT id, email, name, password_hash, created_at, updated_at
+		FROM users
+		WHERE ` + column + ` = $1
+	`
+
+	var userDB db.UserDB
+	err := r.DB.QueryRow(ctx, query, value).Scan(
+		&userDB.ID,
+		&userDB.Email,
+		&userDB.Name,
+		&userDB.PasswordHash,
+		&userDB.CreatedAt,
+		&userDB.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to retrieve user: %v", err)
+		return nil, err
+	}
+
+	user := converter.FromUserDB(&userDB)
+	return &user, nil
+}
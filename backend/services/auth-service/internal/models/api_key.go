@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Scopes an API key can be granted. Every key carries at least one of them.
+const (
+	// ScopeRead allows reading stored results: emails awaiting review, category counts, and custom categories.
+	ScopeRead = "read"
+
+	// ScopeCategorize allows categorizing emails, submitting feedback, and managing custom categories.
+	ScopeCategorize = "categorize"
+
+	// ScopeAdmin allows everything, including managing the API keys of every user.
+	ScopeAdmin = "admin"
+)
+
+// ValidScope reports whether scope is one of the scopes an API key can be granted.
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeCategorize || scope == ScopeAdmin
+}
+
+// APIKey is a long-lived credential of a user for scripts and server-side integrations, which cannot
+// log in interactively. The key itself is only shown once, when it is created: Prefix identifies it,
+// and only the hash of its secret part is stored. ExpiresAt, LastUsedAt, and RevokedAt are nil when the
+// key never expires, was never used, or was not revoked.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Active reports whether the key is accepted at now: neither revoked nor expired.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
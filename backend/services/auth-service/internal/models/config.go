@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Config holds configuration data for the auth service.
+// This includes server settings, the claims and lifetimes of issued tokens,
+// the rotation of signing keys, password hashing, and a database connection pool.
+type Config struct {
+	GRPCPort string // gRPC server port
+
+	Issuer          string        // Issuer claim of every token, checked when validating tokens
+	Audience        string        // Audience claim of every token, checked when validating tokens
+	AccessTokenTTL  time.Duration // How long an access token authenticates requests
+	RefreshTokenTTL time.Duration // How long a refresh token can be exchanged for new tokens
+
+	KeyRotationInterval time.Duration // How long a signing key signs tokens before a new one replaces it
+	KeyRefreshInterval  time.Duration // How often the signing keys are reloaded, picking up keys of other replicas
+
+	BcryptCost        int // Work factor of password hashes
+	MinPasswordLength int // Shortest password accepted at registration
+
+	DBPool *pgxpool.Pool
+}
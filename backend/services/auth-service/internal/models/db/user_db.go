@@ -0,0 +1,46 @@
+package db
+
+import "time"
+
+// UserDB represents the database schema for storing user accounts.
+type UserDB struct {
+	ID           string    `db:"id"`            // Unique identifier for the user (UUID).
+	Email        string    `db:"email"`         // The normalized email address the user logs in with.
+	Name         string    `db:"name"`          // The user's display name.
+	PasswordHash string    `db:"password_hash"` // The bcrypt hash of the user's password.
+	CreatedAt    time.Time `db:"created_at"`    // Timestamp indicating when the user registered.
+	UpdatedAt    time.Time `db:"updated_at"`    // Timestamp indicating when the user was last updated.
+}
+
+// RefreshTokenDB represents the database schema for tracking issued refresh tokens.
+type RefreshTokenDB struct {
+	ID         string     `db:"id"`          // The jti claim of the refresh token (UUID).
+	UserID     string     `db:"user_id"`     // The user the token was issued to.
+	FamilyID   string     `db:"family_id"`   // The ID shared by every token descending from the same login.
+	ExpiresAt  time.Time  `db:"expires_at"`  // Timestamp after which the token is no longer accepted.
+	CreatedAt  time.Time  `db:"created_at"`  // Timestamp indicating when the token was issued.
+	RevokedAt  *time.Time `db:"revoked_at"`  // Timestamp indicating when the token was used up or revoked.
+	ReplacedBy *string    `db:"replaced_by"` // The ID of the token that replaced this one, if any.
+}
+
+// SigningKeyDB represents the database schema for storing the keys signing tokens.
+type SigningKeyDB struct {
+	ID         string    `db:"id"`          // The key ID, found in the kid header of the tokens it signed.
+	PrivateKey string    `db:"private_key"` // The PEM-encoded PKCS #8 private key.
+	CreatedAt  time.Time `db:"created_at"`  // Timestamp indicating when the key was generated.
+	ExpiresAt  time.Time `db:"expires_at"`  // Timestamp after which the key no longer verifies tokens.
+}
+
+// APIKeyDB represents the database schema for storing API keys.
+type APIKeyDB struct {
+	ID         string     `db:"id"`           // Unique identifier for the key (UUID).
+	UserID     string     `db:"user_id"`      // The user the key acts on behalf of.
+	Name       string     `db:"name"`         // A label telling the user's keys apart.
+	Prefix     string     `db:"prefix"`       // The public part of the key, used to look it up.
+	SecretHash string     `db:"secret_hash"`  // The SHA-256 hash of the secret part of the key.
+	Scopes     []string   `db:"scopes"`       // The scopes granted to the key.
+	CreatedAt  time.Time  `db:"created_at"`   // Timestamp indicating when the key was created.
+	ExpiresAt  *time.Time `db:"expires_at"`   // Timestamp after which the key is rejected, if any.
+	LastUsedAt *time.Time `db:"last_used_at"` // Timestamp of the last request authenticated with the key, if any.
+	RevokedAt  *time.Time `db:"revoked_at"`   // Timestamp indicating when the key was revoked, if it was.
+}
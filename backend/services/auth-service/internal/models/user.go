@@ -0,0 +1,50 @@
+package models
+
+import (
+	"crypto/rsa"
+	"time"
+)
+
+// User is a registered account. Email is normalized to lower case and identifies the user at login,
+// and PasswordHash is the bcrypt hash of the user's password, which is never stored or returned as is.
+type User struct {
+	ID           string
+	Email        string
+	Name         string
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// TokenPair is the pair of tokens issued at login: a short-lived access token authenticating requests,
+// and a longer-lived refresh token exchanged for a new pair once the access token expires.
+// RefreshTokenID is the ID of the refresh token, under which it is tracked so it can be revoked.
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenID        string
+	RefreshTokenExpiresAt time.Time
+}
+
+// RefreshToken tracks an issued refresh token. A refresh token can be exchanged once: it is then
+// revoked and replaced by the refresh token of the new pair, so presenting it again reveals that it
+// was stolen. Tokens of the same login share a FamilyID, so a stolen token revokes its whole family.
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy string
+}
+
+// SigningKey is an RSA key signing tokens. The newest key signs every new token, while older keys
+// keep verifying the tokens they signed until ExpiresAt, when the last of those tokens has expired.
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/handlers"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/tokens"
+
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// Server initializes and runs a gRPC server for authentication.
+// It sets up the user, refresh token, API key, and signing key repositories, the token manager rotating the
+// signing keys, and the auth handler, then registers the gRPC service and manages startup/shutdown.
+type Server struct {
+	config      *models.Config
+	tokens      *tokens.Manager
+	grpcServer  *grpc.Server
+	authHandler *handlers.AuthHandler
+}
+
+// NewServer creates a new Server instance, preparing the database tables, loading or generating the
+// signing keys, and configuring the handlers and the gRPC server. It returns an error if any of the
+// components fail to initialize.
+func NewServer(config *models.Config) (*Server, error) {
+	// Initialize the repositories, creating their tables if needed. Refresh tokens and API keys reference users.
+	userRepo := handlers.NewUserRepository(config.DBPool)
+	if err := userRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare users table: %w", err)
+	}
+	tokenRepo := handlers.NewTokenRepository(config.DBPool)
+	if err := tokenRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare refresh tokens table: %w", err)
+	}
+	apiKeyRepo := handlers.NewAPIKeyRepository(config.DBPool)
+	if err := apiKeyRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare API keys table: %w", err)
+	}
+	keyRepo := handlers.NewKeyRepository(config.DBPool)
+	if err := keyRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare signing keys table: %w", err)
+	}
+
+	// Load the signing keys, generating the first one on the first start, and keep rotating them
+	manager := tokens.NewManager(keyRepo, tokens.Options{
+		Issuer:           config.Issuer,
+		Audience:         config.Audience,
+		AccessTTL:        config.AccessTokenTTL,
+		RefreshTTL:       config.RefreshTokenTTL,
+		RotationInterval: config.KeyRotationInterval,
+		RefreshInterval:  config.KeyRefreshInterval,
+	})
+	if err := manager.Start(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	log.Printf("Loaded %d signing keys", len(manager.PublicKeys()))
+
+	// Create the auth handler that ties everything together
+	handler, err := handlers.NewAuthHandler(config, userRepo, tokenRepo, apiKeyRepo, manager)
+	if err != nil {
+		manager.Close()
+		return nil, fmt.Errorf("failed to create auth handler: %w", err)
+	}
+
+	// Create and register the gRPC server and reflection service
+	grpcServer := grpc.NewServer()
+	pb.RegisterAuthServiceServer(grpcServer, handler)
+	reflection.Register(grpcServer)
+
+	return &Server{
+		config:      config,
+		tokens:      manager,
+		grpcServer:  grpcServer,
+		authHandler: handler,
+	}, nil
+}
+
+// Start begins listening on the configured gRPC port and handles incoming requests.
+// If the server fails to start listening, it returns an error.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.config.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	log.Printf("gRPC server is listening on port: %s", s.config.GRPCPort)
+	return s.grpcServer.Serve(listener)
+}
+
+// Stop gracefully stops the gRPC server, ensuring no new requests are accepted and ongoing requests
+// are completed, then stops rotating the signing keys and closes the database connection pool.
+func (s *Server) Stop() {
+	s.grpcServer.GracefulStop()
+	s.tokens.Close()
+	s.config.DBPool.Close()
+}
@@ -0,0 +1,241 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+)
+
+// keyBits is the size of the generated RSA signing keys.
+const keyBits = 2048
+
+// reloadThrottle bounds how often a token signed with an unknown key triggers a reload of the keys,
+// so forged key IDs cannot make every request hit the database.
+const reloadThrottle = 5 * time.Second
+
+// KeyStore persists the signing keys, so every replica of the auth service signs and verifies
+// tokens with the same keys, and keys survive restarts.
+type KeyStore interface {
+	// SaveKey stores a newly generated signing key.
+	SaveKey(ctx context.Context, key models.SigningKey) error
+
+	// GetKeys returns the keys that have not expired at now, newest first.
+	GetKeys(ctx context.Context, now time.Time) ([]models.SigningKey, error)
+}
+
+// Options configures the claims and lifetimes of the issued tokens and the rotation of the signing keys.
+type Options struct {
+	Issuer           string
+	Audience         string
+	AccessTTL        time.Duration
+	RefreshTTL       time.Duration
+	RotationInterval time.Duration
+	RefreshInterval  time.Duration
+}
+
+// Manager issues and verifies tokens. The newest signing key signs every new token, and once it is
+// older than the rotation interval a new key is generated to replace it. Retired keys keep verifying
+// the tokens they signed until those have expired. Keys are reloaded from the store periodically, so
+// keys generated by other replicas are picked up.
+type Manager struct {
+	store   KeyStore
+	options Options
+
+	mu         sync.RWMutex
+	keys       []models.SigningKey
+	lastReload time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewManager creates a Manager backed by the given key store. Start must be called before
+// tokens are issued or verified.
+func NewManager(store KeyStore, options Options) *Manager {
+	return &Manager{
+		store:   store,
+		options: options,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start loads the signing keys, generating the first one if there is none yet or rotating the newest
+// one if it is due, then keeps reloading and rotating them in the background until Close is called.
+func (m *Manager) Start(ctx context.Context) error {
+	if err := m.refresh(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(m.done)
+
+		ticker := time.NewTicker(m.options.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				if err := m.refresh(context.Background()); err != nil {
+					log.Printf("Failed to refresh signing keys: %v", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops reloading and rotating the signing keys.
+func (m *Manager) Close() {
+	close(m.stop)
+	<-m.done
+}
+
+// refresh reloads the signing keys from the store, and generates a new key if the newest one is
+// due for rotation. Replicas rotating at the same time each generate a key, and the newest one wins.
+func (m *Manager) refresh(ctx context.Context) error {
+	now := time.Now()
+	keys, err := m.store.GetKeys(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	if len(keys) == 0 || now.Sub(keys[0].CreatedAt) >= m.options.RotationInterval {
+		key, err := m.generateKey(now)
+		if err != nil {
+			return err
+		}
+		if err := m.store.SaveKey(ctx, key); err != nil {
+			return fmt.Errorf("failed to save signing key: %w", err)
+		}
+		log.Printf("Generated signing key %s", key.ID)
+		keys = append([]models.SigningKey{key}, keys...)
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.lastReload = now
+	m.mu.Unlock()
+	return nil
+}
+
+// generateKey generates a new signing key. It is kept until the last refresh token it may sign
+// has expired: it signs tokens for a rotation interval, and the last of them lives a refresh TTL longer.
+func (m *Manager) generateKey(now time.Time) (models.SigningKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return models.SigningKey{
+		ID:         uuid.New().String(),
+		PrivateKey: privateKey,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(m.options.RotationInterval + max(m.options.RefreshTTL, m.options.AccessTTL)),
+	}, nil
+}
+
+// Issue issues a new pair of tokens for the user, signed with the newest signing key.
+func (m *Manager) Issue(user models.User) (models.TokenPair, error) {
+	m.mu.RLock()
+	if len(m.keys) == 0 {
+		m.mu.RUnlock()
+		return models.TokenPair{}, errors.New("no signing key available")
+	}
+	key := m.keys[0]
+	m.mu.RUnlock()
+
+	now := time.Now()
+	access := jwt.Claims{
+		Issuer:    m.options.Issuer,
+		Subject:   user.ID,
+		Audience:  m.options.Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.options.AccessTTL).Unix(),
+		ID:        uuid.New().String(),
+		Email:     user.Email,
+		Type:      jwt.AccessToken,
+	}
+	accessToken, err := jwt.Sign(access, key.ID, key.PrivateKey)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refresh := access
+	refresh.ExpiresAt = now.Add(m.options.RefreshTTL).Unix()
+	refresh.ID = uuid.New().String()
+	refresh.Email = ""
+	refresh.Type = jwt.RefreshToken
+	refreshToken, err := jwt.Sign(refresh, key.ID, key.PrivateKey)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  time.Unix(access.ExpiresAt, 0),
+		RefreshToken:          refreshToken,
+		RefreshTokenID:        refresh.ID,
+		RefreshTokenExpiresAt: time.Unix(refresh.ExpiresAt, 0),
+	}, nil
+}
+
+// Verify verifies a token of the given type issued by this service, returning its claims.
+// A token signed with a key not loaded yet reloads the keys, at most once every few seconds,
+// since another replica may have just rotated them.
+func (m *Manager) Verify(token string, tokenType jwt.TokenType) (*jwt.Claims, error) {
+	expected := jwt.Expected{Issuer: m.options.Issuer, Audience: m.options.Audience, Type: tokenType}
+
+	claims, err := jwt.Verify(token, m.publicKey, expected, time.Now())
+	if !errors.Is(err, jwt.ErrUnknownKey) || !m.reloadDue() {
+		return claims, err
+	}
+	if err := m.refresh(context.Background()); err != nil {
+		log.Printf("Failed to refresh signing keys: %v", err)
+		return nil, jwt.ErrUnknownKey
+	}
+	return jwt.Verify(token, m.publicKey, expected, time.Now())
+}
+
+// reloadDue reports whether the keys were last reloaded long enough ago to reload them again.
+func (m *Manager) reloadDue() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Since(m.lastReload) >= reloadThrottle
+}
+
+// publicKey returns the public key of the loaded signing key with the given ID.
+func (m *Manager) publicKey(keyID string) (*rsa.PublicKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.keys {
+		if key.ID == keyID {
+			return &key.PrivateKey.PublicKey, nil
+		}
+	}
+	return nil, jwt.ErrUnknownKey
+}
+
+// PublicKeys returns the public keys of every signing key still verifying tokens, sorted by key ID,
+// so other services can verify tokens without calling the auth service.
+func (m *Manager) PublicKeys() []jwt.JWK {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]jwt.JWK, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, jwt.NewJWK(key.ID, &key.PrivateKey.PublicKey))
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyID < keys[j].KeyID })
+	return keys
+}
@@ -0,0 +1,123 @@
+package converter
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models/db"
+)
+
+// ToUserDB converts a service-level User into its database representation.
+func ToUserDB(user models.User) db.UserDB {
+	return db.UserDB{
+		ID:           user.ID,
+		Email:        user.Email,
+		Name:         user.Name,
+		PasswordHash: user.PasswordHash,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+	}
+}
+
+// FromUserDB converts a database user record into a service-level User.
+func FromUserDB(u *db.UserDB) models.User {
+	return models.User{
+		ID:           u.ID,
+		Email:        u.Email,
+		Name:         u.Name,
+		PasswordHash: u.PasswordHash,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+}
+
+// ToRefreshTokenDB converts a tracked refresh token into its database representation.
+// An empty ReplacedBy is stored as NULL.
+func ToRefreshTokenDB(token models.RefreshToken) db.RefreshTokenDB {
+	var replacedBy *string
+	if token.ReplacedBy != "" {
+		replacedBy = &token.ReplacedBy
+	}
+	return db.RefreshTokenDB{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		FamilyID:   token.FamilyID,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+		RevokedAt:  token.RevokedAt,
+		ReplacedBy: replacedBy,
+	}
+}
+
+// ToSigningKeyDB converts a signing key into its database representation, encoding the private key
+// as PEM-encoded PKCS #8.
+func ToSigningKeyDB(key models.SigningKey) (db.SigningKeyDB, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return db.SigningKeyDB{}, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+	return db.SigningKeyDB{
+		ID:         key.ID,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+	}, nil
+}
+
+// FromSigningKeyDB converts a database signing key record into a signing key, decoding its private key.
+func FromSigningKeyDB(k *db.SigningKeyDB) (models.SigningKey, error) {
+	block, _ := pem.Decode([]byte(k.PrivateKey))
+	if block == nil {
+		return models.SigningKey{}, errors.New("signing key is not PEM-encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return models.SigningKey{}, fmt.Errorf("failed to decode signing key: %w", err)
+	}
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return models.SigningKey{}, errors.New("signing key is not an RSA key")
+	}
+	return models.SigningKey{
+		ID:         k.ID,
+		PrivateKey: privateKey,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+	}, nil
+}
+
+// ToAPIKeyDB converts an API key into its database representation.
+func ToAPIKeyDB(key models.APIKey) db.APIKeyDB {
+	return db.APIKeyDB{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		SecretHash: key.SecretHash,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+// FromAPIKeyDB converts a database API key record into an API key.
+func FromAPIKeyDB(k *db.APIKeyDB) models.APIKey {
+	return models.APIKey{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		SecretHash: k.SecretHash,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/auth-service/internal/models"
+	"github.com/samiransarii/inboXpert/services/common/jwt"
+
+	pb "github.com/samiransarii/inboXpert/services/auth-service/proto"
+)
+
+// ToProtoUser converts a User into its protobuf representation, leaving out the password hash.
+func ToProtoUser(user *models.User) *pb.User {
+	return &pb.User{
+		Id:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		CreatedAt: user.CreatedAt.Unix(),
+	}
+}
+
+// ToProtoTokenPair converts a TokenPair into its protobuf representation. Both tokens are bearer tokens.
+func ToProtoTokenPair(pair *models.TokenPair) *pb.TokenPair {
+	return &pb.TokenPair{
+		AccessToken:           pair.AccessToken,
+		RefreshToken:          pair.RefreshToken,
+		TokenType:             "Bearer",
+		AccessTokenExpiresAt:  pair.AccessTokenExpiresAt.Unix(),
+		RefreshTokenExpiresAt: pair.RefreshTokenExpiresAt.Unix(),
+	}
+}
+
+// ToProtoPublicKey converts a JSON Web Key into its protobuf representation.
+func ToProtoPublicKey(key jwt.JWK) *pb.PublicKey {
+	return &pb.PublicKey{
+		Kty: key.KeyType,
+		Kid: key.KeyID,
+		Use: key.Use,
+		Alg: key.Algorithm,
+		N:   key.Modulus,
+		E:   key.Exponent,
+	}
+}
+
+// ToProtoAPIKey converts an API key into its protobuf representation, leaving out the hash of its secret.
+// Unset times are reported as 0.
+func ToProtoAPIKey(key *models.APIKey) *pb.ApiKey {
+	return &pb.ApiKey{
+		Id:         key.ID,
+		UserId:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		CreatedAt:  key.CreatedAt.Unix(),
+		ExpiresAt:  unixOrZero(key.ExpiresAt),
+		LastUsedAt: unixOrZero(key.LastUsedAt),
+		RevokedAt:  unixOrZero(key.RevokedAt),
+	}
+}
+
+// unixOrZero returns the Unix time of t, or 0 if t is nil.
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
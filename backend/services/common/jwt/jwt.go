@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned for tokens that are not well-formed RS256 JSON Web Tokens.
+	ErrMalformed = errors.New("malformed token")
+
+	// ErrUnknownKey is returned for tokens signed with a key that is not known, or no longer valid.
+	ErrUnknownKey = errors.New("token signed with an unknown key")
+
+	// ErrSignature is returned for tokens whose signature does not match their content.
+	ErrSignature = errors.New("invalid token signature")
+
+	// ErrExpired is returned for tokens past their expiry.
+	ErrExpired = errors.New("token has expired")
+
+	// ErrClaims is returned for tokens issued by another issuer, for another audience, or of another type.
+	ErrClaims = errors.New("unexpected token claims")
+)
+
+// algorithm is the only signing algorithm issued and accepted, so tokens cannot downgrade to
+// an unsigned or symmetric algorithm.
+const algorithm = "RS256"
+
+// TokenType tells access tokens, which authenticate requests, from refresh tokens, which are only
+// exchanged for new tokens.
+type TokenType string
+
+const (
+	// AccessToken authenticates requests for a short time.
+	AccessToken TokenType = "access"
+
+	// RefreshToken is exchanged for a new pair of tokens once the access token expires.
+	RefreshToken TokenType = "refresh"
+)
+
+// Claims are the claims of the tokens issued by the auth service. Times are in Unix seconds.
+type Claims struct {
+	Issuer    string    `json:"iss"`
+	Subject   string    `json:"sub"`
+	Audience  string    `json:"aud"`
+	IssuedAt  int64     `json:"iat"`
+	ExpiresAt int64     `json:"exp"`
+	ID        string    `json:"jti"`
+	Email     string    `json:"email,omitempty"`
+	Type      TokenType `json:"token_use"`
+}
+
+// Expected lists the claims a token must carry to be accepted. Empty fields are not checked.
+type Expected struct {
+	Issuer   string
+	Audience string
+	Type     TokenType
+}
+
+// header is the JOSE header of a token.
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// KeyFunc returns the public key with the given key ID, or ErrUnknownKey.
+type KeyFunc func(keyID string) (*rsa.PublicKey, error)
+
+// Sign encodes the claims into a token signed with RS256 by the private key with the given key ID.
+func Sign(claims Claims, keyID string, key *rsa.PrivateKey) (string, error) {
+	headerJSON, err := json.Marshal(header{Algorithm: algorithm, Type: "JWT", KeyID: keyID})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(headerJSON) + "." + encode(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signingInput + "." + encode(signature), nil
+}
+
+// Verify checks the signature of a token against the key named by its header, that it has not
+// expired at now, and that it carries the expected claims. It returns the claims of a valid token.
+func Verify(token string, keys KeyFunc, expected Expected, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, err
+	}
+	if h.Algorithm != algorithm {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrMalformed, h.Algorithm)
+	}
+
+	key, err := keys(h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, ErrSignature
+	}
+
+	var claims Claims
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+	if (expected.Issuer != "" && claims.Issuer != expected.Issuer) ||
+		(expected.Audience != "" && claims.Audience != expected.Audience) ||
+		(expected.Type != "" && claims.Type != expected.Type) {
+		return nil, ErrClaims
+	}
+	return &claims, nil
+}
+
+// JWK is an RSA public key in the JSON Web Key format, as published in a JSON Web Key Set
+// so tokens can be verified without calling the auth service.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// NewJWK returns the JSON Web Key of an RSA public key with the given key ID.
+func NewJWK(keyID string, key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		KeyID:     keyID,
+		Use:       "sig",
+		Algorithm: algorithm,
+		Modulus:   encode(key.N.Bytes()),
+		Exponent:  encode(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// PublicKey decodes the RSA public key of a JSON Web Key.
+func (k JWK) PublicKey() (*rsa.PublicKey, error) {
+	if k.KeyType != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+	modulus, err := base64.RawURLEncoding.DecodeString(k.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key modulus: %w", err)
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(k.Exponent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key exponent: %w", err)
+	}
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid key exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
+
+// encode returns the unpadded base64url encoding of data used throughout JSON Web Tokens.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeJSON decodes a base64url-encoded JSON segment of a token.
+func decodeJSON(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
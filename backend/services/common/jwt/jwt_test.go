@@ -0,0 +1,245 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// now is the fixed time tokens are verified at.
+var now = time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+
+var (
+	keysOnce             sync.Once
+	signingKey, otherKey *rsa.PrivateKey
+)
+
+// testKeys returns the key tokens are signed with, published under the key ID "current", and
+// another key that is not published.
+func testKeys(t *testing.T) (*rsa.PrivateKey, *rsa.PrivateKey) {
+	t.Helper()
+	keysOnce.Do(func() {
+		var err error
+		if signingKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatal(err)
+		}
+		if otherKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return signingKey, otherKey
+}
+
+// validClaims returns the claims of an access token valid at now.
+func validClaims() Claims {
+	return Claims{
+		Issuer:    "inboxpert-auth",
+		Subject:   "user-1",
+		Audience:  "inboxpert-api",
+		IssuedAt:  now.Add(-time.Minute).Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+		ID:        "token-1",
+		Email:     "user@example.com",
+		Type:      AccessToken,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, other := testKeys(t)
+	keys := func(keyID string) (*rsa.PublicKey, error) {
+		if keyID == "current" {
+			return &key.PublicKey, nil
+		}
+		return nil, ErrUnknownKey
+	}
+	expected := Expected{Issuer: "inboxpert-auth", Audience: "inboxpert-api", Type: AccessToken}
+
+	sign := func(t *testing.T, claims Claims, keyID string, key *rsa.PrivateKey) string {
+		t.Helper()
+		token, err := Sign(claims, keyID, key)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return token
+	}
+	withClaims := func(change func(*Claims)) Claims {
+		claims := validClaims()
+		change(&claims)
+		return claims
+	}
+
+	tests := []struct {
+		name     string
+		token    func(t *testing.T) string
+		expected Expected
+		wantErr  error
+	}{
+		{
+			name:     "valid",
+			token:    func(t *testing.T) string { return sign(t, validClaims(), "current", key) },
+			expected: expected,
+		},
+		{
+			name: "nothing expected",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.Type = RefreshToken }), "current", key)
+			},
+		},
+		{
+			name: "unsigned algorithm",
+			token: func(t *testing.T) string {
+				return withHeader(sign(t, validClaims(), "current", key), `{"alg":"none","typ":"JWT","kid":"current"}`)
+			},
+			expected: expected,
+			wantErr:  ErrMalformed,
+		},
+		{
+			name: "symmetric algorithm",
+			token: func(t *testing.T) string {
+				return withHeader(sign(t, validClaims(), "current", key), `{"alg":"HS256","typ":"JWT","kid":"current"}`)
+			},
+			expected: expected,
+			wantErr:  ErrMalformed,
+		},
+		{
+			name:     "unknown key ID",
+			token:    func(t *testing.T) string { return sign(t, validClaims(), "retired", key) },
+			expected: expected,
+			wantErr:  ErrUnknownKey,
+		},
+		{
+			name:     "signed by another key",
+			token:    func(t *testing.T) string { return sign(t, validClaims(), "current", other) },
+			expected: expected,
+			wantErr:  ErrSignature,
+		},
+		{
+			name: "tampered claims",
+			token: func(t *testing.T) string {
+				parts := strings.Split(sign(t, validClaims(), "current", key), ".")
+				forged := strings.Split(sign(t, withClaims(func(c *Claims) { c.Subject = "admin" }), "current", key), ".")
+				return parts[0] + "." + forged[1] + "." + parts[2]
+			},
+			expected: expected,
+			wantErr:  ErrSignature,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.ExpiresAt = now.Add(-time.Second).Unix() }), "current", key)
+			},
+			expected: expected,
+			wantErr:  ErrExpired,
+		},
+		{
+			name: "expiring now",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.ExpiresAt = now.Unix() }), "current", key)
+			},
+			expected: expected,
+			wantErr:  ErrExpired,
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.Audience = "other-api" }), "current", key)
+			},
+			expected: expected,
+			wantErr:  ErrClaims,
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.Issuer = "other-auth" }), "current", key)
+			},
+			expected: expected,
+			wantErr:  ErrClaims,
+		},
+		{
+			name: "refresh token as access token",
+			token: func(t *testing.T) string {
+				return sign(t, withClaims(func(c *Claims) { c.Type = RefreshToken }), "current", key)
+			},
+			expected: expected,
+			wantErr:  ErrClaims,
+		},
+		{
+			name: "missing segment",
+			token: func(t *testing.T) string {
+				return strings.Join(strings.Split(sign(t, validClaims(), "current", key), ".")[:2], ".")
+			},
+			expected: expected,
+			wantErr:  ErrMalformed,
+		},
+		{
+			name:     "invalid encoding",
+			token:    func(t *testing.T) string { return "not base64!.e30.e30" },
+			expected: expected,
+			wantErr:  ErrMalformed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := Verify(test.token(t), keys, test.expected, now)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if claims.Subject != "user-1" || claims.Email != "user@example.com" {
+				t.Errorf("Verify() claims = %+v, want those of user-1", claims)
+			}
+		})
+	}
+}
+
+func TestJWK(t *testing.T) {
+	key, _ := testKeys(t)
+
+	jwk := NewJWK("current", &key.PublicKey)
+	if jwk.KeyID != "current" || jwk.Algorithm != "RS256" || jwk.Use != "sig" {
+		t.Errorf("NewJWK() = %+v, want an RS256 signing key with ID current", jwk)
+	}
+	publicKey, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatalf("PublicKey() error = %v", err)
+	}
+	if !publicKey.Equal(&key.PublicKey) {
+		t.Error("PublicKey() does not match the key of the JWK")
+	}
+
+	tests := []struct {
+		name   string
+		change func(*JWK)
+	}{
+		{name: "not RSA", change: func(k *JWK) { k.KeyType = "EC" }},
+		{name: "invalid modulus", change: func(k *JWK) { k.Modulus = "not base64!" }},
+		{name: "invalid exponent encoding", change: func(k *JWK) { k.Exponent = "not base64!" }},
+		{name: "exponent too small", change: func(k *JWK) { k.Exponent = encode([]byte{1}) }},
+		{name: "exponent too large", change: func(k *JWK) { k.Exponent = encode([]byte{1, 0, 0, 0, 0}) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invalid := jwk
+			test.change(&invalid)
+			if _, err := invalid.PublicKey(); err == nil {
+				t.Error("PublicKey() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// withHeader replaces the header of a token with the given JSON.
+func withHeader(token, headerJSON string) string {
+	parts := strings.SplitN(token, ".", 2)
+	return encode([]byte(headerJSON)) + "." + parts[1]
+}
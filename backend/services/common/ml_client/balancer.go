@@ -0,0 +1,272 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// dnsPrefix marks an address that stands for every IP address its host resolves to.
+const dnsPrefix = "dns:///"
+
+// lookupHost resolves the host of a "dns:///" address into its IP addresses.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// replica is one instance of the ML service and its request counters.
+type replica struct {
+	addr        string
+	healthy     atomic.Bool
+	outstanding atomic.Int64
+	requests    atomic.Int64
+	failures    atomic.Int64
+}
+
+// stats returns a snapshot of the replica's state.
+func (r *replica) stats() ReplicaStats {
+	return ReplicaStats{
+		Address:     r.addr,
+		Healthy:     r.healthy.Load(),
+		Outstanding: r.outstanding.Load(),
+		Requests:    r.requests.Load(),
+		Failures:    r.failures.Load(),
+	}
+}
+
+// invoke sends a request to the replica chosen by the balancing policy. A replica that turns out
+// to be unavailable is ejected right away, rather than at its next health check.
+func (c *MLPredictionClient) invoke(ctx context.Context, call func(client pb.EmailPredictionClient) error) error {
+	r := c.pick()
+	if r == nil {
+		return errors.New("no ML service replica available")
+	}
+
+	conn, err := c.manager.GetConnection(ctx, r.addr)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	r.requests.Add(1)
+	r.outstanding.Add(1)
+	defer r.outstanding.Add(-1)
+
+	err = call(pb.NewEmailPredictionClient(conn))
+	if err != nil && ctx.Err() == nil {
+		r.failures.Add(1)
+		if status.Code(err) == codes.Unavailable && r.healthy.Swap(false) {
+			log.Printf("ML replica %s is unavailable, ejecting it until its next health check: %v", r.addr, err)
+		}
+	}
+	return err
+}
+
+// pick returns the replica the next request is sent to, chosen among the healthy replicas by the
+// balancing policy. If no replica is healthy, every replica is considered, since a request that
+// might fail is better than one that is certain to.
+func (c *MLPredictionClient) pick() *replica {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	candidates := make([]*replica, 0, len(c.replicas))
+	for _, r := range c.replicas {
+		if r.healthy.Load() {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = c.replicas
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Start from the next replica in turn, so ties of the least-outstanding policy are spread evenly.
+	start := int(c.next.Add(1) % uint64(len(candidates)))
+	if c.policy == RoundRobin {
+		return candidates[start]
+	}
+
+	best := candidates[start]
+	for i := 1; i < len(candidates); i++ {
+		r := candidates[(start+i)%len(candidates)]
+		if r.outstanding.Load() < best.outstanding.Load() {
+			best = r
+		}
+	}
+	return best
+}
+
+// healthLoop health checks every replica on each interval until the client is closed,
+// resolving DNS addresses again first so replicas can be added and removed at runtime.
+func (c *MLPredictionClient) healthLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.healthInterval)
+		if err := c.refreshReplicas(ctx); err != nil {
+			log.Printf("Failed to refresh ML replicas, keeping the current ones: %v", err)
+		}
+		c.checkHealth(ctx)
+		cancel()
+	}
+}
+
+// refreshReplicas resolves the configured addresses into the current set of replicas,
+// keeping the counters of replicas that remain and closing the connections of removed ones.
+// New replicas are considered healthy until their first health check.
+func (c *MLPredictionClient) refreshReplicas(ctx context.Context) error {
+	addrs, err := resolveTargets(ctx, c.targets)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing := make(map[string]*replica, len(c.replicas))
+	for _, r := range c.replicas {
+		existing[r.addr] = r
+	}
+
+	replicas := make([]*replica, 0, len(addrs))
+	for _, addr := range addrs {
+		if r, ok := existing[addr]; ok {
+			replicas = append(replicas, r)
+			delete(existing, addr)
+			continue
+		}
+		if _, err := c.manager.GetConnection(ctx, addr); err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", addr, err)
+		}
+		r := &replica{addr: addr}
+		r.healthy.Store(true)
+		replicas = append(replicas, r)
+		if c.replicas != nil {
+			log.Printf("Added ML replica %s", addr)
+		}
+	}
+
+	for addr := range existing {
+		if err := c.manager.CloseConnection(addr); err != nil {
+			log.Printf("Error closing connection to removed ML replica %s: %v", addr, err)
+		}
+		log.Printf("Removed ML replica %s", addr)
+	}
+
+	c.replicas = replicas
+	return nil
+}
+
+// checkHealth health checks every replica concurrently with the standard gRPC health service.
+// Replicas whose server does not implement the health service count as healthy as long as they
+// answer at all.
+func (c *MLPredictionClient) checkHealth(ctx context.Context) {
+	c.mu.RLock()
+	replicas := slices.Clone(c.replicas)
+	c.mu.RUnlock()
+
+	results := make(chan struct{}, len(replicas))
+	for _, r := range replicas {
+		go func() {
+			defer func() { results <- struct{}{} }()
+
+			err := c.checkReplica(ctx, r)
+			healthy := err == nil
+			if r.healthy.Swap(healthy) != healthy {
+				if healthy {
+					log.Printf("ML replica %s is healthy again", r.addr)
+				} else {
+					log.Printf("ML replica %s failed its health check: %v", r.addr, err)
+				}
+			}
+		}()
+	}
+	for range replicas {
+		<-results
+	}
+}
+
+// checkReplica health checks a single replica.
+func (c *MLPredictionClient) checkReplica(ctx context.Context, r *replica) error {
+	conn, err := c.manager.GetConnection(ctx, r.addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.healthTimeout)
+	defer cancel()
+
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("replica reports %s", response.Status)
+	}
+	return nil
+}
+
+// resolveTargets expands the configured addresses into replica addresses, resolving every
+// "dns:///host:port" address into one address per IP of host. Duplicates are dropped.
+func resolveTargets(ctx context.Context, targets []string) ([]string, error) {
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+
+	for _, target := range targets {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		if !strings.HasPrefix(target, dnsPrefix) {
+			add(target)
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(strings.TrimPrefix(target, dnsPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", target, err)
+		}
+		ips, err := lookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+		slices.Sort(ips)
+		for _, ip := range ips {
+			add(net.JoinHostPort(ip, port))
+		}
+	}
+
+	if len(addrs) == 0 {
+		return nil, errors.New("no ML service replica found")
+	}
+	return addrs, nil
+}
@@ -0,0 +1,319 @@
+package mlclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/common/utils"
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// fakeReplica is an in-process ML server answering every email with its own address as the category,
+// along with a health service whose status the test controls.
+type fakeReplica struct {
+	pb.UnimplementedEmailPredictionServer
+
+	addr   string
+	health *health.Server
+
+	mu  sync.Mutex
+	err error // returned from every prediction, if set
+}
+
+// startReplica starts a fakeReplica on a local port, stopped when the test ends.
+func startReplica(t *testing.T) *fakeReplica {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	r := &fakeReplica{addr: listener.Addr().String(), health: health.NewServer()}
+	server := grpc.NewServer()
+	pb.RegisterEmailPredictionServer(server, r)
+	healthpb.RegisterHealthServer(server, r.health)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return r
+}
+
+func (r *fakeReplica) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &pb.CategoryResponse{Id: email.Id, Category: r.addr}, nil
+}
+
+// setServing sets the status the replica's health service reports.
+func (r *fakeReplica) setServing(serving bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	r.health.SetServingStatus("", status)
+}
+
+// setErr makes every prediction of the replica fail with err, or succeed if err is nil.
+func (r *fakeReplica) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      BalancingPolicy
+		healthy     []bool
+		outstanding []int64
+		want        []string
+	}{
+		{
+			name:    "round robin takes turns",
+			policy:  RoundRobin,
+			healthy: []bool{true, true, true},
+			want:    []string{"b", "c", "a", "b", "c", "a"},
+		},
+		{
+			name:    "round robin skips unhealthy replicas",
+			policy:  RoundRobin,
+			healthy: []bool{true, false, true},
+			want:    []string{"c", "a", "c", "a"},
+		},
+		{
+			name:    "round robin falls back to every replica when none is healthy",
+			policy:  RoundRobin,
+			healthy: []bool{false, false, false},
+			want:    []string{"b", "c", "a"},
+		},
+		{
+			name:        "least outstanding picks the idlest replica",
+			policy:      LeastOutstanding,
+			healthy:     []bool{true, true, true},
+			outstanding: []int64{4, 1, 3},
+			want:        []string{"b", "b", "b"},
+		},
+		{
+			name:        "least outstanding spreads ties",
+			policy:      LeastOutstanding,
+			healthy:     []bool{true, true, true},
+			outstanding: []int64{2, 2, 2},
+			want:        []string{"b", "c", "a"},
+		},
+		{
+			name:        "least outstanding skips unhealthy replicas",
+			policy:      LeastOutstanding,
+			healthy:     []bool{true, false, true},
+			outstanding: []int64{3, 0, 5},
+			want:        []string{"a", "a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &MLPredictionClient{policy: test.policy}
+			for i, addr := range []string{"a", "b", "c"} {
+				r := &replica{addr: addr}
+				r.healthy.Store(test.healthy[i])
+				if test.outstanding != nil {
+					r.outstanding.Store(test.outstanding[i])
+				}
+				c.replicas = append(c.replicas, r)
+			}
+
+			var got []string
+			for range test.want {
+				got = append(got, c.pick().addr)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("picked %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHealthChecks(t *testing.T) {
+	a, b := startReplica(t), startReplica(t)
+	client, err := NewClient(ClientConfig{Addresses: []string{a.addr, b.addr}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name string
+		// change updates the replicas before the step.
+		change func()
+		// check reports whether the step runs the health checks.
+		check   bool
+		healthy []bool
+		servers []string
+	}{
+		{
+			name:    "serves from every healthy replica",
+			change:  func() {},
+			healthy: []bool{true, true},
+			servers: []string{a.addr, b.addr},
+		},
+		{
+			name:    "ejects a replica failing its health check",
+			change:  func() { b.setServing(false) },
+			check:   true,
+			healthy: []bool{true, false},
+			servers: []string{a.addr},
+		},
+		{
+			name:    "readmits a replica passing its health check again",
+			change:  func() { b.setServing(true) },
+			check:   true,
+			healthy: []bool{true, true},
+			servers: []string{a.addr, b.addr},
+		},
+		{
+			name:    "ejects an unavailable replica right away",
+			change:  func() { a.setErr(status.Error(codes.Unavailable, "shutting down")) },
+			healthy: []bool{false, true},
+		},
+		{
+			name:    "keeps the ejected replica out until its next health check",
+			change:  func() {},
+			healthy: []bool{false, true},
+			servers: []string{b.addr},
+		},
+		{
+			name:    "readmits the replica at its next health check",
+			change:  func() { a.setErr(nil) },
+			check:   true,
+			healthy: []bool{true, true},
+			servers: []string{a.addr, b.addr},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.change()
+			if test.check {
+				client.checkHealth(context.Background())
+			}
+
+			// Send enough requests for every replica in rotation to answer at least one.
+			served := make(map[string]bool)
+			for i := range 4 {
+				response, err := client.CategorizeEmail(context.Background(), &pb.EmailRequest{Id: fmt.Sprint(i)})
+				if err == nil {
+					served[response.Category] = true
+				}
+			}
+
+			var healthy []bool
+			for _, stats := range client.Stats() {
+				healthy = append(healthy, stats.Healthy)
+			}
+			if !slices.Equal(healthy, test.healthy) {
+				t.Errorf("healthy = %v, want %v", healthy, test.healthy)
+			}
+			if test.servers != nil {
+				var servers []string
+				for _, addr := range []string{a.addr, b.addr} {
+					if served[addr] {
+						servers = append(servers, addr)
+					}
+				}
+				if !slices.Equal(servers, test.servers) {
+					t.Errorf("served by %v, want %v", servers, test.servers)
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshReplicasResolvesDNS(t *testing.T) {
+	var resolved []string
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		if host != "ml-server" || resolved == nil {
+			return nil, fmt.Errorf("failed to resolve %s", host)
+		}
+		return resolved, nil
+	}
+	t.Cleanup(func() { lookupHost = net.DefaultResolver.LookupHost })
+
+	c := &MLPredictionClient{
+		targets: []string{"dns:///ml-server:50051", "10.0.0.9:50051"},
+		manager: utils.GetGRPCClientManager(),
+		done:    make(chan struct{}),
+	}
+	t.Cleanup(func() { c.Close() })
+
+	tests := []struct {
+		name     string
+		resolved []string
+		want     []string
+	}{
+		{
+			name:     "resolves every IP of the host",
+			resolved: []string{"10.0.0.2", "10.0.0.1"},
+			want:     []string{"10.0.0.1:50051", "10.0.0.2:50051", "10.0.0.9:50051"},
+		},
+		{
+			name:     "adds and removes replicas as the host resolves differently",
+			resolved: []string{"10.0.0.2", "10.0.0.3"},
+			want:     []string{"10.0.0.2:50051", "10.0.0.3:50051", "10.0.0.9:50051"},
+		},
+		{
+			name:     "drops duplicates",
+			resolved: []string{"10.0.0.9", "10.0.0.3"},
+			want:     []string{"10.0.0.3:50051", "10.0.0.9:50051"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Count a request on every replica, to check the counters of remaining replicas are kept.
+			for _, r := range c.replicas {
+				r.requests.Add(1)
+			}
+			previous := c.Stats()
+
+			resolved = test.resolved
+			if err := c.refreshReplicas(context.Background()); err != nil {
+				t.Fatalf("refreshReplicas() error = %v", err)
+			}
+
+			var addrs []string
+			for _, stats := range c.Stats() {
+				addrs = append(addrs, stats.Address)
+				if !stats.Healthy {
+					t.Errorf("replica %s is unhealthy before its first health check", stats.Address)
+				}
+				for _, before := range previous {
+					if before.Address == stats.Address && before.Requests != stats.Requests {
+						t.Errorf("replica %s has %d requests, want the %d it had", stats.Address, stats.Requests, before.Requests)
+					}
+				}
+			}
+			if !slices.Equal(addrs, test.want) {
+				t.Errorf("replicas = %v, want %v", addrs, test.want)
+			}
+		})
+	}
+
+	resolved = nil
+	if err := c.refreshReplicas(context.Background()); err == nil {
+		t.Error("refreshReplicas() error = nil, want an error when the host fails to resolve")
+	}
+	if len(c.Stats()) != 2 {
+		t.Errorf("got %d replicas after a failed refresh, want the 2 it had", len(c.Stats()))
+	}
+}
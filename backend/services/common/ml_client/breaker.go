@@ -0,0 +1,201 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// ErrCircuitOpen is returned without calling the ML service while a Breaker's circuit is open.
+var ErrCircuitOpen = errors.New("ML service circuit breaker is open")
+
+// BreakerState is the state of a Breaker's circuit.
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through and counts consecutive failures.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects every request until the open timeout has passed.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a limited number of trial requests through to probe the ML service.
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// BreakerConfig holds the thresholds of a Breaker.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before trial requests are let through.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of trial requests let through while half-open.
+	// The circuit closes once all of them succeed, and opens again as soon as one fails.
+	HalfOpenRequests int
+}
+
+// Breaker implements the Service interface by wrapping another Service with a circuit breaker.
+// After FailureThreshold consecutive failures, the ML service is considered down and requests
+// fail fast with ErrCircuitOpen instead of piling up retries and timeouts against it. Once
+// OpenTimeout has passed, a few trial requests decide whether the circuit closes again.
+//
+// Only failures of the ML service itself count, including requests whose deadline was exceeded:
+// requests cancelled by their caller and requests the service rejected as invalid do not. Every call
+// counts once, so a Coalescer must wrap the Breaker rather than the other way around, or one failed
+// batch would count once per email. Breaker is safe for concurrent use.
+type Breaker struct {
+	next   Service
+	config BreakerConfig
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int       // consecutive failures while closed
+	openedAt  time.Time // when the circuit last opened
+	trials    int       // trial requests let through while half-open
+	successes int       // successful trial requests while half-open
+}
+
+// NewBreaker wraps next with a circuit breaker configured by cfg. Non-positive settings fall back
+// to opening after 5 failures, staying open for 30s, and a single trial request.
+func NewBreaker(next Service, cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	return &Breaker{
+		next:   next,
+		config: cfg,
+		state:  BreakerClosed,
+	}
+}
+
+// CategorizeEmail sends the email to the wrapped Service, unless the circuit is open.
+func (b *Breaker) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+	response, err := b.next.CategorizeEmail(ctx, email)
+	b.record(ctx, err)
+	return response, err
+}
+
+// BatchCategorizeEmails sends the batch to the wrapped Service, unless the circuit is open.
+// A batch counts as a single request.
+func (b *Breaker) BatchCategorizeEmails(ctx context.Context, emails []*pb.EmailRequest) (*pb.BatchCategoryResponse, error) {
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+	response, err := b.next.BatchCategorizeEmails(ctx, emails)
+	b.record(ctx, err)
+	return response, err
+}
+
+// Close closes the wrapped Service.
+func (b *Breaker) Close() error {
+	return b.next.Close()
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, moving an open circuit to half-open once its
+// timeout has passed.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen {
+		if time.Since(b.openedAt) < b.config.OpenTimeout {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+	}
+	if b.state == BreakerHalfOpen {
+		if b.trials >= b.config.HalfOpenRequests {
+			return false
+		}
+		b.trials++
+	}
+	return true
+}
+
+// record updates the circuit with the outcome of a request that was let through.
+func (b *Breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A request cancelled by its caller says nothing about the health of the ML service, but one
+	// that ran out of time does: the ML client sets no deadline of its own, so a hung ML service
+	// only ever shows up as the caller's deadline being exceeded.
+	failed := err != nil && isServiceFailure(err)
+	if err != nil && (errors.Is(ctx.Err(), context.Canceled) || !failed) {
+		// The outcome says nothing about the health of the ML service; free the trial slot.
+		if b.state == BreakerHalfOpen {
+			b.trials--
+		}
+		return
+	}
+
+	switch b.state {
+	case BreakerClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.setState(BreakerOpen)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.setState(BreakerOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.setState(BreakerClosed)
+		}
+	}
+}
+
+// setState moves the circuit to state and resets the counters of the new state.
+// The caller must hold b.mu.
+func (b *Breaker) setState(state BreakerState) {
+	log.Printf("ML service circuit breaker: %s -> %s", b.state, state)
+	b.state = state
+	b.failures = 0
+	b.trials = 0
+	b.successes = 0
+	if state == BreakerOpen {
+		b.openedAt = time.Now()
+	}
+}
+
+// isServiceFailure reports whether an error means the ML service is down or overloaded, as
+// opposed to the request itself being rejected.
+func isServiceFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated, codes.Canceled:
+		return false
+	}
+	return true
+}
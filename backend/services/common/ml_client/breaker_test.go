@@ -0,0 +1,218 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// fakeService is a Service returning err from every call and counting the calls that reached it.
+type fakeService struct {
+	err   error
+	calls int
+}
+
+func (s *fakeService) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &pb.CategoryResponse{}, nil
+}
+
+func (s *fakeService) BatchCategorizeEmails(ctx context.Context, emails []*pb.EmailRequest) (*pb.BatchCategoryResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &pb.BatchCategoryResponse{}, nil
+}
+
+func (s *fakeService) Close() error {
+	return nil
+}
+
+// breakerStep is a call through a Breaker, returning err from the ML service.
+type breakerStep struct {
+	err error
+	// ctx returns the context of the call, or nil for a background context.
+	ctx func(t *testing.T) context.Context
+	// expire makes the open timeout pass before the call.
+	expire bool
+
+	// wantCall reports whether the call reaches the ML service, and wantState is the state afterwards.
+	wantCall  bool
+	wantState BreakerState
+}
+
+var errUnavailable = status.Error(codes.Unavailable, "ML service unavailable")
+
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name: "opens after consecutive failures",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+				{wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "successes reset the failure count",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "closes after successful trial requests",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+				{expire: true, wantCall: true, wantState: BreakerHalfOpen},
+				{wantCall: true, wantState: BreakerClosed},
+				{wantCall: true, wantState: BreakerClosed},
+			},
+		},
+		{
+			name: "reopens on a failed trial request",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+				{expire: true, err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+				{wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "does not count cancelled requests",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: status.Error(codes.Canceled, "canceled"), ctx: cancelledContext, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, ctx: cancelledContext, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "does not count rejected requests",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: status.Error(codes.InvalidArgument, "empty email"), wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "counts exceeded deadlines",
+			steps: []breakerStep{
+				{err: status.Error(codes.DeadlineExceeded, "deadline exceeded"), ctx: expiredContext, wantCall: true, wantState: BreakerClosed},
+				{err: status.Error(codes.DeadlineExceeded, "deadline exceeded"), ctx: expiredContext, wantCall: true, wantState: BreakerClosed},
+				{err: status.Error(codes.DeadlineExceeded, "deadline exceeded"), ctx: expiredContext, wantCall: true, wantState: BreakerOpen},
+			},
+		},
+		{
+			name: "frees the trial slot of a cancelled trial request",
+			steps: []breakerStep{
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerClosed},
+				{err: errUnavailable, wantCall: true, wantState: BreakerOpen},
+				{expire: true, err: errUnavailable, ctx: cancelledContext, wantCall: true, wantState: BreakerHalfOpen},
+				{wantCall: true, wantState: BreakerHalfOpen},
+				{wantCall: true, wantState: BreakerClosed},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := &fakeService{}
+			breaker := NewBreaker(service, BreakerConfig{
+				FailureThreshold: 3,
+				OpenTimeout:      time.Hour,
+				HalfOpenRequests: 2,
+			})
+
+			for i, step := range test.steps {
+				if step.expire {
+					expireOpenTimeout(breaker)
+				}
+				ctx := context.Background()
+				if step.ctx != nil {
+					ctx = step.ctx(t)
+				}
+
+				service.err = step.err
+				calls := service.calls
+				_, err := breaker.CategorizeEmail(ctx, &pb.EmailRequest{})
+
+				if called := service.calls > calls; called != step.wantCall {
+					t.Fatalf("step %d: called the ML service = %v, want %v", i, called, step.wantCall)
+				}
+				if !step.wantCall && !errors.Is(err, ErrCircuitOpen) {
+					t.Errorf("step %d: error = %v, want %v", i, err, ErrCircuitOpen)
+				}
+				if state := breaker.State(); state != step.wantState {
+					t.Errorf("step %d: state = %s, want %s", i, state, step.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerLimitsTrialRequests(t *testing.T) {
+	breaker := NewBreaker(&fakeService{err: errUnavailable}, BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour, HalfOpenRequests: 2})
+	if _, err := breaker.BatchCategorizeEmails(context.Background(), nil); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("first request rejected with %v", err)
+	}
+	expireOpenTimeout(breaker)
+	if state := breaker.State(); state != BreakerHalfOpen {
+		t.Fatalf("state = %s, want %s", state, BreakerHalfOpen)
+	}
+
+	// Trial requests still in flight hold their slots, so only two are let through.
+	var allowed int
+	for range 3 {
+		if breaker.allow() {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Errorf("allowed %d trial requests, want 2", allowed)
+	}
+}
+
+// expireOpenTimeout moves the opening of the breaker's circuit back past its open timeout.
+func expireOpenTimeout(b *Breaker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.openedAt = b.openedAt.Add(-b.config.OpenTimeout)
+}
+
+// cancelledContext returns a context its caller cancelled.
+func cancelledContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+// expiredContext returns a context whose deadline was exceeded.
+func expiredContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	t.Cleanup(cancel)
+	return ctx
+}
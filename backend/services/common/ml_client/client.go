@@ -2,57 +2,118 @@ package mlclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/samiransarii/inboXpert/common/utils"
 	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
 )
 
 // NewClient initializes a new MLPredictionClient using the provided configuration.
-// It verifies the connection to the ML service during initialization.
+// It connects to every replica of the ML service and health checks them once before returning,
+// then keeps health checking them in the background until the client is closed.
 func NewClient(cfg ClientConfig) (*MLPredictionClient, error) {
-	manager := utils.GetGRPCClientManager()
+	targets := cfg.Addresses
+	if len(targets) == 0 && cfg.Address != "" {
+		targets = []string{cfg.Address}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no ML service address configured")
+	}
+
+	policy := cfg.Policy
+	switch policy {
+	case "":
+		policy = RoundRobin
+	case RoundRobin, LeastOutstanding:
+	default:
+		return nil, fmt.Errorf("unknown balancing policy %q", policy)
+	}
 
-	// Verify the initial connection to the ML service.
-	_, err := manager.GetConnection(context.Background(), cfg.Address)
-	if err != nil {
+	c := &MLPredictionClient{
+		targets:        targets,
+		policy:         policy,
+		healthInterval: cfg.HealthCheckInterval,
+		healthTimeout:  cfg.HealthCheckTimeout,
+		manager:        utils.GetGRPCClientManager(),
+		done:           make(chan struct{}),
+	}
+	if c.healthInterval <= 0 {
+		c.healthInterval = 10 * time.Second
+	}
+	if c.healthTimeout <= 0 {
+		c.healthTimeout = 2 * time.Second
+	}
+
+	// Verify the initial connection to every replica of the ML service.
+	if err := c.refreshReplicas(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to establish initial connection: %w", err)
 	}
+	c.checkHealth(context.Background())
+
+	c.wg.Add(1)
+	go c.healthLoop()
 
-	return &MLPredictionClient{
-		serviceAddr: cfg.Address,
-		manager:     manager,
-	}, nil
+	return c, nil
 }
 
-// CategorizeEmail sends a single email to the ML service for categorization
+// CategorizeEmail sends a single email to one replica of the ML service for categorization
 // and returns the categorization result.
 func (c *MLPredictionClient) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
-	conn, err := c.manager.GetConnection(ctx, c.serviceAddr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get connection: %w", err)
-	}
-
-	client := pb.NewEmailPredictionClient(conn)
-	return client.CategorizeEmail(ctx, email)
+	var response *pb.CategoryResponse
+	err := c.invoke(ctx, func(client pb.EmailPredictionClient) error {
+		var err error
+		response, err = client.CategorizeEmail(ctx, email)
+		return err
+	})
+	return response, err
 }
 
-// BatchCategorizeEmails sends a batch of emails to the ML service for categorization
+// BatchCategorizeEmails sends a batch of emails to one replica of the ML service for categorization
 // and returns a BatchCategoryResponse containing the results for all emails.
 func (c *MLPredictionClient) BatchCategorizeEmails(ctx context.Context, emails []*pb.EmailRequest) (*pb.BatchCategoryResponse, error) {
-	conn, err := c.manager.GetConnection(ctx, c.serviceAddr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get connection: %w", err)
-	}
-
-	client := pb.NewEmailPredictionClient(conn)
 	request := &pb.BatchEmailRequest{
 		Emails: emails,
 	}
-	return client.BatchCategorizeEmail(ctx, request)
+
+	var response *pb.BatchCategoryResponse
+	err := c.invoke(ctx, func(client pb.EmailPredictionClient) error {
+		var err error
+		response, err = client.BatchCategorizeEmail(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+// Stats returns the state of every replica of the ML service, in the order they were configured.
+func (c *MLPredictionClient) Stats() []ReplicaStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make([]ReplicaStats, len(c.replicas))
+	for i, r := range c.replicas {
+		stats[i] = r.stats()
+	}
+	return stats
 }
 
-// Close terminates the gRPC connection to the ML service.
+// Close stops the health checks and terminates the gRPC connections to every replica of the ML service.
 func (c *MLPredictionClient) Close() error {
-	return c.manager.CloseConnection(c.serviceAddr)
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lastErr error
+	for _, r := range c.replicas {
+		if err := c.manager.CloseConnection(r.addr); err != nil {
+			lastErr = err
+		}
+	}
+	c.replicas = nil
+	return lastErr
 }
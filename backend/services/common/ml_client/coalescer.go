@@ -0,0 +1,250 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// ErrCoalescerClosed is returned for emails submitted to a Coalescer after it was closed.
+var ErrCoalescerClosed = errors.New("ML request coalescer is closed")
+
+// CoalescerConfig holds the settings of a Coalescer.
+type CoalescerConfig struct {
+	// MaxBatchSize is the largest number of emails sent to the ML service in one batch.
+	// A batch is sent as soon as it is full, without waiting for MaxWait.
+	MaxBatchSize int
+	// MaxWait is how long the first email of a batch waits for more emails to arrive.
+	MaxWait time.Duration
+	// BatchTimeout bounds each batch request to the ML service. Since a batch serves several
+	// callers, it cannot use the context of any single one of them.
+	BatchTimeout time.Duration
+}
+
+// Coalescer implements the Service interface by gathering concurrent single-email requests
+// for up to MaxWait or MaxBatchSize emails and sending them to the wrapped Service as one
+// batch request, then handing each caller its own result. This trades a few milliseconds of
+// latency for far fewer round trips to the ML service under load.
+//
+// Each caller only waits as long as its own context allows: cancelled requests are dropped
+// from batches that were not sent yet, and their results are discarded otherwise.
+// If the ML service does not implement batch requests, the Coalescer falls back to sending
+// every email on its own. Coalescer is safe for concurrent use.
+type Coalescer struct {
+	next    Service
+	config  CoalescerConfig
+	pending chan *pendingRequest
+	done    chan struct{}
+
+	wg               sync.WaitGroup
+	closeOnce        sync.Once
+	batchUnsupported atomic.Bool
+}
+
+// pendingRequest is a single-email request waiting for its batch to be sent.
+type pendingRequest struct {
+	ctx    context.Context
+	email  *pb.EmailRequest
+	result chan pendingResult // buffered, so senders never block on callers that gave up
+}
+
+// pendingResult is the outcome of a pendingRequest.
+type pendingResult struct {
+	response *pb.CategoryResponse
+	err      error
+}
+
+// NewCoalescer wraps next with a Coalescer configured by cfg and starts gathering requests.
+// Non-positive settings fall back to batches of 32 emails, a 5ms wait, and a 30s batch timeout.
+func NewCoalescer(next Service, cfg CoalescerConfig) *Coalescer {
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = 32
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 5 * time.Millisecond
+	}
+	if cfg.BatchTimeout <= 0 {
+		cfg.BatchTimeout = 30 * time.Second
+	}
+
+	c := &Coalescer{
+		next:    next,
+		config:  cfg,
+		pending: make(chan *pendingRequest),
+		done:    make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.run()
+	return c
+}
+
+// CategorizeEmail queues the email for the next batch and waits for its result, until the
+// context is cancelled.
+func (c *Coalescer) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
+	request := &pendingRequest{
+		ctx:    ctx,
+		email:  email,
+		result: make(chan pendingResult, 1),
+	}
+
+	select {
+	case c.pending <- request:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, ErrCoalescerClosed
+	}
+
+	select {
+	case result := <-request.result:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// BatchCategorizeEmails sends a batch that is already assembled straight to the wrapped Service.
+func (c *Coalescer) BatchCategorizeEmails(ctx context.Context, emails []*pb.EmailRequest) (*pb.BatchCategoryResponse, error) {
+	return c.next.BatchCategorizeEmails(ctx, emails)
+}
+
+// Close stops gathering requests, waits for the batches in flight to be answered, and closes
+// the wrapped Service.
+func (c *Coalescer) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	c.wg.Wait()
+	return c.next.Close()
+}
+
+// run gathers pending requests into batches and sends each batch in its own goroutine,
+// so a slow batch never holds up the next one.
+func (c *Coalescer) run() {
+	defer c.wg.Done()
+
+	for {
+		var batch []*pendingRequest
+		select {
+		case request := <-c.pending:
+			batch = append(batch, request)
+		case <-c.done:
+			return
+		}
+
+		timer := time.NewTimer(c.config.MaxWait)
+		closed := false
+	gather:
+		for len(batch) < c.config.MaxBatchSize {
+			select {
+			case request := <-c.pending:
+				batch = append(batch, request)
+			case <-timer.C:
+				break gather
+			case <-c.done:
+				closed = true
+				break gather
+			}
+		}
+		timer.Stop()
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.send(batch)
+		}()
+		if closed {
+			return
+		}
+	}
+}
+
+// send sends the requests of a batch whose callers are still waiting to the ML service and
+// delivers the results.
+func (c *Coalescer) send(batch []*pendingRequest) {
+	live := batch[:0]
+	for _, request := range batch {
+		if request.ctx.Err() == nil {
+			live = append(live, request)
+		}
+	}
+
+	switch {
+	case len(live) == 0:
+		return
+	case len(live) == 1 || c.batchUnsupported.Load():
+		c.sendEach(live)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.BatchTimeout)
+	defer cancel()
+
+	emails := make([]*pb.EmailRequest, len(live))
+	for i, request := range live {
+		emails[i] = request.email
+	}
+
+	response, err := c.next.BatchCategorizeEmails(ctx, emails)
+	if status.Code(err) == codes.Unimplemented {
+		c.batchUnsupported.Store(true)
+		c.sendEach(live)
+		return
+	}
+	if err != nil {
+		for _, request := range live {
+			request.result <- pendingResult{err: fmt.Errorf("failed to categorize batch: %w", err)}
+		}
+		return
+	}
+
+	results := matchResults(emails, response.Results)
+	for i, request := range live {
+		if results[i] == nil {
+			request.result <- pendingResult{err: fmt.Errorf("ML service returned no result for email %s", request.email.Id)}
+			continue
+		}
+		request.result <- pendingResult{response: results[i]}
+	}
+}
+
+// sendEach sends every request on its own and concurrently, using the context of its caller.
+func (c *Coalescer) sendEach(requests []*pendingRequest) {
+	var wg sync.WaitGroup
+	for _, request := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response, err := c.next.CategorizeEmail(request.ctx, request.email)
+			request.result <- pendingResult{response: response, err: err}
+		}()
+	}
+	wg.Wait()
+}
+
+// matchResults pairs the results of a batch with its emails. Results are matched by position
+// when the ML service returned one result per email, and by email ID otherwise.
+// Emails without a result are left nil.
+func matchResults(emails []*pb.EmailRequest, results []*pb.CategoryResponse) []*pb.CategoryResponse {
+	if len(results) == len(emails) {
+		return results
+	}
+
+	byID := make(map[string]*pb.CategoryResponse, len(results))
+	for _, result := range results {
+		byID[result.Id] = result
+	}
+	matched := make([]*pb.CategoryResponse, len(emails))
+	for i, email := range emails {
+		matched[i] = byID[email.Id]
+	}
+	return matched
+}
@@ -0,0 +1,207 @@
+package mlclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// batchService is a Service recording the batch and single-email requests it receives. Batch
+// requests fail with batchErr, and otherwise answer every email but those in missing.
+type batchService struct {
+	batchErr error
+	missing  map[string]bool
+
+	mu      sync.Mutex
+	batches [][]string
+	singles []string
+}
+
+func (s *batchService) CategorizeEmail(ctx context.Context, email *pb.EmailRequest) (*pb.CategoryResponse, error) {
+	s.mu.Lock()
+	s.singles = append(s.singles, email.Id)
+	s.mu.Unlock()
+	return &pb.CategoryResponse{Id: email.Id, Category: "category-" + email.Id}, nil
+}
+
+func (s *batchService) BatchCategorizeEmails(ctx context.Context, emails []*pb.EmailRequest) (*pb.BatchCategoryResponse, error) {
+	ids := make([]string, len(emails))
+	for i, email := range emails {
+		ids[i] = email.Id
+	}
+	s.mu.Lock()
+	s.batches = append(s.batches, ids)
+	s.mu.Unlock()
+
+	if s.batchErr != nil {
+		return nil, s.batchErr
+	}
+	response := &pb.BatchCategoryResponse{}
+	for _, id := range ids {
+		if !s.missing[id] {
+			response.Results = append(response.Results, &pb.CategoryResponse{Id: id, Category: "category-" + id})
+		}
+	}
+	return response, nil
+}
+
+func (s *batchService) Close() error {
+	return nil
+}
+
+func TestCoalescer(t *testing.T) {
+	errOverloaded := status.Error(codes.ResourceExhausted, "ML service overloaded")
+
+	tests := []struct {
+		name    string
+		config  CoalescerConfig
+		service *batchService
+		// rounds are the emails sent concurrently, one round after the other.
+		rounds [][]string
+
+		wantBatches [][]string
+		wantSingles []string
+		// wantErrs are the emails whose callers get an error, and wrapping which error if set.
+		wantErrs map[string]error
+	}{
+		{
+			name:        "flushes full batches without waiting",
+			config:      CoalescerConfig{MaxBatchSize: 3, MaxWait: time.Hour},
+			service:     &batchService{},
+			rounds:      [][]string{{"a", "b", "c"}, {"d", "e", "f"}},
+			wantBatches: [][]string{{"a", "b", "c"}, {"d", "e", "f"}},
+		},
+		{
+			name:        "flushes partial batches after the wait",
+			config:      CoalescerConfig{MaxBatchSize: 10, MaxWait: 50 * time.Millisecond},
+			service:     &batchService{},
+			rounds:      [][]string{{"a", "b"}},
+			wantBatches: [][]string{{"a", "b"}},
+		},
+		{
+			name:        "sends a lone email on its own",
+			config:      CoalescerConfig{MaxBatchSize: 10, MaxWait: time.Millisecond},
+			service:     &batchService{},
+			rounds:      [][]string{{"a"}},
+			wantSingles: []string{"a"},
+		},
+		{
+			name:        "fans a batch error out to every caller",
+			config:      CoalescerConfig{MaxBatchSize: 3, MaxWait: time.Hour},
+			service:     &batchService{batchErr: errOverloaded},
+			rounds:      [][]string{{"a", "b", "c"}},
+			wantBatches: [][]string{{"a", "b", "c"}},
+			wantErrs:    map[string]error{"a": errOverloaded, "b": errOverloaded, "c": errOverloaded},
+		},
+		{
+			name:        "fails only the emails missing from the results",
+			config:      CoalescerConfig{MaxBatchSize: 3, MaxWait: time.Hour},
+			service:     &batchService{missing: map[string]bool{"b": true}},
+			rounds:      [][]string{{"a", "b", "c"}},
+			wantBatches: [][]string{{"a", "b", "c"}},
+			wantErrs:    map[string]error{"b": nil},
+		},
+		{
+			name:        "falls back to single requests when batches are unimplemented",
+			config:      CoalescerConfig{MaxBatchSize: 2, MaxWait: time.Hour},
+			service:     &batchService{batchErr: status.Error(codes.Unimplemented, "unknown method BatchCategorizeEmail")},
+			rounds:      [][]string{{"a", "b"}, {"c", "d"}},
+			wantBatches: [][]string{{"a", "b"}},
+			wantSingles: []string{"a", "b", "c", "d"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			coalescer := NewCoalescer(test.service, test.config)
+			defer coalescer.Close()
+
+			for _, round := range test.rounds {
+				var wg sync.WaitGroup
+				for _, id := range round {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						response, err := coalescer.CategorizeEmail(context.Background(), &pb.EmailRequest{Id: id})
+
+						wantErr, failing := test.wantErrs[id]
+						switch {
+						case failing && err == nil:
+							t.Errorf("email %s: got no error, want one", id)
+						case failing && wantErr != nil && !errors.Is(err, wantErr):
+							t.Errorf("email %s: error = %v, want it to wrap %v", id, err, wantErr)
+						case !failing && err != nil:
+							t.Errorf("email %s: error = %v", id, err)
+						case !failing && response.Category != "category-"+id:
+							t.Errorf("email %s: got the result of email %s", id, response.Id)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+
+			// Emails of a round join their batch in any order.
+			batches := make([][]string, len(test.service.batches))
+			for i, batch := range test.service.batches {
+				batches[i] = slices.Sorted(slices.Values(batch))
+			}
+			if fmt.Sprint(batches) != fmt.Sprint(test.wantBatches) {
+				t.Errorf("batches = %v, want %v", batches, test.wantBatches)
+			}
+			singles := slices.Sorted(slices.Values(test.service.singles))
+			if fmt.Sprint(singles) != fmt.Sprint(test.wantSingles) {
+				t.Errorf("single requests = %v, want %v", singles, test.wantSingles)
+			}
+		})
+	}
+}
+
+func TestCoalescerDropsCancelledRequests(t *testing.T) {
+	service := &batchService{}
+	coalescer := NewCoalescer(service, CoalescerConfig{MaxBatchSize: 2, MaxWait: time.Hour})
+	defer coalescer.Close()
+
+	// The cancelled caller gives up while its batch is still gathering, and the batch is sent
+	// once the second email fills it.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		_, err := coalescer.CategorizeEmail(ctx, &pb.EmailRequest{Id: "cancelled"})
+		cancelled <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled request error = %v, want %v", err, context.Canceled)
+	}
+
+	response, err := coalescer.CategorizeEmail(context.Background(), &pb.EmailRequest{Id: "live"})
+	if err != nil {
+		t.Fatalf("live request error = %v", err)
+	}
+	if response.Id != "live" {
+		t.Errorf("live request got the result of email %s", response.Id)
+	}
+	if len(service.batches) != 0 || !slices.Equal(service.singles, []string{"live"}) {
+		t.Errorf("batches = %v and single requests = %v, want only the live email sent on its own", service.batches, service.singles)
+	}
+}
+
+func TestCoalescerClose(t *testing.T) {
+	coalescer := NewCoalescer(&batchService{}, CoalescerConfig{})
+	if err := coalescer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := coalescer.CategorizeEmail(context.Background(), &pb.EmailRequest{Id: "a"}); !errors.Is(err, ErrCoalescerClosed) {
+		t.Errorf("error after Close() = %v, want %v", err, ErrCoalescerClosed)
+	}
+}
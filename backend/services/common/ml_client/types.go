@@ -2,21 +2,65 @@ package mlclient
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/samiransarii/inboXpert/common/utils"
 	pb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
 )
 
 // MLPredictionClient implements the Service interface and provides methods
-// to interact with the ML service for email categorization.
+// to interact with the ML service for email categorization. Requests are balanced
+// across the replicas of the ML service, skipping replicas that fail their health checks.
 type MLPredictionClient struct {
-	serviceAddr string
-	manager     *utils.GRPCClientManager
+	targets        []string
+	policy         BalancingPolicy
+	healthInterval time.Duration
+	healthTimeout  time.Duration
+	manager        *utils.GRPCClientManager
+
+	mu       sync.RWMutex
+	replicas []*replica
+	next     atomic.Uint64
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
 }
 
+// BalancingPolicy decides which replica of the ML service receives a request.
+type BalancingPolicy string
+
+const (
+	// RoundRobin sends requests to the healthy replicas in turn.
+	RoundRobin BalancingPolicy = "round_robin"
+	// LeastOutstanding sends each request to the healthy replica with the fewest requests in flight.
+	LeastOutstanding BalancingPolicy = "least_outstanding"
+)
+
 // ClientConfig holds the configuration required to connect to the ML service.
 type ClientConfig struct {
+	// Address is the address of a single ML server, used when Addresses is empty.
 	Address string
+	// Addresses lists the replicas of the ML service. An address of the form "dns:///host:port"
+	// stands for every IP address host resolves to, and is resolved again on every health check.
+	Addresses []string
+	// Policy decides how requests are balanced across replicas; RoundRobin by default.
+	Policy BalancingPolicy
+	// HealthCheckInterval is how often every replica is health checked; 10s by default.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds each health check; 2s by default.
+	HealthCheckTimeout time.Duration
+}
+
+// ReplicaStats describes the state of one replica of the ML service.
+type ReplicaStats struct {
+	Address     string `json:"address"`
+	Healthy     bool   `json:"healthy"`
+	Outstanding int64  `json:"outstanding"` // requests currently in flight
+	Requests    int64  `json:"requests"`    // requests sent since the replica was added
+	Failures    int64  `json:"failures"`    // requests that failed since the replica was added
 }
 
 // Service defines the interface for interacting with the ML service.
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/samiransarii/inboXpert/common/utils"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/classifier"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/config"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/evaluation"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/handlers"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/rules"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/taxonomy"
+
+	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
+)
+
+// evaluate replays a labeled dataset through the categorization pipeline (rules, ML prediction,
+// and confidence thresholds) and reports per-category precision, recall, and F1, a confusion matrix,
+// and the calibration of the confidence scores. The database is not used, so nothing is stored
+// while evaluating.
+//
+// Example:
+//
+//	go run ./cmd/evaluate -rules rules.yaml -json -out report.json
+//	go run ./cmd/evaluate -stub -stub-category PERSONAL
+//	go run ./cmd/evaluate -model model.json
+func main() {
+	dataset := flag.String("dataset", "../ml_server/data/processed/detailed_labeled_emails.csv", "labeled CSV dataset to evaluate")
+	mlAddr := flag.String("ml-addr", "localhost:50055", "comma-separated addresses of the ML service replicas")
+	modelFile := flag.String("model", "", "embedded model file to evaluate instead of the ML service")
+	stub := flag.Bool("stub", false, "use a stub ML client that predicts -stub-category for every email")
+	stubCategory := flag.String("stub-category", "PERSONAL", "category predicted by the stub ML client")
+	stubConfidence := flag.Float64("stub-confidence", 0.5, "confidence of the stub ML client's predictions")
+	threshold := flag.Float64("threshold", utils.GetEnvAsFloat("CONFIDENCE_THRESHOLD", 0.5), "minimum confidence to accept a prediction without review")
+	categoryThresholds := flag.String("category-thresholds", utils.GetEnv("CATEGORY_THRESHOLDS", ""), "per-category thresholds, e.g. FINANCE=0.7,SHOPPING=0.4")
+	rulesFile := flag.String("rules", utils.GetEnv("RULES_FILE", ""), "YAML or JSON rules file applied around the ML prediction")
+	taxonomyFile := flag.String("taxonomy", utils.GetEnv("TAXONOMY_FILE", ""), "YAML or JSON category taxonomy (default: the ML categories)")
+	workers := flag.Int("workers", 10, "number of emails categorized concurrently")
+	retries := flag.Int("retries", 3, "attempts per email against the ML service")
+	buckets := flag.Int("buckets", 10, "number of confidence calibration buckets")
+	jsonOutput := flag.Bool("json", false, "write the report as JSON")
+	out := flag.String("out", "-", `output file, or "-" for standard output`)
+	flag.Parse()
+
+	examples, err := readDataset(*dataset)
+	if err != nil {
+		log.Fatalf("Failed to read dataset: %v", err)
+	}
+
+	var mlClient mlclient.Service
+	switch {
+	case *stub:
+		mlClient = &evaluation.StubClient{Category: *stubCategory, Confidence: float32(*stubConfidence)}
+	case *modelFile != "":
+		model, err := classifier.Load(*modelFile)
+		if err != nil {
+			log.Fatalf("Failed to load model: %v", err)
+		}
+		mlClient = classifier.NewClient(model)
+	default:
+		mlClient, err = mlclient.NewClient(mlclient.ClientConfig{Addresses: config.ParseList(*mlAddr)})
+		if err != nil {
+			log.Fatalf("Failed to create ML client: %v", err)
+		}
+	}
+	defer mlClient.Close()
+
+	ruleEngine, err := rules.LoadFile(*rulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load rules: %v", err)
+	}
+
+	tax, err := taxonomy.LoadFile(*taxonomyFile)
+	if err != nil {
+		log.Fatalf("Failed to load taxonomy: %v", err)
+	}
+
+	thresholds, err := config.ParseCategoryThresholds(*categoryThresholds)
+	if err != nil {
+		log.Fatalf("Invalid -category-thresholds: %v", err)
+	}
+
+	cfg := &models.Config{
+		NumWorkers:          *workers,
+		RetryAttempts:       *retries,
+		RulesFile:           *rulesFile,
+		TaxonomyFile:        *taxonomyFile,
+		ConfidenceThreshold: float32(*threshold),
+		CategoryThresholds:  thresholds,
+	}
+
+	// Predictions never touch the database, so the handler is created without repositories.
+	handler := handlers.NewCategorizationHandler(mlClient, cfg, nil, nil, ruleEngine, tax)
+
+	report := evaluation.Evaluate(context.Background(), handler, examples, evaluation.Options{
+		Workers: *workers,
+		Buckets: *buckets,
+	})
+
+	if err := write(*out, report, *jsonOutput); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+// readDataset reads the labeled examples of a CSV dataset.
+func readDataset(path string) ([]export.Example, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return export.ReadCSV(bufio.NewReader(file))
+}
+
+// write writes the report as text or JSON to the output file, or to standard output for "-".
+func write(out string, report *evaluation.Report, jsonOutput bool) error {
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if jsonOutput {
+		return evaluation.WriteJSON(w, report)
+	}
+	return evaluation.WriteText(w, report)
+}
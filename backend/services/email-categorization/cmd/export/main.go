@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/config"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/handlers"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// export writes the stored emails as a labeled training dataset in the format of the ML server's
+// detailed_labeled_emails.csv. Emails corrected by users are labeled with the corrected category,
+// and the remaining emails are labeled with their prediction if it is confident enough.
+//
+// Example:
+//
+//	go run ./cmd/export -out dataset.csv -from 2024-01-01 -per-category 500
+func main() {
+	out := flag.String("out", "-", `output file, or "-" for standard output`)
+	format := flag.String("format", "", "output format, csv or jsonl (default: inferred from -out, otherwise csv)")
+	minConfidence := flag.Float64("min-confidence", 0.8, "minimum confidence of uncorrected predictions to export")
+	from := flag.String("from", "", "export emails stored on or after this date (YYYY-MM-DD)")
+	to := flag.String("to", "", "export emails stored before this date (YYYY-MM-DD)")
+	perCategory := flag.Int("per-category", 0, "maximum number of examples per category, 0 for no limit")
+	seed := flag.Uint64("seed", 1, "seed for the per-category sampling")
+	flag.Parse()
+
+	outputFormat, err := resolveFormat(*format, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filter := models.LabeledEmailFilter{MinConfidence: float32(*minConfidence)}
+	if filter.From, err = parseDate(*from); err != nil {
+		log.Fatalf("Invalid -from date: %v", err)
+	}
+	if filter.To, err = parseDate(*to); err != nil {
+		log.Fatalf("Invalid -to date: %v", err)
+	}
+
+	cfg := config.New()
+	defer cfg.DBPool.Close()
+
+	emailRepo := handlers.NewEmailRepository(cfg.DBPool)
+	labeled, err := emailRepo.GetLabeledEmails(context.Background(), filter)
+	if err != nil {
+		log.Fatalf("Failed to load labeled emails: %v", err)
+	}
+
+	examples := export.BuildExamples(labeled, export.Options{
+		PerCategory: *perCategory,
+		Seed:        *seed,
+	})
+
+	if err := write(*out, outputFormat, examples); err != nil {
+		log.Fatalf("Failed to write dataset: %v", err)
+	}
+
+	log.Printf("Exported %d examples from %d labeled emails", len(examples), len(labeled))
+}
+
+// resolveFormat returns the output format, inferring it from the output file's extension
+// when no format is given.
+func resolveFormat(format, out string) (string, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(out), ".jsonl") {
+			return "jsonl", nil
+		}
+		return "csv", nil
+	}
+	if format != "csv" && format != "jsonl" {
+		return "", fmt.Errorf("unknown format %q, expected csv or jsonl", format)
+	}
+	return format, nil
+}
+
+// parseDate parses an optional YYYY-MM-DD date. An empty value yields no bound.
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// write writes the examples in the given format to the output file, or to standard output for "-".
+func write(out, format string, examples []export.Example) error {
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	buffered := bufio.NewWriter(w)
+	var err error
+	if format == "jsonl" {
+		err = export.WriteJSONL(buffered, examples)
+	} else {
+		err = export.WriteCSV(buffered, examples)
+	}
+	if err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/config"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/handlers"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/routing"
+)
+
+// modelreport compares the models emails were routed to, from the predictions stored while shadowing
+// or splitting traffic between models: how often every shadow model agrees with the model served in
+// its place, how confident every model is, and how often it agrees with the users' corrections.
+//
+// Example:
+//
+//	go run ./cmd/modelreport -from 2024-06-01
+//	go run ./cmd/modelreport -json -out report.json
+func main() {
+	from := flag.String("from", "", "compare predictions made on or after this date (YYYY-MM-DD)")
+	to := flag.String("to", "", "compare predictions made before this date (YYYY-MM-DD)")
+	buckets := flag.Int("buckets", 10, "number of confidence buckets")
+	jsonOutput := flag.Bool("json", false, "write the report as JSON")
+	out := flag.String("out", "-", `output file, or "-" for standard output`)
+	flag.Parse()
+
+	fromDate, err := parseDate(*from)
+	if err != nil {
+		log.Fatalf("Invalid -from date: %v", err)
+	}
+	toDate, err := parseDate(*to)
+	if err != nil {
+		log.Fatalf("Invalid -to date: %v", err)
+	}
+
+	cfg := config.New()
+	defer cfg.DBPool.Close()
+
+	predictionRepo := handlers.NewPredictionRepository(cfg.DBPool)
+	predictions, err := predictionRepo.GetPredictions(context.Background(), fromDate, toDate)
+	if err != nil {
+		log.Fatalf("Failed to load model predictions: %v", err)
+	}
+
+	report := routing.BuildReport(predictions, *buckets)
+	if err := write(*out, report, *jsonOutput); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+// parseDate parses an optional YYYY-MM-DD date. An empty value yields no bound.
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// write writes the report as text or JSON to the output file, or to standard output for "-".
+func write(out string, report *routing.Report, jsonOutput bool) error {
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if jsonOutput {
+		return routing.WriteJSON(w, report)
+	}
+	return routing.WriteText(w, report)
+}
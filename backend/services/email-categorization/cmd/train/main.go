@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/classifier"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+)
+
+// train fits the embedded Naive Bayes classifier on a labeled CSV dataset, in the format of the ML
+// server's detailed_labeled_emails.csv or of the export command, and writes the model to a JSON file
+// that the service loads with ML_BACKEND=embedded.
+//
+// Example:
+//
+//	go run ./cmd/train -out model.json -version nb-2024-06
+func main() {
+	dataset := flag.String("dataset", "../ml_server/data/processed/detailed_labeled_emails.csv", "labeled CSV dataset to train on")
+	out := flag.String("out", "model.json", "model file to write")
+	version := flag.String("version", "embedded-v1", "version recorded in the model")
+	maxFeatures := flag.Int("max-features", 1000, "number of terms in the vocabulary")
+	alpha := flag.Float64("alpha", 1, "additive smoothing of the term probabilities")
+	minConfidence := flag.Float64("min-confidence", 0, "minimum labeling confidence of the examples to train on")
+	flag.Parse()
+
+	examples, err := readDataset(*dataset)
+	if err != nil {
+		log.Fatalf("Failed to read dataset: %v", err)
+	}
+
+	// Leave out examples whose label itself was uncertain
+	kept := examples[:0]
+	for _, example := range examples {
+		if float64(example.Confidence) >= *minConfidence {
+			kept = append(kept, example)
+		}
+	}
+
+	model, err := classifier.Train(kept, classifier.Options{
+		MaxFeatures: *maxFeatures,
+		Alpha:       *alpha,
+		Version:     *version,
+	})
+	if err != nil {
+		log.Fatalf("Failed to train model: %v", err)
+	}
+
+	if err := model.Save(*out); err != nil {
+		log.Fatalf("Failed to write model: %v", err)
+	}
+	log.Printf("Trained model %s on %d examples of %d categories with %d terms, written to %s",
+		model.Version, model.Examples, len(model.Classes), len(model.Vocabulary), *out)
+}
+
+// readDataset reads the labeled examples of a CSV dataset.
+func readDataset(path string) ([]export.Example, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return export.ReadCSV(bufio.NewReader(file))
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// Options configures a caching Client.
+type Options struct {
+	// Size is the number of predictions kept in the in-process LRU.
+	Size int
+	// TTL is how long a prediction stays cached.
+	TTL time.Duration
+	// ModelVersion identifies the model behind the ML service. Predictions are cached per
+	// model version, so changing it invalidates everything cached before.
+	ModelVersion string
+	// Store is the optional persistent tier consulted when the LRU misses. Nil disables it.
+	Store *PostgresStore
+}
+
+// Stats counts how ML predictions were served by a Client.
+type Stats struct {
+	Hits           int64 `json:"hits"`            // served from the in-process LRU
+	PersistentHits int64 `json:"persistent_hits"` // served from the persistent tier
+	Misses         int64 `json:"misses"`          // sent to the ML service
+}
+
+// Client wraps an ML client with a result cache keyed by the normalized content of the email
+// (see Key), so near-identical emails such as newsletters and notifications are only sent to
+// the ML service once. Predictions are looked up in an in-process LRU first and then in the
+// optional persistent tier. Only successful predictions are cached.
+// Client implements mlclient.Service and is safe for concurrent use.
+type Client struct {
+	next         mlclient.Service
+	lru          *LRU
+	store        *PostgresStore
+	ttl          time.Duration
+	modelVersion string
+
+	hits           atomic.Int64
+	persistentHits atomic.Int64
+	misses         atomic.Int64
+}
+
+// NewClient wraps the ML client next with a result cache configured by opts.
+func NewClient(next mlclient.Service, opts Options) *Client {
+	return &Client{
+		next:         next,
+		lru:          NewLRU(opts.Size),
+		store:        opts.Store,
+		ttl:          opts.TTL,
+		modelVersion: opts.ModelVersion,
+	}
+}
+
+// hitKey is the context key of the flag WithHit sets when a prediction is served from the cache.
+type hitKey struct{}
+
+// WithHit returns a context under which CategorizeEmail sets *hit to true when it serves the
+// prediction from the cache, so callers can tell cached predictions apart.
+func WithHit(ctx context.Context, hit *bool) context.Context {
+	return context.WithValue(ctx, hitKey{}, hit)
+}
+
+// CategorizeEmail returns the cached prediction for the email if there is one, and otherwise
+// asks the ML service and caches its prediction. The returned prediction always carries the
+// ID of the requested email.
+// Under a context from WithHit, a cache hit is reported as well.
+func (c *Client) CategorizeEmail(ctx context.Context, email *mlpb.EmailRequest) (*mlpb.CategoryResponse, error) {
+	key := Key(email, c.modelVersion)
+	if response, ok := c.lookup(ctx, key); ok {
+		if hit, ok := ctx.Value(hitKey{}).(*bool); ok {
+			*hit = true
+		}
+		return withID(response, email.Id), nil
+	}
+
+	c.misses.Add(1)
+	response, err := c.next.CategorizeEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	c.save(ctx, key, response)
+	return response, nil
+}
+
+// BatchCategorizeEmails serves the cached predictions of a batch from the cache and sends only
+// the remaining emails to the ML service, in a single batch. The results keep the order of the emails.
+func (c *Client) BatchCategorizeEmails(ctx context.Context, emails []*mlpb.EmailRequest) (*mlpb.BatchCategoryResponse, error) {
+	results := make([]*mlpb.CategoryResponse, len(emails))
+	keys := make([]string, len(emails))
+
+	var missing []int
+	for i, email := range emails {
+		keys[i] = Key(email, c.modelVersion)
+		if response, ok := c.lookup(ctx, keys[i]); ok {
+			results[i] = withID(response, email.Id)
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	if len(missing) > 0 {
+		c.misses.Add(int64(len(missing)))
+
+		batch := make([]*mlpb.EmailRequest, len(missing))
+		for j, i := range missing {
+			batch[j] = emails[i]
+		}
+		response, err := c.next.BatchCategorizeEmails(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Results) != len(batch) {
+			return nil, fmt.Errorf("ML service returned %d results for %d emails", len(response.Results), len(batch))
+		}
+
+		for j, i := range missing {
+			results[i] = response.Results[j]
+			c.save(ctx, keys[i], response.Results[j])
+		}
+	}
+
+	return &mlpb.BatchCategoryResponse{Results: results}, nil
+}
+
+// Close closes the wrapped ML client.
+func (c *Client) Close() error {
+	return c.next.Close()
+}
+
+// Stats returns how many predictions were served from each cache tier and how many missed.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Hits:           c.hits.Load(),
+		PersistentHits: c.persistentHits.Load(),
+		Misses:         c.misses.Load(),
+	}
+}
+
+// lookup returns the prediction cached under key from the LRU or, failing that, from the
+// persistent tier, which then also refills the LRU. Errors of the persistent tier are logged
+// and treated as misses.
+func (c *Client) lookup(ctx context.Context, key string) (*mlpb.CategoryResponse, bool) {
+	now := time.Now()
+	if response, ok := c.lru.Get(key, now); ok {
+		c.hits.Add(1)
+		return response, true
+	}
+	if c.store == nil {
+		return nil, false
+	}
+
+	response, expiresAt, ok, err := c.store.Get(ctx, key, now)
+	if err != nil {
+		log.Printf("Failed to read prediction cache: %v", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	c.persistentHits.Add(1)
+	c.lru.Put(key, response, expiresAt)
+	return response, true
+}
+
+// save caches a prediction under key in every tier. Errors of the persistent tier are logged,
+// since the prediction itself succeeded.
+func (c *Client) save(ctx context.Context, key string, response *mlpb.CategoryResponse) {
+	cached := proto.Clone(response).(*mlpb.CategoryResponse)
+	expiresAt := time.Now().Add(c.ttl)
+
+	c.lru.Put(key, cached, expiresAt)
+	if c.store == nil {
+		return
+	}
+	if err := c.store.Put(ctx, key, c.modelVersion, cached, expiresAt); err != nil {
+		log.Printf("Failed to write prediction cache: %v", err)
+	}
+}
+
+// withID returns a copy of a cached prediction carrying the ID of the email it is returned for,
+// since the prediction may have been made for a different email with the same content.
+func withID(response *mlpb.CategoryResponse, id string) *mlpb.CategoryResponse {
+	clone := proto.Clone(response).(*mlpb.CategoryResponse)
+	clone.Id = id
+	return clone
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+var (
+	// digitRuns matches numbers, which usually differ between otherwise identical notifications
+	// (order numbers, amounts, dates, verification codes) without changing their category.
+	digitRuns = regexp.MustCompile(`[0-9]+`)
+
+	// whitespaceRuns matches any run of whitespace, so re-wrapped text hashes the same.
+	whitespaceRuns = regexp.MustCompile(`\s+`)
+)
+
+// Key returns the cache key of an email for the given model version: a SHA-256 hash of the
+// sender's domain and the normalized subject and body. Emails from the same domain whose subject
+// and body only differ in case, whitespace, or numbers share the same key. Including the model
+// version means results of a previous model are never returned after the model changes.
+func Key(email *mlpb.EmailRequest, modelVersion string) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		modelVersion,
+		senderDomain(email.Sender),
+		normalize(email.Subject),
+		normalize(email.Body),
+	} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// senderDomain returns the lowercased domain of a sender address such as
+// "News <news@example.com>", or the whole lowercased sender if it has no domain.
+func senderDomain(sender string) string {
+	sender = strings.ToLower(strings.TrimSpace(sender))
+	if at := strings.LastIndexByte(sender, '@'); at >= 0 {
+		sender = sender[at+1:]
+	}
+	return strings.TrimRight(sender, "> ")
+}
+
+// normalize lowercases text, replaces numbers with a placeholder, and collapses whitespace.
+func normalize(text string) string {
+	text = strings.ToLower(text)
+	text = digitRuns.ReplaceAllString(text, "#")
+	text = whitespaceRuns.ReplaceAllString(text, " ")
+	return strings.TrimSpace(text)
+}
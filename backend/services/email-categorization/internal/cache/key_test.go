@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"testing"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+func TestKey(t *testing.T) {
+	email := &mlpb.EmailRequest{
+		Sender:  "Shop <orders@shop.example.com>",
+		Subject: "Your order 10423 has shipped",
+		Body:    "Order 10423 of $59.90 ships on 2024-03-10.\nTrack it with code 88213.",
+	}
+
+	tests := []struct {
+		name         string
+		email        *mlpb.EmailRequest
+		modelVersion string
+		same         bool
+	}{
+		{
+			name: "numbers differ",
+			email: &mlpb.EmailRequest{
+				Sender:  email.Sender,
+				Subject: "Your order 9 has shipped",
+				Body:    "Order 9 of $1234.00 ships on 2025-12-01.\nTrack it with code 4.",
+			},
+			modelVersion: "v1",
+			same:         true,
+		},
+		{
+			name: "case and whitespace differ",
+			email: &mlpb.EmailRequest{
+				Sender:  email.Sender,
+				Subject: "  YOUR ORDER 10423 HAS SHIPPED",
+				Body:    "Order 10423 of $59.90\tships on 2024-03-10. Track   it with code 88213.\n",
+			},
+			modelVersion: "v1",
+			same:         true,
+		},
+		{
+			name: "sender differs within the domain",
+			email: &mlpb.EmailRequest{
+				Sender:  "NO-REPLY@SHOP.EXAMPLE.COM",
+				Subject: email.Subject,
+				Body:    email.Body,
+			},
+			modelVersion: "v1",
+			same:         true,
+		},
+		{
+			name: "sender domain differs",
+			email: &mlpb.EmailRequest{
+				Sender:  "Shop <orders@other.example.com>",
+				Subject: email.Subject,
+				Body:    email.Body,
+			},
+			modelVersion: "v1",
+		},
+		{
+			name: "words differ",
+			email: &mlpb.EmailRequest{
+				Sender:  email.Sender,
+				Subject: "Your order 10423 was cancelled",
+				Body:    email.Body,
+			},
+			modelVersion: "v1",
+		},
+		{
+			name:         "model version differs",
+			email:        email,
+			modelVersion: "v2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			same := Key(test.email, test.modelVersion) == Key(email, "v1")
+			if same != test.same {
+				t.Errorf("same key = %t, want %t", same, test.same)
+			}
+		})
+	}
+}
+
+func TestSenderDomain(t *testing.T) {
+	tests := []struct {
+		sender string
+		want   string
+	}{
+		{sender: "news@example.com", want: "example.com"},
+		{sender: "News <News@Example.COM>", want: "example.com"},
+		{sender: `"a@b" <news@mail.example.com> `, want: "mail.example.com"},
+		{sender: "Example Newsletter", want: "example newsletter"},
+		{sender: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.sender, func(t *testing.T) {
+			if got := senderDomain(test.sender); got != test.want {
+				t.Errorf("senderDomain(%q) = %q, want %q", test.sender, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Your code is 482913", want: "your code is #"},
+		{text: "Order #10423: $59.90 on 2024-03-10", want: "order ##: $#.# on #-#-#"},
+		{text: "  Hello\n\n\tWorld  ", want: "hello world"},
+		{text: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := normalize(test.text); got != test.want {
+				t.Errorf("normalize(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
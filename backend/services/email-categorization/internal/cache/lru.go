@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// LRU is an in-process, size-bounded cache of ML predictions. When it is full, the least recently
+// used prediction is evicted. It is safe for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+}
+
+// lruEntry is a cached prediction and the time it expires at.
+type lruEntry struct {
+	key       string
+	response  *mlpb.CategoryResponse
+	expiresAt time.Time
+}
+
+// NewLRU creates an LRU holding at most capacity predictions.
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: max(capacity, 1),
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// Get returns the prediction cached under key, unless it is missing or has expired.
+func (c *LRU) Get(key string, now time.Time) (*mlpb.CategoryResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.response, true
+}
+
+// Put caches a prediction under key until expiresAt, evicting the least recently used
+// prediction if the cache is full.
+func (c *LRU) Put(key string, response *mlpb.CategoryResponse, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*lruEntry)
+		entry.response = response
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, response: response, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached predictions, including expired ones not yet evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
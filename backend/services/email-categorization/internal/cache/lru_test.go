@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// now is the fixed time the cache is used at.
+var now = time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+
+func TestLRU(t *testing.T) {
+	// A step puts a prediction under put, or gets the prediction under get, at the given time.
+	type step struct {
+		at       time.Duration
+		put      string
+		ttl      time.Duration
+		get      string
+		wantHit  bool
+		wantKeys []string // cached keys after the step, most recently used first
+	}
+	tests := []struct {
+		name     string
+		capacity int
+		steps    []step
+	}{
+		{
+			name:     "evicts the least recently put",
+			capacity: 2,
+			steps: []step{
+				{put: "a", ttl: time.Hour, wantKeys: []string{"a"}},
+				{put: "b", ttl: time.Hour, wantKeys: []string{"b", "a"}},
+				{put: "c", ttl: time.Hour, wantKeys: []string{"c", "b"}},
+				{get: "a", wantKeys: []string{"c", "b"}},
+			},
+		},
+		{
+			name:     "evicts the least recently used",
+			capacity: 2,
+			steps: []step{
+				{put: "a", ttl: time.Hour, wantKeys: []string{"a"}},
+				{put: "b", ttl: time.Hour, wantKeys: []string{"b", "a"}},
+				{get: "a", wantHit: true, wantKeys: []string{"a", "b"}},
+				{put: "c", ttl: time.Hour, wantKeys: []string{"c", "a"}},
+				{get: "b", wantKeys: []string{"c", "a"}},
+			},
+		},
+		{
+			name:     "replacing a prediction does not evict",
+			capacity: 2,
+			steps: []step{
+				{put: "a", ttl: time.Hour, wantKeys: []string{"a"}},
+				{put: "b", ttl: time.Hour, wantKeys: []string{"b", "a"}},
+				{put: "a", ttl: time.Hour, wantKeys: []string{"a", "b"}},
+				{get: "b", wantHit: true, wantKeys: []string{"b", "a"}},
+			},
+		},
+		{
+			name:     "expires predictions after their TTL",
+			capacity: 2,
+			steps: []step{
+				{put: "a", ttl: time.Minute, wantKeys: []string{"a"}},
+				{put: "b", ttl: time.Hour, wantKeys: []string{"b", "a"}},
+				{at: 59 * time.Second, get: "a", wantHit: true, wantKeys: []string{"a", "b"}},
+				{at: time.Minute, get: "a", wantKeys: []string{"b"}},
+				{at: time.Minute, get: "b", wantHit: true, wantKeys: []string{"b"}},
+			},
+		},
+		{
+			name:     "replacing a prediction extends its TTL",
+			capacity: 2,
+			steps: []step{
+				{put: "a", ttl: time.Minute, wantKeys: []string{"a"}},
+				{at: 30 * time.Second, put: "a", ttl: time.Minute, wantKeys: []string{"a"}},
+				{at: time.Minute, get: "a", wantHit: true, wantKeys: []string{"a"}},
+			},
+		},
+		{
+			name:     "holds at least one prediction",
+			capacity: 0,
+			steps: []step{
+				{put: "a", ttl: time.Hour, wantKeys: []string{"a"}},
+				{put: "b", ttl: time.Hour, wantKeys: []string{"b"}},
+				{get: "b", wantHit: true, wantKeys: []string{"b"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lru := NewLRU(test.capacity)
+			for i, step := range test.steps {
+				at := now.Add(step.at)
+				if step.put != "" {
+					lru.Put(step.put, &mlpb.CategoryResponse{Category: step.put}, at.Add(step.ttl))
+				} else {
+					response, hit := lru.Get(step.get, at)
+					if hit != step.wantHit {
+						t.Errorf("step %d: Get(%q) hit = %t, want %t", i, step.get, hit, step.wantHit)
+					}
+					if hit && response.Category != step.get {
+						t.Errorf("step %d: Get(%q) returned the prediction of %q", i, step.get, response.Category)
+					}
+				}
+
+				if keys := lruKeys(lru); !slices.Equal(keys, step.wantKeys) {
+					t.Errorf("step %d: cached keys = %v, want %v", i, keys, step.wantKeys)
+				}
+				if lru.Len() != len(step.wantKeys) {
+					t.Errorf("step %d: Len() = %d, want %d", i, lru.Len(), len(step.wantKeys))
+				}
+			}
+		})
+	}
+}
+
+// lruKeys returns the keys cached in an LRU, most recently used first.
+func lruKeys(lru *LRU) []string {
+	var keys []string
+	for element := lru.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*lruEntry).key)
+	}
+	return keys
+}
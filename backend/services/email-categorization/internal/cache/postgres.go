@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/proto"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// predictionCacheSchema creates the table of cached ML predictions if it does not exist yet.
+// Predictions are stored as serialized protobuf messages, along with the model version that
+// produced them.
+const predictionCacheSchema = `
+	CREATE TABLE IF NOT EXISTS ml_prediction_cache (
+		key TEXT PRIMARY KEY,
+		model_version TEXT NOT NULL,
+		response BYTEA NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	)
+`
+
+// PostgresStore is a persistent cache tier of ML predictions shared by every instance of the
+// service and kept across restarts.
+type PostgresStore struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewPostgresStore creates a new instance of PostgresStore with the given database connection pool.
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{DB: db}
+}
+
+// EnsureSchema creates the ml_prediction_cache table if it does not exist yet.
+func (s *PostgresStore) EnsureSchema(ctx context.Context) error {
+	if _, err := s.DB.Exec(ctx, predictionCacheSchema); err != nil {
+		log.Printf("Failed to create ml_prediction_cache table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Purge deletes every cached prediction that has expired or was made by a model version other
+// than modelVersion, and returns the number of deleted predictions.
+func (s *PostgresStore) Purge(ctx context.Context, modelVersion string) (int64, error) {
+	tag, err := s.DB.Exec(ctx, `
+		DELETE FROM ml_prediction_cache
+		WHERE model_version <> $1 OR expires_at <= $2
+	`, modelVersion, time.Now())
+	if err != nil {
+		log.Printf("Failed to purge prediction cache: %v", err)
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Get returns the prediction cached under key, unless it is missing or has expired.
+func (s *PostgresStore) Get(ctx context.Context, key string, now time.Time) (*mlpb.CategoryResponse, time.Time, bool, error) {
+	var data []byte
+	var expiresAt time.Time
+	err := s.DB.QueryRow(ctx, `
+		SELECT response, expires_at FROM ml_prediction_cache
+		WHERE key = $1 AND expires_at > $2
+	`, key, now).Scan(&data, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	response := &mlpb.CategoryResponse{}
+	if err := proto.Unmarshal(data, response); err != nil {
+		return nil, time.Time{}, false, err
+	}
+	return response, expiresAt, true, nil
+}
+
+// Put caches a prediction made by the given model version under key until expiresAt,
+// replacing any prediction already cached under the same key.
+func (s *PostgresStore) Put(ctx context.Context, key, modelVersion string, response *mlpb.CategoryResponse, expiresAt time.Time) error {
+	data, err := proto.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.DB.Exec(ctx, `
+		INSERT INTO ml_prediction_cache (key, model_version, response, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			model_version = EXCLUDED.model_version,
+			response = EXCLUDED.response,
+			expires_at = EXCLUDED.expires_at
+	`, key, modelVersion, data, expiresAt)
+	return err
+}
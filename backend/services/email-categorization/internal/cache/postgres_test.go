@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// testStore returns a PostgresStore on the database at TEST_DATABASE_URL, in a schema of its own
+// dropped when the test ends. The test is skipped if TEST_DATABASE_URL is not set.
+func testStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("cache_test_%d", time.Now().UnixNano())
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(db.Close)
+	if _, err := db.Exec(ctx, "CREATE SCHEMA "+pgx.Identifier{schema}.Sanitize()); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(ctx, "DROP SCHEMA "+pgx.Identifier{schema}.Sanitize()+" CASCADE")
+	})
+
+	store := NewPostgresStore(db)
+	if err := store.EnsureSchema(ctx); err != nil {
+		t.Fatalf("EnsureSchema() error = %v", err)
+	}
+	return store
+}
+
+func TestPostgresStorePurge(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+	current := time.Now()
+
+	entries := []struct {
+		key          string
+		modelVersion string
+		expiresAt    time.Time
+		kept         bool
+	}{
+		{key: "current", modelVersion: "v2", expiresAt: current.Add(time.Hour), kept: true},
+		{key: "previous-model", modelVersion: "v1", expiresAt: current.Add(time.Hour)},
+		{key: "unversioned", modelVersion: "", expiresAt: current.Add(time.Hour)},
+		{key: "expired", modelVersion: "v2", expiresAt: current.Add(-time.Minute)},
+	}
+	for _, entry := range entries {
+		response := &mlpb.CategoryResponse{Category: entry.key}
+		if err := store.Put(ctx, entry.key, entry.modelVersion, response, entry.expiresAt); err != nil {
+			t.Fatalf("Put(%q) error = %v", entry.key, err)
+		}
+	}
+
+	purged, err := store.Purge(ctx, "v2")
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if purged != 3 {
+		t.Errorf("Purge() = %d, want 3", purged)
+	}
+
+	for _, entry := range entries {
+		var count int
+		if err := store.DB.QueryRow(ctx, "SELECT count(*) FROM ml_prediction_cache WHERE key = $1", entry.key).Scan(&count); err != nil {
+			t.Fatalf("failed to count %q: %v", entry.key, err)
+		}
+		if kept := count == 1; kept != entry.kept {
+			t.Errorf("prediction %q kept = %t, want %t", entry.key, kept, entry.kept)
+		}
+	}
+
+	response, _, ok, err := store.Get(ctx, "current", current)
+	if err != nil || !ok || response.Category != "current" {
+		t.Errorf("Get() of the kept prediction = %v, %t, %v, want it returned", response, ok, err)
+	}
+}
+
+func TestPostgresStoreGet(t *testing.T) {
+	store := testStore(t)
+	ctx := context.Background()
+	current := time.Now().Truncate(time.Microsecond)
+
+	expiresAt := current.Add(time.Minute)
+	if err := store.Put(ctx, "a", "v1", &mlpb.CategoryResponse{Category: "first"}, current); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := store.Put(ctx, "a", "v1", &mlpb.CategoryResponse{Category: "second"}, expiresAt); err != nil {
+		t.Fatalf("Put() replacing a prediction error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		at   time.Time
+		want string
+	}{
+		{name: "returns the latest prediction", key: "a", at: current, want: "second"},
+		{name: "misses expired predictions", key: "a", at: expiresAt},
+		{name: "misses unknown keys", key: "b", at: current},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, gotExpiresAt, ok, err := store.Get(ctx, test.key, test.at)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if ok != (test.want != "") {
+				t.Fatalf("Get() hit = %t, want %t", ok, test.want != "")
+			}
+			if ok && (response.Category != test.want || !gotExpiresAt.Equal(expiresAt)) {
+				t.Errorf("Get() = %q until %v, want %q until %v", response.Category, gotExpiresAt, test.want, expiresAt)
+			}
+		})
+	}
+}
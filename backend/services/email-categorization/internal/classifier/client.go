@@ -0,0 +1,74 @@
+package classifier
+
+import (
+	"context"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+const (
+	// keywordCount is the number of keywords returned with every prediction.
+	keywordCount = 5
+
+	// modelName is the name every prediction reports its model under, along with the model's version.
+	modelName = "naive_bayes"
+)
+
+// Client implements mlclient.Service by classifying emails in-process with a Model, so the service
+// can run without the Python ML server. It is safe for concurrent use.
+type Client struct {
+	model *Model
+}
+
+// NewClient creates a Client predicting with the given model.
+func NewClient(model *Model) *Client {
+	return &Client{model: model}
+}
+
+// CategorizeEmail classifies a single email.
+func (c *Client) CategorizeEmail(ctx context.Context, email *mlpb.EmailRequest) (*mlpb.CategoryResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prediction := c.model.Predict(email.Subject+" "+email.Body, keywordCount)
+	alternatives := make([]*mlpb.AlternativeCategory, len(prediction.Alternatives))
+	for i, alt := range prediction.Alternatives {
+		alternatives[i] = &mlpb.AlternativeCategory{
+			Category:    alt.Category,
+			Confindence: alt.ConfidenceScore,
+		}
+	}
+	return &mlpb.CategoryResponse{
+		Id:           email.Id,
+		Category:     prediction.Category,
+		Confidence:   prediction.Confidence,
+		Keywords:     prediction.Keywords,
+		Alternatives: alternatives,
+		ModelName:    modelName,
+		ModelVersion: c.model.Version,
+	}, nil
+}
+
+// BatchCategorizeEmails classifies every email of a batch, keeping their order.
+func (c *Client) BatchCategorizeEmails(ctx context.Context, emails []*mlpb.EmailRequest) (*mlpb.BatchCategoryResponse, error) {
+	results := make([]*mlpb.CategoryResponse, len(emails))
+	for i, email := range emails {
+		result, err := c.CategorizeEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return &mlpb.BatchCategoryResponse{Results: results}, nil
+}
+
+// Close does nothing, since the model holds no resources.
+func (c *Client) Close() error {
+	return nil
+}
+
+// Version returns the version of the model.
+func (c *Client) Version() string {
+	return c.model.Version
+}
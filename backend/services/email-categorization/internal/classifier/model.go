@@ -0,0 +1,304 @@
+package classifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// formatVersion is the version of the model file format written by Save.
+const formatVersion = 1
+
+// Options configures the training of a Model. Zero values fall back to the settings of the
+// ML server's TfidfVectorizer and MultinomialNB.
+type Options struct {
+	// MaxFeatures is the number of terms in the vocabulary, the most frequent ones in the dataset.
+	MaxFeatures int
+	// Alpha is the additive smoothing of the term probabilities.
+	Alpha float64
+	// Version identifies the trained model, e.g. for the prediction cache.
+	Version string
+}
+
+// Model is a Multinomial Naive Bayes classifier over TF-IDF features of unigrams and bigrams,
+// the same kind of model the ML server trains with scikit-learn. It is stored as a JSON file,
+// so it can be trained once and shipped with the service. A Model is safe for concurrent use,
+// since it is never modified after training or loading.
+type Model struct {
+	FormatVersion  int         `json:"format_version"`
+	Version        string      `json:"version"`
+	TrainedAt      time.Time   `json:"trained_at"`
+	Examples       int         `json:"examples"`
+	Classes        []string    `json:"classes"`
+	ClassLogPrior  []float64   `json:"class_log_prior"`
+	Vocabulary     []string    `json:"vocabulary"`
+	IDF            []float64   `json:"idf"`
+	FeatureLogProb [][]float64 `json:"feature_log_prob"` // per class, per vocabulary term
+
+	index map[string]int // position of every vocabulary term
+}
+
+// Prediction is the outcome of classifying an email: the most likely category and its probability,
+// the other categories ranked by probability, and the terms of the email that most favored the category.
+type Prediction struct {
+	Category     string
+	Confidence   float32
+	Alternatives []models.Alternative
+	Keywords     []string
+}
+
+// Train fits a Model on labeled examples, using the subject and body of every example as its text.
+func Train(examples []export.Example, opts Options) (*Model, error) {
+	if opts.MaxFeatures <= 0 {
+		opts.MaxFeatures = 1000
+	}
+	if opts.Alpha <= 0 {
+		opts.Alpha = 1
+	}
+
+	// Tokenize every example and count the frequency and document frequency of its terms
+	documents := make([][]string, 0, len(examples))
+	labels := make([]string, 0, len(examples))
+	frequency := make(map[string]int)
+	documentFrequency := make(map[string]int)
+	for _, example := range examples {
+		if example.Category == "" {
+			continue
+		}
+		documentTerms := terms(example.Subject + " " + example.Body)
+		documents = append(documents, documentTerms)
+		labels = append(labels, example.Category)
+
+		seen := make(map[string]bool)
+		for _, term := range documentTerms {
+			frequency[term]++
+			if !seen[term] {
+				seen[term] = true
+				documentFrequency[term]++
+			}
+		}
+	}
+	if len(documents) == 0 {
+		return nil, errors.New("no labeled examples to train on")
+	}
+
+	// Keep the most frequent terms, breaking ties alphabetically so training is deterministic
+	vocabulary := make([]string, 0, len(frequency))
+	for term := range frequency {
+		vocabulary = append(vocabulary, term)
+	}
+	sort.Slice(vocabulary, func(i, j int) bool {
+		if frequency[vocabulary[i]] != frequency[vocabulary[j]] {
+			return frequency[vocabulary[i]] > frequency[vocabulary[j]]
+		}
+		return vocabulary[i] < vocabulary[j]
+	})
+	vocabulary = vocabulary[:min(len(vocabulary), opts.MaxFeatures)]
+	sort.Strings(vocabulary)
+
+	model := &Model{
+		FormatVersion: formatVersion,
+		Version:       opts.Version,
+		TrainedAt:     time.Now().UTC(),
+		Examples:      len(documents),
+		Vocabulary:    vocabulary,
+		IDF:           make([]float64, len(vocabulary)),
+	}
+	model.buildIndex()
+
+	// Smoothed inverse document frequency, as computed by scikit-learn
+	n := float64(len(documents))
+	for i, term := range vocabulary {
+		model.IDF[i] = math.Log((1+n)/(1+float64(documentFrequency[term]))) + 1
+	}
+
+	// Sum the TF-IDF vectors of every class
+	classIndex := make(map[string]int)
+	var classCounts []int
+	var featureCounts [][]float64
+	for i, documentTerms := range documents {
+		c, exists := classIndex[labels[i]]
+		if !exists {
+			c = len(model.Classes)
+			classIndex[labels[i]] = c
+			model.Classes = append(model.Classes, labels[i])
+			classCounts = append(classCounts, 0)
+			featureCounts = append(featureCounts, make([]float64, len(vocabulary)))
+		}
+		classCounts[c]++
+		for term, weight := range model.vectorize(documentTerms) {
+			featureCounts[c][term] += weight
+		}
+	}
+
+	// Turn the counts into smoothed log probabilities
+	model.ClassLogPrior = make([]float64, len(model.Classes))
+	model.FeatureLogProb = make([][]float64, len(model.Classes))
+	for c := range model.Classes {
+		model.ClassLogPrior[c] = math.Log(float64(classCounts[c]) / n)
+
+		total := opts.Alpha * float64(len(vocabulary))
+		for _, count := range featureCounts[c] {
+			total += count
+		}
+		model.FeatureLogProb[c] = make([]float64, len(vocabulary))
+		for term, count := range featureCounts[c] {
+			model.FeatureLogProb[c][term] = math.Log((count + opts.Alpha) / total)
+		}
+	}
+
+	return model, nil
+}
+
+// Load reads a model written by Save.
+func Load(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model file: %w", err)
+	}
+
+	var model Model
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("failed to parse model file: %w", err)
+	}
+	if model.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("unsupported model format version %d", model.FormatVersion)
+	}
+	if len(model.Classes) == 0 || len(model.ClassLogPrior) != len(model.Classes) ||
+		len(model.FeatureLogProb) != len(model.Classes) || len(model.IDF) != len(model.Vocabulary) {
+		return nil, errors.New("model file is inconsistent")
+	}
+	for _, logProb := range model.FeatureLogProb {
+		if len(logProb) != len(model.Vocabulary) {
+			return nil, errors.New("model file is inconsistent")
+		}
+	}
+
+	model.buildIndex()
+	return &model, nil
+}
+
+// Save writes the model to a JSON file.
+func (m *Model) Save(path string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Predict classifies the text of an email, returning up to keywords terms that explain the prediction.
+func (m *Model) Predict(text string, keywords int) Prediction {
+	features := m.vectorize(terms(text))
+
+	// Joint log likelihood of every class, turned into probabilities with a stable softmax
+	scores := make([]float64, len(m.Classes))
+	best := 0
+	for c := range m.Classes {
+		scores[c] = m.ClassLogPrior[c]
+		for term, weight := range features {
+			scores[c] += weight * m.FeatureLogProb[c][term]
+		}
+		if scores[c] > scores[best] {
+			best = c
+		}
+	}
+	var sum float64
+	for c := range scores {
+		scores[c] = math.Exp(scores[c] - scores[best])
+		sum += scores[c]
+	}
+
+	prediction := Prediction{
+		Category:   m.Classes[best],
+		Confidence: float32(scores[best] / sum),
+	}
+	for c, class := range m.Classes {
+		if c != best {
+			prediction.Alternatives = append(prediction.Alternatives, models.Alternative{
+				Category:        class,
+				ConfidenceScore: float32(scores[c] / sum),
+			})
+		}
+	}
+	sort.SliceStable(prediction.Alternatives, func(i, j int) bool {
+		return prediction.Alternatives[i].ConfidenceScore > prediction.Alternatives[j].ConfidenceScore
+	})
+	prediction.Keywords = m.keywords(features, best, keywords)
+
+	return prediction
+}
+
+// keywords returns up to limit terms of an email that favor class the most over the other classes,
+// weighted by how much they occur in the email.
+func (m *Model) keywords(features map[int]float64, class, limit int) []string {
+	type contribution struct {
+		term  int
+		score float64
+	}
+	var contributions []contribution
+	for term, weight := range features {
+		var others float64
+		for c := range m.Classes {
+			if c != class {
+				others += m.FeatureLogProb[c][term]
+			}
+		}
+		if len(m.Classes) > 1 {
+			others /= float64(len(m.Classes) - 1)
+		}
+		if score := weight * (m.FeatureLogProb[class][term] - others); score > 0 {
+			contributions = append(contributions, contribution{term: term, score: score})
+		}
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if contributions[i].score != contributions[j].score {
+			return contributions[i].score > contributions[j].score
+		}
+		return contributions[i].term < contributions[j].term
+	})
+
+	keywords := make([]string, 0, min(limit, len(contributions)))
+	for _, c := range contributions[:min(limit, len(contributions))] {
+		keywords = append(keywords, m.Vocabulary[c.term])
+	}
+	return keywords
+}
+
+// vectorize returns the L2-normalized TF-IDF vector of a tokenized document, keyed by vocabulary position.
+func (m *Model) vectorize(documentTerms []string) map[int]float64 {
+	vector := make(map[int]float64)
+	for _, term := range documentTerms {
+		if i, exists := m.index[term]; exists {
+			vector[i]++
+		}
+	}
+
+	var norm float64
+	for i, count := range vector {
+		vector[i] = count * m.IDF[i]
+		norm += vector[i] * vector[i]
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// buildIndex maps every vocabulary term to its position.
+func (m *Model) buildIndex() {
+	m.index = make(map[string]int, len(m.Vocabulary))
+	for i, term := range m.Vocabulary {
+		m.index[term] = i
+	}
+}
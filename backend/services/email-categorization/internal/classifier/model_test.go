@@ -0,0 +1,174 @@
+package classifier
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+)
+
+// fixture is a small labeled dataset with distinct vocabularies for every category.
+var fixture = []export.Example{
+	{Subject: "Your invoice is ready", Body: "Please find the invoice attached. Payment is due next week.", Category: "FINANCE"},
+	{Subject: "Payment received", Body: "We received your payment for the invoice. Thank you.", Category: "FINANCE"},
+	{Subject: "Bank statement available", Body: "Your monthly bank statement and account balance are available.", Category: "FINANCE"},
+	{Subject: "Flash sale today", Body: "Huge discount on shoes. Shop the sale now and save big.", Category: "PROMOTIONS"},
+	{Subject: "Exclusive discount code", Body: "Use this discount code to save on your next order. Shop now.", Category: "PROMOTIONS"},
+	{Subject: "Weekend sale", Body: "Everything in the store is on sale this weekend. Save today.", Category: "PROMOTIONS"},
+	{Subject: "Meeting tomorrow", Body: "Can we move the project meeting to tomorrow afternoon with the team?", Category: "WORK"},
+	{Subject: "Project deadline", Body: "The project deadline is Friday. Please send the report to the team.", Category: "WORK"},
+	{Subject: "Team standup notes", Body: "Notes from the team standup meeting about the project roadmap.", Category: "WORK"},
+	{Subject: "Unlabeled", Body: "This example has no category and is skipped."},
+}
+
+func TestTrainPredict(t *testing.T) {
+	model, err := Train(fixture, Options{Version: "test"})
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+
+	if model.Examples != 9 {
+		t.Errorf("Examples = %d, want 9, skipping the unlabeled example", model.Examples)
+	}
+	if want := []string{"FINANCE", "PROMOTIONS", "WORK"}; !reflect.DeepEqual(model.Classes, want) {
+		t.Errorf("Classes = %v, want %v", model.Classes, want)
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		category string
+		keyword  string
+	}{
+		{name: "finance", text: "Invoice payment due", category: "FINANCE", keyword: "invoice"},
+		{name: "promotions", text: "Big sale, use the discount code", category: "PROMOTIONS", keyword: "discount"},
+		{name: "work", text: "Agenda for the project meeting", category: "WORK", keyword: "project"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			prediction := model.Predict(test.text, 3)
+
+			if prediction.Category != test.category {
+				t.Fatalf("Category = %q, want %q", prediction.Category, test.category)
+			}
+			if len(prediction.Alternatives) != len(model.Classes)-1 {
+				t.Fatalf("got %d alternatives, want %d", len(prediction.Alternatives), len(model.Classes)-1)
+			}
+
+			total := float64(prediction.Confidence)
+			previous := prediction.Confidence
+			for _, alt := range prediction.Alternatives {
+				if alt.Category == prediction.Category {
+					t.Errorf("alternative %q repeats the predicted category", alt.Category)
+				}
+				if alt.ConfidenceScore > previous {
+					t.Errorf("alternatives %v are not ranked by confidence below %v", prediction.Alternatives, prediction.Confidence)
+				}
+				previous = alt.ConfidenceScore
+				total += float64(alt.ConfidenceScore)
+			}
+			if math.Abs(total-1) > 1e-5 {
+				t.Errorf("probabilities sum to %v, want 1", total)
+			}
+
+			if len(prediction.Keywords) > 3 {
+				t.Errorf("got %d keywords, want at most 3", len(prediction.Keywords))
+			}
+			if !slices.Contains(prediction.Keywords, test.keyword) {
+				t.Errorf("Keywords = %v, want them to include %q", prediction.Keywords, test.keyword)
+			}
+		})
+	}
+}
+
+func TestTrainOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		examples   []export.Example
+		opts       Options
+		vocabulary int
+		wantErr    bool
+	}{
+		{name: "limits the vocabulary", examples: fixture, opts: Options{MaxFeatures: 10}, vocabulary: 10},
+		{name: "no examples", wantErr: true},
+		{name: "only unlabeled examples", examples: fixture[len(fixture)-1:], wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model, err := Train(test.examples, test.opts)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("Train() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Train() error = %v", err)
+			}
+			if len(model.Vocabulary) != test.vocabulary {
+				t.Errorf("got %d vocabulary terms, want %d", len(model.Vocabulary), test.vocabulary)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	model, err := Train(fixture, Options{Version: "test"})
+	if err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "model.json")
+	if err := model.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if loaded.Version != model.Version || !loaded.TrainedAt.Equal(model.TrainedAt) || loaded.Examples != model.Examples {
+		t.Errorf("loaded model metadata = %q %v %d, want %q %v %d",
+			loaded.Version, loaded.TrainedAt, loaded.Examples, model.Version, model.TrainedAt, model.Examples)
+	}
+	for _, text := range []string{"Invoice payment due", "Big sale, use the discount code", "Agenda for the project meeting", "nothing known"} {
+		if got, want := loaded.Predict(text, 3), model.Predict(text, 3); !reflect.DeepEqual(got, want) {
+			t.Errorf("loaded model predicts %+v for %q, want %+v", got, text, want)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not JSON", data: "not a model"},
+		{name: "unsupported format version", data: `{"format_version": 2, "classes": ["A"], "class_log_prior": [0], "feature_log_prob": [[]]}`},
+		{name: "no classes", data: `{"format_version": 1}`},
+		{name: "missing priors", data: `{"format_version": 1, "classes": ["A", "B"], "class_log_prior": [0], "feature_log_prob": [[], []]}`},
+		{name: "missing feature probabilities", data: `{"format_version": 1, "classes": ["A"], "class_log_prior": [0], "vocabulary": ["a"], "idf": [1], "feature_log_prob": [[]]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model.json")
+			if err := os.WriteFile(path, []byte(test.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("Load() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file error = nil, want an error")
+	}
+}
@@ -1,6 +1,17 @@
 package config
 
-import "github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samiransarii/inboXpert/common/utils"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+
+	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
+)
 
 // New creates a new Config instance with initialized settings for the email categorization service.
 // This includes gRPC server settings, ML server endpoint, batching parameters, worker counts, and retry policies.
@@ -10,16 +21,112 @@ func New() *models.Config {
 	// The connectDB() function should return a pooled connection object.
 	dbPool := connectDB()
 
+	// Determine how batches with partially failed emails are persisted,
+	// falling back to saving the successful emails for unknown values.
+	persistPolicy := models.PersistPolicy(utils.GetEnv("BATCH_PERSIST_POLICY", string(models.PersistSucceeded)))
+	if persistPolicy != models.PersistAllOrNothing && persistPolicy != models.PersistSucceeded {
+		log.Printf("Unknown BATCH_PERSIST_POLICY %q, using %q", persistPolicy, models.PersistSucceeded)
+		persistPolicy = models.PersistSucceeded
+	}
+
+	// Read the per-category confidence thresholds, ignoring them entirely if they are malformed.
+	categoryThresholds, err := ParseCategoryThresholds(utils.GetEnv("CATEGORY_THRESHOLDS", ""))
+	if err != nil {
+		log.Printf("Invalid CATEGORY_THRESHOLDS, using the global threshold for every category: %v", err)
+		categoryThresholds = nil
+	}
+
+	// Determine how the labels of an email are selected, falling back to top-k for unknown values.
+	labelSelection := models.LabelSelection(utils.GetEnv("LABEL_SELECTION", string(models.LabelsTopK)))
+	if labelSelection != models.LabelsTopK && labelSelection != models.LabelsCumulative {
+		log.Printf("Unknown LABEL_SELECTION %q, using %q", labelSelection, models.LabelsTopK)
+		labelSelection = models.LabelsTopK
+	}
+
+	// Read how long cached ML predictions stay valid, falling back to a day for malformed values.
+	cacheTTL, err := time.ParseDuration(utils.GetEnv("ML_CACHE_TTL", "24h"))
+	if err != nil || cacheTTL <= 0 {
+		log.Printf("Invalid ML_CACHE_TTL, using 24h: %v", err)
+		cacheTTL = 24 * time.Hour
+	}
+	cachePersistent, err := strconv.ParseBool(utils.GetEnv("ML_CACHE_POSTGRES", "false"))
+	if err != nil {
+		log.Printf("Invalid ML_CACHE_POSTGRES, disabling the persistent cache: %v", err)
+		cachePersistent = false
+	}
+
+	// Read how long single-email ML requests wait to be batched together, disabling batching for malformed values.
+	batchWait, err := time.ParseDuration(utils.GetEnv("ML_BATCH_WAIT", "0"))
+	if err != nil || batchWait < 0 {
+		log.Printf("Invalid ML_BATCH_WAIT, disabling request coalescing: %v", err)
+		batchWait = 0
+	}
+
+	// Determine what classifies emails, falling back to the ML server for unknown values.
+	backend := models.MLBackend(utils.GetEnv("ML_BACKEND", string(models.BackendGRPC)))
+	if backend != models.BackendGRPC && backend != models.BackendEmbedded {
+		log.Printf("Unknown ML_BACKEND %q, using %q", backend, models.BackendGRPC)
+		backend = models.BackendGRPC
+	}
+
+	// Read the balancing of ML requests across replicas, falling back to round-robin for unknown values.
+	balancingPolicy := mlclient.BalancingPolicy(utils.GetEnv("ML_BALANCING_POLICY", string(mlclient.RoundRobin)))
+	if balancingPolicy != mlclient.RoundRobin && balancingPolicy != mlclient.LeastOutstanding {
+		log.Printf("Unknown ML_BALANCING_POLICY %q, using %q", balancingPolicy, mlclient.RoundRobin)
+		balancingPolicy = mlclient.RoundRobin
+	}
+	healthCheckInterval, err := time.ParseDuration(utils.GetEnv("ML_HEALTH_CHECK_INTERVAL", "10s"))
+	if err != nil || healthCheckInterval <= 0 {
+		log.Printf("Invalid ML_HEALTH_CHECK_INTERVAL, using 10s: %v", err)
+		healthCheckInterval = 10 * time.Second
+	}
+
+	// Determine how emails are categorized while the ML service is down, falling back to the rules for unknown values.
+	fallback := models.FallbackMode(utils.GetEnv("ML_FALLBACK", string(models.FallbackRules)))
+	if fallback != models.FallbackRules && fallback != models.FallbackModel && fallback != models.FallbackNone {
+		log.Printf("Unknown ML_FALLBACK %q, using %q", fallback, models.FallbackRules)
+		fallback = models.FallbackRules
+	}
+	breakerOpenTimeout, err := time.ParseDuration(utils.GetEnv("BREAKER_OPEN_TIMEOUT", "30s"))
+	if err != nil || breakerOpenTimeout <= 0 {
+		log.Printf("Invalid BREAKER_OPEN_TIMEOUT, using 30s: %v", err)
+		breakerOpenTimeout = 30 * time.Second
+	}
+	retryBackoff, err := time.ParseDuration(utils.GetEnv("RETRY_BACKOFF", "100ms"))
+	if err != nil || retryBackoff < 0 {
+		log.Printf("Invalid RETRY_BACKOFF, using 100ms: %v", err)
+		retryBackoff = 100 * time.Millisecond
+	}
+
 	// Return a new Config instance populated with essential parameters.
 	return &models.Config{
 		// GRPCPort defines the network address and port on which the gRPC server will listen.
 		GRPCPort: ":50051",
 
-		// MLServerAddr specifies the address of the machine learning server that handles categorization logic.
-		MLServerAddr: "localhost:50055",
+		// MLBackend selects whether emails are classified by the ML server or by the embedded model
+		// of MLModelFile, which is trained with cmd/train.
+		MLBackend:   backend,
+		MLModelFile: utils.GetEnv("ML_MODEL_FILE", ""),
+
+		// MLServerAddrs lists the replicas of the machine learning server that handles categorization logic,
+		// e.g. ML_SERVER_ADDRS="ml-1:50055,ml-2:50055" or ML_SERVER_ADDRS="dns:///ml.internal:50055".
+		MLServerAddrs: ParseList(utils.GetEnv("ML_SERVER_ADDRS", "localhost:50055")),
 
-		// MaxBatchSize limits how many items can be processed in a batch.
-		MaxBatchSize: 1000,
+		// BreakerFailureThreshold, BreakerOpenTimeout, and BreakerHalfOpenRequests configure the circuit
+		// breaker in front of the ML service, and MLFallback how emails are categorized while it is open.
+		BreakerFailureThreshold: max(utils.GetEnvAsInt("BREAKER_FAILURE_THRESHOLD", 5), 1),
+		BreakerOpenTimeout:      breakerOpenTimeout,
+		BreakerHalfOpenRequests: max(utils.GetEnvAsInt("BREAKER_HALF_OPEN_REQUESTS", 1), 1),
+		MLFallback:              fallback,
+
+		// MLBalancingPolicy and MLHealthCheckInterval decide how requests are spread across the ML replicas
+		// and how quickly unhealthy replicas are ejected and healthy ones brought back.
+		MLBalancingPolicy:     balancingPolicy,
+		MLHealthCheckInterval: healthCheckInterval,
+
+		// MaxBatchSize limits how many items can be processed in a batch. It is shared with the
+		// API Gateway, which splits larger requests into batches of that size.
+		MaxBatchSize: utils.MaxBatchSize(),
 
 		// NumWorkers sets the number of concurrent workers handling categorization tasks.
 		NumWorkers: 10,
@@ -28,7 +135,99 @@ func New() *models.Config {
 		// will be retried before giving up.
 		RetryAttempts: 3,
 
+		// RetryBackoff is the delay before the first retry of an ML request. It doubles on every
+		// further retry, so a struggling ML service is not hammered with immediate retries.
+		RetryBackoff: retryBackoff,
+
+		// BatchPersistPolicy decides whether a batch with failed emails is saved partially
+		// or not at all.
+		BatchPersistPolicy: persistPolicy,
+
+		// RulesFile points to the rule set applied before and after the ML prediction.
+		// Rules are disabled when no file is configured.
+		RulesFile: utils.GetEnv("RULES_FILE", ""),
+
+		// TaxonomyFile points to the tree of categories results may be assigned to.
+		// The flat list of categories predicted by the ML service is used when no file is configured.
+		TaxonomyFile: utils.GetEnv("TAXONOMY_FILE", ""),
+
+		// RoutingFile points to the models besides the primary one that emails are shadowed to or split
+		// between. Every email is served by the primary model when no file is configured.
+		RoutingFile: utils.GetEnv("ROUTING_FILE", ""),
+
+		// ModelVersion identifies the model served by the ML service. Cached predictions are kept
+		// per model version, so bumping it after deploying a new model invalidates the cache.
+		ModelVersion: utils.GetEnv("ML_MODEL_VERSION", "v1"),
+
+		// ServiceVersion identifies the deployed version of this service. It is recorded with every
+		// categorization along with the model and the rule set that produced it.
+		ServiceVersion: utils.GetEnv("SERVICE_VERSION", "dev"),
+
+		// MLCacheSize, MLCacheTTL, and MLCachePersistent configure the cache of ML predictions
+		// keyed by the normalized content of an email. ML_CACHE_SIZE=0 disables the cache.
+		MLCacheSize:       max(utils.GetEnvAsInt("ML_CACHE_SIZE", 10000), 0),
+		MLCacheTTL:        cacheTTL,
+		MLCachePersistent: cachePersistent,
+
+		// MLBatchSize and MLBatchWait configure the coalescing of concurrent single-email ML requests
+		// into batch requests of up to MLBatchSize emails. Coalescing adds up to MLBatchWait to every
+		// request, so it is off unless ML_BATCH_WAIT is set to a positive duration, e.g. 5ms.
+		MLBatchSize: max(utils.GetEnvAsInt("ML_BATCH_SIZE", 32), 1),
+		MLBatchWait: batchWait,
+
+		// ConfidenceThreshold is the minimum confidence a prediction needs to be accepted.
+		// Less confident predictions are marked as needing review instead of being filed.
+		ConfidenceThreshold: float32(utils.GetEnvAsFloat("CONFIDENCE_THRESHOLD", 0.5)),
+
+		// CategoryThresholds override ConfidenceThreshold for individual categories,
+		// e.g. CATEGORY_THRESHOLDS="FINANCE=0.7,SHOPPING=0.4".
+		CategoryThresholds: categoryThresholds,
+
+		// LabelSelection, MaxLabels, CumulativeLabelScore, and MinLabelScore decide which of the
+		// ranked categories become labels of an email, so an email can carry several categories.
+		// Setting MAX_LABELS=1 restores single-label categorization.
+		LabelSelection:       labelSelection,
+		MaxLabels:            max(utils.GetEnvAsInt("MAX_LABELS", 2), 1),
+		CumulativeLabelScore: float32(utils.GetEnvAsFloat("CUMULATIVE_LABEL_SCORE", 0.8)),
+		MinLabelScore:        float32(utils.GetEnvAsFloat("MIN_LABEL_SCORE", 0.3)),
+
 		// DBPool is the connection pool to the underlying database.
 		DBPool: dbPool,
 	}
 }
+
+// ParseList splits a comma-separated list, dropping empty entries and surrounding whitespace.
+func ParseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseCategoryThresholds parses a comma-separated list of CATEGORY=threshold pairs,
+// e.g. "FINANCE=0.7,SHOPPING=0.4". An empty value yields no thresholds.
+func ParseCategoryThresholds(value string) (map[string]float32, error) {
+	thresholds := make(map[string]float32)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		category, rawThreshold, found := strings.Cut(pair, "=")
+		category = strings.TrimSpace(category)
+		if !found || category == "" {
+			return nil, fmt.Errorf("expected CATEGORY=threshold, got %q", pair)
+		}
+
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(rawThreshold), 32)
+		if err != nil || threshold < 0 || threshold > 1 {
+			return nil, fmt.Errorf("invalid threshold for %s: %q", category, rawThreshold)
+		}
+		thresholds[category] = float32(threshold)
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,260 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/export"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// Predictor categorizes a single email. It is implemented by handlers.CategorizationHandler,
+// so the evaluation covers the full pipeline of rules, ML prediction, and retries.
+type Predictor interface {
+	Predict(ctx context.Context, email *models.Email) (*models.CategoryResult, error)
+}
+
+// Options controls how a dataset is evaluated.
+type Options struct {
+	// Workers is the number of emails categorized concurrently. Values below 1 mean 1.
+	Workers int
+	// Buckets is the number of equal-width confidence buckets used for calibration. Values below 1 mean 10.
+	Buckets int
+}
+
+// Report summarizes how well the predicted categories match the labels of a dataset.
+// Emails whose categorization failed are counted but excluded from every metric. Emails
+// left for review are predicted as models.NeedsReviewCategory, so they count as incorrect.
+type Report struct {
+	Total       int     `json:"total"`
+	Failed      int     `json:"failed"`
+	NeedsReview int     `json:"needs_review"`
+	Correct     int     `json:"correct"`
+	Accuracy    float64 `json:"accuracy"`
+
+	MacroPrecision float64 `json:"macro_precision"`
+	MacroRecall    float64 `json:"macro_recall"`
+	MacroF1        float64 `json:"macro_f1"`
+
+	Categories      []CategoryMetrics   `json:"categories"`
+	ConfusionMatrix ConfusionMatrix     `json:"confusion_matrix"`
+	Calibration     []CalibrationBucket `json:"calibration"`
+
+	// ExpectedCalibrationError is the average gap between confidence and accuracy
+	// across the calibration buckets, weighted by the number of emails in each bucket.
+	ExpectedCalibrationError float64 `json:"expected_calibration_error"`
+}
+
+// CategoryMetrics holds the precision, recall, and F1 score of a single category.
+// Support is the number of emails labeled with the category, and Predicted the number
+// of emails the pipeline assigned to it.
+type CategoryMetrics struct {
+	Category  string  `json:"category"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Support   int     `json:"support"`
+	Predicted int     `json:"predicted"`
+}
+
+// ConfusionMatrix counts the emails of each labeled category (rows) by their predicted
+// category (columns). Both axes use the order of Labels.
+type ConfusionMatrix struct {
+	Labels []string `json:"labels"`
+	Counts [][]int  `json:"counts"`
+}
+
+// CalibrationBucket groups the emails whose confidence score falls within [Lower, Upper)
+// (the last bucket includes 1.0) and compares their mean confidence with their accuracy.
+type CalibrationBucket struct {
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	Count          int     `json:"count"`
+	MeanConfidence float64 `json:"mean_confidence"`
+	Accuracy       float64 `json:"accuracy"`
+}
+
+// outcome is the prediction made for a single labeled example.
+type outcome struct {
+	expected    string
+	predicted   string
+	confidence  float32
+	needsReview bool
+	err         error
+}
+
+// Evaluate categorizes every example of a dataset with the predictor and compares the primary
+// predicted category with the example's label.
+func Evaluate(ctx context.Context, predictor Predictor, examples []export.Example, opts Options) *Report {
+	workers := max(opts.Workers, 1)
+	buckets := opts.Buckets
+	if buckets < 1 {
+		buckets = 10
+	}
+
+	outcomes := make([]outcome, len(examples))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each worker writes only to the outcomes of the indexes it receives.
+			for i := range indexes {
+				outcomes[i] = predict(ctx, predictor, examples[i])
+			}
+		}()
+	}
+	for i := range examples {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return buildReport(outcomes, buckets)
+}
+
+// predict categorizes the email of a single example.
+func predict(ctx context.Context, predictor Predictor, example export.Example) outcome {
+	email := &models.Email{
+		ID:         example.MessageID,
+		Subject:    example.Subject,
+		Body:       example.Body,
+		Sender:     example.Sender,
+		Recipients: example.Recipients,
+		Headers:    example.Headers,
+	}
+
+	result, err := predictor.Predict(ctx, email)
+	if err != nil {
+		return outcome{expected: example.Category, err: err}
+	}
+	if len(result.Categories) == 0 {
+		return outcome{expected: example.Category, err: errors.New("no category predicted")}
+	}
+
+	return outcome{
+		expected:    example.Category,
+		predicted:   result.Categories[0],
+		confidence:  result.ConfidenceScore,
+		needsReview: result.NeedsReview,
+	}
+}
+
+// buildReport computes the metrics of a report from the outcomes of every example.
+func buildReport(outcomes []outcome, buckets int) *Report {
+	report := &Report{Total: len(outcomes)}
+
+	// Collect every category that was either labeled or predicted, in alphabetical order.
+	seen := make(map[string]bool)
+	for _, o := range outcomes {
+		if o.err != nil {
+			report.Failed++
+			continue
+		}
+		seen[o.expected] = true
+		seen[o.predicted] = true
+	}
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	position := make(map[string]int, len(labels))
+	for i, label := range labels {
+		position[label] = i
+	}
+
+	counts := make([][]int, len(labels))
+	for i := range counts {
+		counts[i] = make([]int, len(labels))
+	}
+
+	calibration := make([]CalibrationBucket, buckets)
+	for i := range calibration {
+		calibration[i].Lower = float64(i) / float64(buckets)
+		calibration[i].Upper = float64(i+1) / float64(buckets)
+	}
+
+	evaluated := 0
+	for _, o := range outcomes {
+		if o.err != nil {
+			continue
+		}
+		evaluated++
+		if o.needsReview {
+			report.NeedsReview++
+		}
+		counts[position[o.expected]][position[o.predicted]]++
+
+		correct := o.expected == o.predicted
+		if correct {
+			report.Correct++
+		}
+
+		// The small epsilon keeps float32 scores such as 0.9 (stored as 0.8999999762)
+		// in the bucket they were meant for.
+		confidence := math.Min(math.Max(float64(o.confidence), 0), 1)
+		bucket := &calibration[min(int(confidence*float64(buckets)+1e-6), buckets-1)]
+		bucket.Count++
+		bucket.MeanConfidence += confidence
+		if correct {
+			bucket.Accuracy++
+		}
+	}
+
+	// Turn the bucket sums into means and accumulate the calibration error.
+	for i := range calibration {
+		bucket := &calibration[i]
+		if bucket.Count == 0 {
+			continue
+		}
+		bucket.MeanConfidence /= float64(bucket.Count)
+		bucket.Accuracy /= float64(bucket.Count)
+		report.ExpectedCalibrationError += float64(bucket.Count) / float64(evaluated) *
+			math.Abs(bucket.Accuracy-bucket.MeanConfidence)
+	}
+
+	// Derive the per-category metrics from the confusion matrix.
+	for i, label := range labels {
+		metrics := CategoryMetrics{Category: label}
+		truePositives := counts[i][i]
+		for j := range labels {
+			metrics.Support += counts[i][j]
+			metrics.Predicted += counts[j][i]
+		}
+		metrics.Precision = ratio(truePositives, metrics.Predicted)
+		metrics.Recall = ratio(truePositives, metrics.Support)
+		if metrics.Precision+metrics.Recall > 0 {
+			metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+		}
+
+		report.MacroPrecision += metrics.Precision
+		report.MacroRecall += metrics.Recall
+		report.MacroF1 += metrics.F1
+		report.Categories = append(report.Categories, metrics)
+	}
+	if len(labels) > 0 {
+		report.MacroPrecision /= float64(len(labels))
+		report.MacroRecall /= float64(len(labels))
+		report.MacroF1 /= float64(len(labels))
+	}
+
+	report.Accuracy = ratio(report.Correct, evaluated)
+	report.ConfusionMatrix = ConfusionMatrix{Labels: labels, Counts: counts}
+	report.Calibration = calibration
+	return report
+}
+
+// ratio returns a divided by b, or zero if b is zero.
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
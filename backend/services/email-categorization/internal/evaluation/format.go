@@ -0,0 +1,55 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteJSON writes the report as indented JSON. The output is deterministic for a given
+// report, so the reports of two runs can be diffed directly.
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteText writes the report as human-readable tables: a summary, the per-category metrics,
+// the confusion matrix, and the calibration buckets.
+func WriteText(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintf(tw, "Emails\t%d\t\n", report.Total)
+	fmt.Fprintf(tw, "Failed\t%d\t\n", report.Failed)
+	fmt.Fprintf(tw, "Needs review\t%d\t\n", report.NeedsReview)
+	fmt.Fprintf(tw, "Accuracy\t%.4f\t\n", report.Accuracy)
+	fmt.Fprintf(tw, "Macro precision\t%.4f\t\n", report.MacroPrecision)
+	fmt.Fprintf(tw, "Macro recall\t%.4f\t\n", report.MacroRecall)
+	fmt.Fprintf(tw, "Macro F1\t%.4f\t\n", report.MacroF1)
+	fmt.Fprintf(tw, "Expected calibration error\t%.4f\t\n", report.ExpectedCalibrationError)
+
+	fmt.Fprintln(tw, "\nCategory\tPrecision\tRecall\tF1\tSupport\tPredicted\t")
+	for _, m := range report.Categories {
+		fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%.4f\t%d\t%d\t\n", m.Category, m.Precision, m.Recall, m.F1, m.Support, m.Predicted)
+	}
+
+	// Rows are the labeled categories and columns the predicted ones.
+	matrix := report.ConfusionMatrix
+	fmt.Fprintf(tw, "\nLabel \\ Predicted\t%s\t\n", strings.Join(matrix.Labels, "\t"))
+	for i, label := range matrix.Labels {
+		cells := make([]string, len(matrix.Counts[i]))
+		for j, count := range matrix.Counts[i] {
+			cells[j] = fmt.Sprint(count)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", label, strings.Join(cells, "\t"))
+	}
+
+	fmt.Fprintln(tw, "\nConfidence\tCount\tMean confidence\tAccuracy\t")
+	for _, bucket := range report.Calibration {
+		fmt.Fprintf(tw, "%.2f-%.2f\t%d\t%.4f\t%.4f\t\n", bucket.Lower, bucket.Upper, bucket.Count, bucket.MeanConfidence, bucket.Accuracy)
+	}
+
+	return tw.Flush()
+}
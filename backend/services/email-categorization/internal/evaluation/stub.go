@@ -0,0 +1,39 @@
+package evaluation
+
+import (
+	"context"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// StubClient is an ML client that predicts the same category for every email without
+// contacting the ML service. Evaluating with it measures what the rules alone achieve,
+// with every email the rules do not decide falling back to the stub's category.
+type StubClient struct {
+	Category   string
+	Confidence float32
+}
+
+// CategorizeEmail returns the stub's category and confidence for the email.
+func (c *StubClient) CategorizeEmail(ctx context.Context, email *mlpb.EmailRequest) (*mlpb.CategoryResponse, error) {
+	return &mlpb.CategoryResponse{
+		Id:         email.Id,
+		Category:   c.Category,
+		Confidence: c.Confidence,
+	}, nil
+}
+
+// BatchCategorizeEmails returns the stub's category and confidence for every email of the batch.
+func (c *StubClient) BatchCategorizeEmails(ctx context.Context, emails []*mlpb.EmailRequest) (*mlpb.BatchCategoryResponse, error) {
+	response := &mlpb.BatchCategoryResponse{}
+	for _, email := range emails {
+		result, _ := c.CategorizeEmail(ctx, email)
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// Close does nothing, since the stub holds no connection.
+func (c *StubClient) Close() error {
+	return nil
+}
@@ -0,0 +1,241 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// Columns lists the CSV columns of an exported dataset, in the same order as the ML server's
+// detailed_labeled_emails.csv, so exported files can be used for training as they are.
+var Columns = []string{
+	"message_id", "subject", "body", "sender", "date", "recipients", "headers", "category", "confidence", "factors",
+}
+
+// Options controls which labeled emails are turned into training examples.
+type Options struct {
+	// PerCategory caps the number of examples per category. Zero keeps every example.
+	PerCategory int
+	// Seed makes the per-category sampling reproducible.
+	Seed uint64
+}
+
+// Example is a single labeled email in the format of detailed_labeled_emails.csv.
+type Example struct {
+	MessageID  string            `json:"message_id"`
+	Subject    string            `json:"subject"`
+	Body       string            `json:"body"`
+	Sender     string            `json:"sender"`
+	Date       string            `json:"date"`
+	Recipients []string          `json:"recipients"`
+	Headers    map[string]string `json:"headers"`
+	Category   string            `json:"category"`
+	Confidence float32           `json:"confidence"`
+	Factors    []string          `json:"factors"`
+}
+
+// BuildExamples turns labeled emails into training examples. A user correction, when present,
+// is used as the label with full confidence; otherwise the stored prediction is used. When
+// PerCategory is set, each category is randomly sampled down to that many examples. Examples
+// are grouped by category, in alphabetical order.
+func BuildExamples(labeled []models.LabeledEmail, opts Options) []Example {
+	byCategory := make(map[string][]Example)
+	for _, email := range labeled {
+		example, ok := newExample(email)
+		if !ok {
+			continue
+		}
+		byCategory[example.Category] = append(byCategory[example.Category], example)
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	random := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+
+	var examples []Example
+	for _, category := range categories {
+		group := byCategory[category]
+		if opts.PerCategory > 0 && len(group) > opts.PerCategory {
+			random.Shuffle(len(group), func(i, j int) {
+				group[i], group[j] = group[j], group[i]
+			})
+			group = group[:opts.PerCategory]
+		}
+		examples = append(examples, group...)
+	}
+	return examples
+}
+
+// newExample builds the training example for a labeled email. It returns false if the email
+// has neither a correction nor a predicted category, or if its prediction still awaits review.
+func newExample(labeled models.LabeledEmail) (Example, bool) {
+	email := labeled.Email
+	example := Example{
+		MessageID:  headerValue(email.Headers, "message-id"),
+		Subject:    email.Subject,
+		Body:       email.Body,
+		Sender:     email.Sender,
+		Date:       headerValue(email.Headers, "date"),
+		Recipients: email.Recipients,
+		Headers:    email.Headers,
+	}
+	if example.MessageID == "" {
+		example.MessageID = email.ID
+	}
+	if example.Date == "" {
+		example.Date = labeled.CreatedAt.Format(time.RFC1123Z)
+	}
+
+	result := labeled.Result
+	switch {
+	case labeled.CorrectedCategory != "":
+		example.Category = labeled.CorrectedCategory
+		example.Confidence = 1
+		example.Factors = []string{"Corrected by user"}
+		if result.NeedsReview {
+			example.Factors = append(example.Factors, fmt.Sprintf("Originally predicted %s", result.PredictedCategory))
+		} else if len(result.Categories) > 0 {
+			example.Factors = append(example.Factors, fmt.Sprintf("Originally predicted %s", result.Categories[0]))
+		}
+	case result.NeedsReview:
+		// Predictions awaiting review were not trusted to file the email, so they are not used as labels.
+		return Example{}, false
+	case len(result.Categories) > 0:
+		example.Category = result.Categories[0]
+		example.Confidence = result.ConfidenceScore
+		example.Factors = []string{fmt.Sprintf("Predicted with confidence %.2f", result.ConfidenceScore)}
+		if result.MatchedRule != "" {
+			example.Factors = append(example.Factors, fmt.Sprintf("Matched rule %s", result.MatchedRule))
+		}
+		if len(result.Keywords) > 0 {
+			example.Factors = append(example.Factors, fmt.Sprintf("Found %d keywords", len(result.Keywords)))
+		}
+	default:
+		return Example{}, false
+	}
+
+	return example, true
+}
+
+// headerValue looks up a header case-insensitively.
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// WriteCSV writes the examples as CSV with a header row. The list and dictionary columns
+// (recipients, headers, and factors) are written as Python literals, the way pandas wrote
+// them into detailed_labeled_emails.csv.
+func WriteCSV(w io.Writer, examples []Example) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+
+	for _, example := range examples {
+		record := []string{
+			example.MessageID,
+			example.Subject,
+			example.Body,
+			example.Sender,
+			example.Date,
+			pythonList(example.Recipients),
+			pythonDict(example.Headers),
+			example.Category,
+			strconv.FormatFloat(float64(example.Confidence), 'f', -1, 32),
+			pythonList(example.Factors),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSONL writes the examples as JSON Lines, one example object per line, using the same
+// field names as the CSV columns.
+func WriteJSONL(w io.Writer, examples []Example) error {
+	encoder := json.NewEncoder(w)
+	for _, example := range examples {
+		if err := encoder.Encode(example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pythonList formats a list of strings as a Python list literal, e.g. ['a', 'b'].
+func pythonList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = pythonString(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// pythonDict formats a map of strings as a Python dict literal with sorted keys, e.g. {'a': 'b'}.
+func pythonDict(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = pythonString(key) + ": " + pythonString(values[key])
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+// pythonString formats a string the way Python's repr does: single-quoted unless the string
+// contains single quotes but no double quotes, with backslashes and control characters escaped.
+func pythonString(value string) string {
+	quote := byte('\'')
+	if strings.Contains(value, "'") && !strings.Contains(value, `"`) {
+		quote = '"'
+	}
+
+	var b strings.Builder
+	b.WriteByte(quote)
+	for _, r := range value {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == rune(quote):
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte(quote)
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReadCSV reads a labeled dataset in the format written by WriteCSV, which is also the format of
+// the ML server's detailed_labeled_emails.csv. Columns are located by name from the header row,
+// so their order does not matter, and the list and dictionary columns are parsed from Python literals.
+func ReadCSV(r io.Reader) ([]Example, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.TrimSpace(column)] = i
+	}
+	for _, column := range Columns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	var examples []Example
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+
+		example, err := parseRecord(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		examples = append(examples, example)
+	}
+
+	return examples, nil
+}
+
+// parseRecord builds an example from a CSV record, using index to locate the columns.
+func parseRecord(record []string, index map[string]int) (Example, error) {
+	field := func(column string) string {
+		return record[index[column]]
+	}
+
+	example := Example{
+		MessageID: field("message_id"),
+		Subject:   field("subject"),
+		Body:      field("body"),
+		Sender:    field("sender"),
+		Date:      field("date"),
+		Category:  field("category"),
+	}
+
+	var err error
+	if example.Recipients, err = parsePythonList(field("recipients")); err != nil {
+		return Example{}, fmt.Errorf("invalid recipients: %w", err)
+	}
+	if example.Headers, err = parsePythonDict(field("headers")); err != nil {
+		return Example{}, fmt.Errorf("invalid headers: %w", err)
+	}
+	if example.Factors, err = parsePythonList(field("factors")); err != nil {
+		return Example{}, fmt.Errorf("invalid factors: %w", err)
+	}
+	if confidence := field("confidence"); confidence != "" {
+		value, err := strconv.ParseFloat(confidence, 32)
+		if err != nil {
+			return Example{}, fmt.Errorf("invalid confidence: %w", err)
+		}
+		example.Confidence = float32(value)
+	}
+
+	return example, nil
+}
+
+// parsePythonList parses a Python list literal of strings, e.g. ['a', "b"]. An empty value is an empty list.
+func parsePythonList(value string) ([]string, error) {
+	p := &literalParser{input: strings.TrimSpace(value)}
+	if p.input == "" {
+		return nil, nil
+	}
+
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+	var values []string
+	for !p.consume(']') {
+		if len(values) > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+			if p.consume(']') { // trailing comma
+				break
+			}
+		}
+		item, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, item)
+	}
+	return values, p.end()
+}
+
+// parsePythonDict parses a Python dict literal with string keys and values, e.g. {'a': 'b'}.
+// An empty value is an empty dict.
+func parsePythonDict(value string) (map[string]string, error) {
+	p := &literalParser{input: strings.TrimSpace(value)}
+	values := make(map[string]string)
+	if p.input == "" {
+		return values, nil
+	}
+
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	for first := true; !p.consume('}'); first = false {
+		if !first {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+			if p.consume('}') { // trailing comma
+				break
+			}
+		}
+		key, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		item, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		values[key] = item
+	}
+	return values, p.end()
+}
+
+// literalParser is a minimal parser for the Python string, list, and dict literals
+// that pandas writes for object columns.
+type literalParser struct {
+	input string
+	pos   int
+}
+
+// skipSpace advances past any whitespace.
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// consume skips whitespace and advances past c if it is the next character.
+func (p *literalParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect is like consume, but returns an error if c is not the next character.
+func (p *literalParser) expect(c byte) error {
+	if !p.consume(c) {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	return nil
+}
+
+// end returns an error if anything but whitespace follows the parsed literal.
+func (p *literalParser) end() error {
+	p.skipSpace()
+	if p.pos != len(p.input) {
+		return fmt.Errorf("unexpected trailing input at offset %d", p.pos)
+	}
+	return nil
+}
+
+// string parses a single- or double-quoted Python string literal, decoding its escape sequences.
+func (p *literalParser) string() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) || (p.input[p.pos] != '\'' && p.input[p.pos] != '"') {
+		return "", fmt.Errorf("expected string at offset %d", p.pos)
+	}
+	quote := p.input[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+			b.WriteRune(r)
+			p.pos += size
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+// escape decodes the escape sequence starting at the current backslash into b.
+func (p *literalParser) escape(b *strings.Builder) error {
+	c := p.input[p.pos+1]
+	p.pos += 2
+
+	hexDigits := 0
+	switch c {
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case '\\', '\'', '"':
+		b.WriteByte(c)
+	case 'x':
+		hexDigits = 2
+	case 'u':
+		hexDigits = 4
+	case 'U':
+		hexDigits = 8
+	default:
+		// Python keeps unknown escape sequences as they are.
+		b.WriteByte('\\')
+		b.WriteByte(c)
+	}
+	if hexDigits == 0 {
+		return nil
+	}
+
+	if p.pos+hexDigits > len(p.input) {
+		return fmt.Errorf("truncated \\%c escape at offset %d", c, p.pos)
+	}
+	code, err := strconv.ParseUint(p.input[p.pos:p.pos+hexDigits], 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid \\%c escape at offset %d", c, p.pos)
+	}
+	b.WriteRune(rune(code))
+	p.pos += hexDigits
+	return nil
+}
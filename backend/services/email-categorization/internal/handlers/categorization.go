@@ -2,14 +2,21 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/cache"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/routing"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/rules"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/taxonomy"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
 
 	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
@@ -21,136 +28,299 @@ import (
 // It handles the process from saving emails to the database, sending them to
 // an ML service for categorization, handling batch requests, and storing the results.
 type CategorizationHandler struct {
-	config     *models.Config
-	workerPool chan struct{}
-	mlClient   mlclient.Service
-	emailRepo  *EmailRepository
+	config       *models.Config
+	workerPool   chan struct{}
+	mlClient     mlclient.Service
+	emailRepo    *EmailRepository
+	categoryRepo *CategoryRepository
+	rules        *rules.Engine
+	taxonomy     *taxonomy.Taxonomy
+
+	// fallbackClient classifies emails while the circuit breaker in front of mlClient is open,
+	// under the model fallback. Nil when no fallback model is configured.
+	fallbackClient mlclient.Service
+
+	// router sends emails to A/B variants of the model behind mlClient and to shadow models, whose
+	// predictions are stored through predictionRepo. Nil when emails only go to mlClient.
+	router         *routing.Router
+	predictionRepo *PredictionRepository
+	shadowPool     chan struct{}
+
 	pb.UnimplementedEmailCategorizationServiceServer
 }
 
 // NewCategorizationHandler creates a new CategorizationHandler given a machine learning client service,
-// configuration parameters, and an EmailRepository for database persistence.
-func NewCategorizationHandler(mlClient mlclient.Service, config *models.Config, emailRepo *EmailRepository) *CategorizationHandler {
+// configuration parameters, an EmailRepository for database persistence, a CategoryRepository for users'
+// custom categories, the rule engine applied around the ML prediction, and the taxonomy of categories
+// results may be assigned to.
+func NewCategorizationHandler(mlClient mlclient.Service, config *models.Config, emailRepo *EmailRepository, categoryRepo *CategoryRepository, ruleEngine *rules.Engine, tax *taxonomy.Taxonomy) *CategorizationHandler {
 	return &CategorizationHandler{
-		config:     config,
-		workerPool: make(chan struct{}, config.NumWorkers),
-		mlClient:   mlClient,
-		emailRepo:  emailRepo,
+		config:       config,
+		workerPool:   make(chan struct{}, config.NumWorkers),
+		mlClient:     mlClient,
+		emailRepo:    emailRepo,
+		categoryRepo: categoryRepo,
+		rules:        ruleEngine,
+		taxonomy:     tax,
 	}
 }
 
+// SetFallbackClient sets the client that classifies emails while the ML service is unavailable,
+// under the model fallback.
+func (h *CategorizationHandler) SetFallbackClient(client mlclient.Service) {
+	h.fallbackClient = client
+}
+
+// SetRouter sets the router that splits emails between the model behind the ML client and its variants,
+// and sends them to shadow models. The predictions of every model are stored in predictionRepo.
+func (h *CategorizationHandler) SetRouter(router *routing.Router, predictionRepo *PredictionRepository) {
+	h.router = router
+	h.predictionRepo = predictionRepo
+	h.shadowPool = make(chan struct{}, h.config.NumWorkers)
+}
+
 // CategorizeEmail handles a single email categorization request.
 // It:
-// 1. Assigns a new UUID to the email and saves it to the database.
-// 2. Sends the email to the ML service for categorization.
-// 3. Stores the categorization results in the database.
-// 4. Returns the categorization response as a protobuf message.
+//  1. Derives the email's storage ID from its client-supplied ID, mailbox, and the requesting user.
+//  2. Returns the stored result if the same email was already categorized with unchanged
+//     content, unless the request forces re-categorization or the result came from a fallback.
+//  3. Otherwise sends the email to the ML service for categorization, applying the requesting
+//     user's custom category rules last so they win over the prediction.
+//  4. Stores the email together with its categorization results in the database, once categorized.
+//  5. Returns the categorization response, identified by the client-supplied ID, as a protobuf message.
 func (h *CategorizationHandler) CategorizeEmail(ctx context.Context, req *pb.CategorizeRequest) (*pb.CategorizeResponse, error) {
-	// Generate a new UUID for tracking the email
-	emailID := uuid.New().String()
-
 	// Convert the incoming protobuf email into the internal service model
 	internalEmail := converter.FromProtoEmail(req.Email)
-	internalEmail.ID = emailID
+	if internalEmail == nil {
+		return nil, fmt.Errorf("email is required")
+	}
 
-	// Save the email details to the database
-	err := h.emailRepo.SaveEmail(ctx, *internalEmail)
+	userID, err := requestUser(ctx, req.UserId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save email to the database: %w", err)
+		return nil, err
+	}
+	internalEmail.UserID = userID
+	storageID := storageEmailID(internalEmail)
+
+	// Reuse the previous result if this exact email has already been categorized
+	if !req.ForceRecategorize {
+		stored := h.lookupStoredResults(ctx, []string{storageID})
+		if previous, ok := stored[storageID]; ok && reusable(&previous, internalEmail) {
+			result := reusedResult(&previous, internalEmail, storageID)
+			return &pb.CategorizeResponse{Result: converter.ToProtoCategoryResult(&result)}, nil
+		}
 	}
 
 	// Process the email categorization via the ML service
-	result, err := h.processSingleEmail(ctx, internalEmail)
+	result, err := h.processSingleEmail(ctx, internalEmail, storageID, userID, h.loadUserRules(ctx, userID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to process email: %w", err)
 	}
+	result.EmailID = responseEmailID(internalEmail, storageID)
 
-	// Convert the categories to JSON for storage
-	categoriesJSON, err := json.Marshal(result.Categories)
+	// Store the email under its storage ID together with its categorization record, in a single
+	// transaction, so new content is never stored next to the result of the previous content
+	categorizationRecord, err := newCategoryRecord(storageID, result)
 	if err != nil {
-		log.Printf("Failed to serialize categories: %v", err)
+		return nil, fmt.Errorf("failed to serialize categories: %w", err)
 	}
-
-	// Construct the categorization record and store it in the database
-	categorizationRecord := db.CatgegoryRecord{
-		ID:              uuid.New().String(),
-		EmailID:         emailID,
-		Categories:      string(categoriesJSON),
-		ConfidenceScore: result.ConfidenceScore,
-	}
-	err = h.emailRepo.SaveCategory(ctx, categorizationRecord)
-	if err != nil {
-		log.Printf("Failed to save categorization record: %v", err)
+	storedEmail := *internalEmail
+	storedEmail.ID = storageID
+	storedEmail.ClientID = internalEmail.ID
+	if err := h.emailRepo.SaveBatch(ctx, []models.Email{storedEmail}, []db.CatgegoryRecord{categorizationRecord}); err != nil {
+		return nil, fmt.Errorf("failed to save email to the database: %w", err)
 	}
 
 	// Convert the internal categorization result into a protobuf response
 	return &pb.CategorizeResponse{
-		Result: &pb.CategoryResult{
-			Id:              result.EmailID,
-			Categories:      result.Categories,
-			ConfidenceScore: result.ConfidenceScore,
-		},
+		Result: converter.ToProtoCategoryResult(result),
 	}, nil
 }
 
+// batchItem tracks a single email through batch categorization.
+type batchItem struct {
+	email     *models.Email
+	storageID string
+	result    *models.CategoryResult
+	reused    bool // true when the result was loaded from a previous categorization
+}
+
 // BatchCategorizeEmails handles batch categorization requests. It accepts a list of emails
 // and processes them concurrently, respecting the configured worker count and maximum batch size.
-// It returns a BatchCategorizeResponse containing categorized results for each processed email.
+// Emails that were already categorized with unchanged content reuse their stored result, unless
+// the request forces re-categorization.
+// It returns a BatchCategorizeResponse containing exactly one result per input email, in the same
+// order as the request. Emails that could not be categorized are reported through the result's
+// error field, and the response carries the total number of emails and how many of them failed.
 func (h *CategorizationHandler) BatchCategorizeEmails(ctx context.Context, req *pb.BatchCategorizeRequest) (*pb.BatchCategorizeResponse, error) {
 	if len(req.Emails) > h.config.MaxBatchSize {
 		return nil, fmt.Errorf("batch size %d exceeds maximum allowed size %d", len(req.Emails), h.config.MaxBatchSize)
 	}
 
-	results := make([]*pb.CategoryResult, 0, len(req.Emails))
-	errChan := make(chan error, len(req.Emails))
-	resultChan := make(chan *pb.CategoryResult, len(req.Emails))
+	userID, err := requestUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]batchItem, len(req.Emails))
+	storageIDs := make([]string, 0, len(req.Emails))
+	for i, pbEmail := range req.Emails {
+		internalEmail := converter.FromProtoEmail(pbEmail)
+		if internalEmail == nil {
+			items[i].result = &models.CategoryResult{Error: "email is required"}
+			continue
+		}
+		internalEmail.UserID = userID
+		items[i].email = internalEmail
+		items[i].storageID = storageEmailID(internalEmail)
+		storageIDs = append(storageIDs, items[i].storageID)
+	}
+
+	// Look up every email of the batch that was categorized before in a single query
+	var stored map[string]models.StoredCategorization
+	if !req.ForceRecategorize {
+		stored = h.lookupStoredResults(ctx, storageIDs)
+	}
+
+	// Load the user's custom category rules once for the whole batch
+	userRules := h.loadUserRules(ctx, userID)
 
 	var wg sync.WaitGroup
 
 	// Process each email in a separate goroutine, limited by h.workerPool.
-	for _, pbEmail := range req.Emails {
+	// Each goroutine writes only to its own item, so no further synchronization is needed
+	// and the results keep the order of the incoming emails.
+	for i := range items {
+		item := &items[i]
+		if item.email == nil {
+			continue
+		}
+
+		if previous, ok := stored[item.storageID]; ok && reusable(&previous, item.email) {
+			result := reusedResult(&previous, item.email, item.storageID)
+			item.result = &result
+			item.reused = true
+			continue
+		}
+
 		wg.Add(1)
-		go func(pbEmail *pb.Email) {
+		go func(item *batchItem) {
 			defer wg.Done()
 
 			// Acquire a worker slot
 			h.workerPool <- struct{}{}
 			defer func() { <-h.workerPool }()
 
-			internalEmail := converter.FromProtoEmail(pbEmail)
-			result, err := h.processSingleEmail(ctx, internalEmail)
+			result, err := h.processSingleEmail(ctx, item.email, item.storageID, userID, userRules)
 			if err != nil {
-				errChan <- fmt.Errorf("email %s: %w", pbEmail.Id, err)
-				return
+				log.Printf("Failed to categorize email %s: %v", item.email.ID, err)
+				result = &models.CategoryResult{Error: err.Error()}
 			}
+			result.EmailID = responseEmailID(item.email, item.storageID)
+
+			item.result = result
+		}(item)
+	}
+
+	wg.Wait()
 
-			resultChan <- converter.ToProtoCategoryResult(result)
-		}(pbEmail)
+	// Persist the categorized emails and their results in a single transaction
+	if err := h.persistBatch(ctx, items); err != nil {
+		return nil, fmt.Errorf("failed to save batch to the database: %w", err)
 	}
 
-	// Once all goroutines complete, close the channels
-	go func() {
-		wg.Wait()
-		close(resultChan)
-		close(errChan)
-	}()
+	// Aggregate the results into the batch-level response
+	batchResponse := models.BatchEmailResponse{
+		Results: make([]models.CategoryResult, 0, len(items)),
+		Total:   int32(len(items)),
+	}
+	for _, item := range items {
+		if item.result.Error != "" {
+			batchResponse.Failed++
+		}
+		batchResponse.Results = append(batchResponse.Results, *item.result)
+	}
 
-	// Collect all results
-	for result := range resultChan {
-		results = append(results, result)
+	return converter.ToProtoBatchResponse(&batchResponse), nil
+}
+
+// persistBatch stores the emails of a batch along with their categorization records, following
+// the configured BatchPersistPolicy. Under PersistAllOrNothing, a batch containing any failed email
+// is not stored at all; under PersistSucceeded, only the successfully categorized emails are stored.
+// Emails whose stored result was reused are already persisted and are skipped.
+func (h *CategorizationHandler) persistBatch(ctx context.Context, items []batchItem) error {
+	emails := make([]models.Email, 0, len(items))
+	records := make([]db.CatgegoryRecord, 0, len(items))
+
+	for _, item := range items {
+		if item.result.Error != "" {
+			if h.config.BatchPersistPolicy == models.PersistAllOrNothing {
+				log.Printf("Skipping persistence of batch: email %s failed and policy is %s", item.result.EmailID, models.PersistAllOrNothing)
+				return nil
+			}
+			continue
+		}
+		if item.reused {
+			continue
+		}
+
+		record, err := newCategoryRecord(item.storageID, item.result)
+		if err != nil {
+			return fmt.Errorf("failed to serialize categories for email %s: %w", item.result.EmailID, err)
+		}
+
+		storedEmail := *item.email
+		storedEmail.ID = item.storageID
+		storedEmail.ClientID = item.email.ID
+
+		emails = append(emails, storedEmail)
+		records = append(records, record)
 	}
 
-	// If needed, errors can be read from errChan. Currently, the handler does not aggregate them.
+	if len(emails) == 0 {
+		return nil
+	}
 
-	return &pb.BatchCategorizeResponse{
-		Results: results,
-	}, nil
+	return h.emailRepo.SaveBatch(ctx, emails, records)
 }
 
-// processSingleEmail sends a single email to the ML service and returns the categorization result.
-// It includes a retry mechanism, attempting categorization multiple times if errors occur.
-// On success, it returns a CategoryResult with the email ID, categories, and confidence score.
-func (h *CategorizationHandler) processSingleEmail(ctx context.Context, email *models.Email) (*models.CategoryResult, error) {
+// lookupStoredResults returns the previously stored categorizations for the given storage IDs.
+// A failed lookup is logged and treated as if nothing was stored, so the emails are categorized again.
+func (h *CategorizationHandler) lookupStoredResults(ctx context.Context, storageIDs []string) map[string]models.StoredCategorization {
+	if len(storageIDs) == 0 {
+		return nil
+	}
+
+	stored, err := h.emailRepo.GetCategorizedEmails(ctx, storageIDs)
+	if err != nil {
+		log.Printf("Failed to look up stored categorizations: %v", err)
+		return nil
+	}
+	return stored
+}
+
+// processSingleEmail categorizes a single email and returns the categorization result.
+// The pipeline runs in this order:
+//   - A matching short_circuit rule decides the category without calling the ML service.
+//   - Otherwise the model the router picks for the user or the storage ID predicts the categories,
+//     with retries, and the override and boost rules adjust the prediction. A failing A/B variant
+//     hands the email over to the primary model, and the shadow models predict alongside it.
+//   - While the circuit breaker in front of the ML service is open, the configured fallback
+//     categorizes the email instead and the result is marked as degraded.
+//   - A category outside the taxonomy, or less confident than its threshold, needs review.
+//   - The user's custom category rules, if any, win over everything above.
+//   - The labels are the primary category and the most confident alternatives.
+func (h *CategorizationHandler) processSingleEmail(ctx context.Context, email *models.Email, storageID, userID string, userRules *rules.Engine) (*models.CategoryResult, error) {
+	matches := h.rules.Evaluate(email)
+	if result, ok := rules.ShortCircuit(email, matches); ok {
+		result.Provenance = h.provenance(models.DecisionRule, "", nil)
+		h.applyTaxonomy(result)
+		h.applyConfidenceThreshold(result)
+		applyUserRules(result, email, userRules)
+		h.selectLabels(result)
+		return result, nil
+	}
+
 	mlReq := &models.MLRequest{
 		ID:        email.ID,
 		Subject:   email.Subject,
@@ -160,27 +330,298 @@ func (h *CategorizationHandler) processSingleEmail(ctx context.Context, email *m
 		Headers:   email.Headers,
 	}
 
-	var serverResponse *mlpb.CategoryResponse
-	var err error
-
-	// Attempt to categorize the email multiple times if retries are configured
-	for attempt := 0; attempt < h.config.RetryAttempts; attempt++ {
-		serverResponse, err = h.mlClient.CategorizeEmail(ctx, converter.ToMLRequest(mlReq))
-		if err == nil {
-			break
+	request := converter.ToMLRequest(mlReq)
+	served := h.route(storageID, userID)
+	var cached bool
+	predictCtx := cache.WithHit(ctx, &cached)
+	serverResponse, err := h.predict(predictCtx, served.Service, request)
+	if err != nil && h.router != nil && served.Name != h.router.Primary().Name {
+		log.Printf("Model %s failed to categorize email %s, falling back to the primary model: %v", served.Name, email.ID, err)
+		served = h.router.Primary()
+		serverResponse, err = h.predict(predictCtx, served.Service, request)
+	}
+	degraded := false
+	if errors.Is(err, mlclient.ErrCircuitOpen) {
+		switch {
+		case h.config.MLFallback == models.FallbackModel && h.fallbackClient != nil:
+			serverResponse, err = h.fallbackClient.CategorizeEmail(ctx, request)
+			degraded = true
+		case h.config.MLFallback == models.FallbackRules:
+			result := fallbackResult(email, matches)
+			result.Provenance = h.provenance(models.DecisionFallback, "", nil)
+			h.applyTaxonomy(result)
+			h.applyConfidenceThreshold(result)
+			applyUserRules(result, email, userRules)
+			h.selectLabels(result)
+			return result, nil
 		}
-		log.Printf("Attempt %d failed: %v", attempt+1, err)
 	}
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to categorize email after %d attempts: %w", h.config.RetryAttempts, err)
+		return nil, err
+	}
+	if !degraded && h.router != nil && storageID != "" {
+		h.recordPredictions(ctx, storageID, request, served.Name, serverResponse)
 	}
 
 	mlResponse := converter.FromMLResponse(serverResponse)
 
-	return &models.CategoryResult{
-		EmailID:         mlResponse.ID,
+	path := models.DecisionML
+	switch {
+	case degraded:
+		path = models.DecisionFallback
+	case cached:
+		path = models.DecisionCache
+	}
+
+	result := &models.CategoryResult{
+		EmailID:         email.ID,
 		Categories:      []string{mlResponse.Category},
 		ConfidenceScore: mlResponse.ConfidenceScore,
+		Alternatives:    mlResponse.Alternatives,
+		Keywords:        mlResponse.Keywords,
+		Degraded:        degraded,
+		Provenance:      h.provenance(path, served.Name, serverResponse),
+	}
+	rules.Apply(result, matches)
+	h.applyTaxonomy(result)
+	h.applyConfidenceThreshold(result)
+	applyUserRules(result, email, userRules)
+	h.selectLabels(result)
+
+	return result, nil
+}
+
+// route returns the model serving an email with the given storage ID for a user. Without a router,
+// or without a storage ID, every email is served by the ML client, under the configured model version.
+func (h *CategorizationHandler) route(storageID, userID string) routing.Model {
+	if h.router == nil || storageID == "" {
+		return routing.Model{Name: h.config.ModelVersion, Service: h.mlClient}
+	}
+	return h.router.Route(userID, storageID)
+}
+
+// predict sends an email to a model, retrying failed attempts up to the configured number of
+// times with an exponentially growing delay between them. Requests rejected by the open circuit breaker
+// are not retried, since the ML service is known to be down.
+func (h *CategorizationHandler) predict(ctx context.Context, service mlclient.Service, request *mlpb.EmailRequest) (*mlpb.CategoryResponse, error) {
+	attempts := max(h.config.RetryAttempts, 1)
+	backoff := h.config.RetryBackoff
+
+	for attempt := 1; ; attempt++ {
+		response, err := service.CategorizeEmail(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+		if errors.Is(err, mlclient.ErrCircuitOpen) {
+			return nil, fmt.Errorf("failed to categorize email: %w", err)
+		}
+		log.Printf("Attempt %d failed: %v", attempt, err)
+		if attempt >= attempts {
+			return nil, fmt.Errorf("failed to categorize email after %d attempts: %w", attempts, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to categorize email after %d attempts: %w", attempt, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+// provenance returns the provenance of a result whose category was decided along path. The model is
+// the one named by the prediction, if there is one. A model that does not report its version is recorded
+// under the name it was served as: the configured model version, or its name in the routing file.
+func (h *CategorizationHandler) provenance(path models.DecisionPath, served string, response *mlpb.CategoryResponse) models.Provenance {
+	provenance := models.Provenance{
+		RuleSetHash:    h.rules.Hash(),
+		ServiceVersion: h.config.ServiceVersion,
+		DecisionPath:   path,
+	}
+	if response != nil {
+		provenance.ModelName = response.ModelName
+		provenance.ModelVersion = response.ModelVersion
+		if provenance.ModelVersion == "" {
+			provenance.ModelVersion = served
+		}
+	}
+	return provenance
+}
+
+// fallbackResult categorizes an email with the matching rules alone while the ML service is unavailable.
+// An email no rule matches cannot be filed, so it needs review. Either way the result is marked as degraded,
+// so it is categorized again once the ML service is back.
+func fallbackResult(email *models.Email, matches []rules.Match) *models.CategoryResult {
+	result, ok := rules.Fallback(email, matches)
+	if !ok {
+		result = &models.CategoryResult{
+			EmailID:     email.ID,
+			Categories:  []string{models.NeedsReviewCategory},
+			NeedsReview: true,
+		}
+	}
+	result.Degraded = true
+	return result
+}
+
+// applyConfidenceThreshold marks a result as needing review if its confidence is below the
+// configured threshold for its category. The result is then categorized as NeedsReviewCategory,
+// keeping the predicted category, its confidence, and the alternatives for the user to review.
+func (h *CategorizationHandler) applyConfidenceThreshold(result *models.CategoryResult) {
+	if len(result.Categories) == 0 || result.NeedsReview {
+		return
+	}
+	predicted := result.Categories[0]
+	if result.ConfidenceScore >= h.config.ThresholdFor(predicted) {
+		return
+	}
+
+	result.NeedsReview = true
+	result.PredictedCategory = predicted
+	result.Categories = rules.ReplacePrimary(result.Categories, models.NeedsReviewCategory)
+}
+
+// applyTaxonomy checks the categories of a result decided by the ML prediction or the rules against the
+// taxonomy. Alternatives outside the taxonomy are dropped, and a primary category outside the taxonomy
+// cannot be filed, so the result is marked as needing review, keeping the unknown category as the
+// predicted one. Users' custom categories are applied afterwards, since they are not in the taxonomy.
+func (h *CategorizationHandler) applyTaxonomy(result *models.CategoryResult) {
+	result.Alternatives = slices.DeleteFunc(result.Alternatives, func(alt models.Alternative) bool {
+		return !h.taxonomy.Contains(alt.Category)
+	})
+
+	if len(result.Categories) == 0 || result.NeedsReview || h.taxonomy.Contains(result.Categories[0]) {
+		return
+	}
+	log.Printf("Category %q of email %s is not in the taxonomy", result.Categories[0], result.EmailID)
+
+	result.NeedsReview = true
+	result.PredictedCategory = result.Categories[0]
+	result.Categories = rules.ReplacePrimary(result.Categories, models.NeedsReviewCategory)
+}
+
+// clearReview restores the predicted category of a result that was marked as needing review.
+func clearReview(result *models.CategoryResult) {
+	if !result.NeedsReview {
+		return
+	}
+	result.Categories = rules.ReplacePrimary(result.Categories, result.PredictedCategory)
+	result.NeedsReview = false
+	result.PredictedCategory = ""
+}
+
+// Predict runs an email through the categorization pipeline (rules, ML prediction, and retries)
+// with the primary model only, and without the database or any user's custom categories.
+// It is used to evaluate the pipeline offline, so a handler created for it needs no repositories.
+func (h *CategorizationHandler) Predict(ctx context.Context, email *models.Email) (*models.CategoryResult, error) {
+	return h.processSingleEmail(ctx, email, "", "", nil)
+}
+
+// applyUserRules applies a user's custom category rules to a categorization result.
+// A nil engine means the user has no custom categories. A matching user rule is an explicit
+// decision by the user, so it also resolves a result that needed review.
+func applyUserRules(result *models.CategoryResult, email *models.Email, userRules *rules.Engine) {
+	if userRules == nil {
+		return
+	}
+	matches := userRules.Evaluate(email)
+	if len(matches) == 0 {
+		return
+	}
+	clearReview(result)
+	rules.Apply(result, matches)
+}
+
+// emailIDNamespace is the UUID namespace used to derive storage IDs from client-supplied email IDs.
+var emailIDNamespace = uuid.MustParse("3f1c7a52-8d4e-4b8a-9a0e-6c2d5b7e9f10")
+
+// storageEmailID returns the ID under which an email is stored in the database. Client-supplied IDs
+// (such as Gmail message IDs) are only unique within a mailbox, so the storage ID is a UUID derived
+// from both the mailbox and the client ID, and from the user the email is categorized for, so users
+// never see or overwrite each other's emails. The same email therefore always maps to the same row.
+// Emails of anonymous requests keep the IDs derived from the mailbox and client ID alone.
+// Emails without a client ID get a random UUID.
+func storageEmailID(email *models.Email) string {
+	if email.ID == "" {
+		return uuid.New().String()
+	}
+	if email.UserID == "" {
+		return uuid.NewSHA1(emailIDNamespace, []byte(email.Mailbox+"\x00"+email.ID)).String()
+	}
+	return uuid.NewSHA1(emailIDNamespace, []byte(email.UserID+"\x00"+email.Mailbox+"\x00"+email.ID)).String()
+}
+
+// categoryRecordID returns the ID of the categorization record of the email with the given
+// storage ID. Re-categorizing an email replaces its record instead of adding a new one.
+func categoryRecordID(storageID string) string {
+	return uuid.NewSHA1(emailIDNamespace, []byte("category\x00"+storageID)).String()
+}
+
+// responseEmailID returns the ID reported back to the caller for an email: the client-supplied ID
+// when present, or the generated storage ID otherwise.
+func responseEmailID(email *models.Email, storageID string) string {
+	if email.ID != "" {
+		return email.ID
+	}
+	return storageID
+}
+
+// newCategoryRecord builds the categorization record stored for the email with the given storage ID.
+func newCategoryRecord(storageID string, result *models.CategoryResult) (db.CatgegoryRecord, error) {
+	// Convert the categories, alternatives, and keywords to JSON for storage
+	categoriesJSON, err := converter.ToCategoryDetailsJSON(result)
+	if err != nil {
+		return db.CatgegoryRecord{}, err
+	}
+
+	return db.CatgegoryRecord{
+		ID:              categoryRecordID(storageID),
+		EmailID:         storageID,
+		Categories:      categoriesJSON,
+		ConfidenceScore: result.ConfidenceScore,
+		NeedsReview:     result.NeedsReview,
+		ModelName:       result.Provenance.ModelName,
+		ModelVersion:    result.Provenance.ModelVersion,
+		RuleSetHash:     result.Provenance.RuleSetHash,
+		ServiceVersion:  result.Provenance.ServiceVersion,
+		DecisionPath:    string(result.Provenance.DecisionPath),
 	}, nil
 }
+
+// reusable reports whether a stored categorization can be returned for an email instead of categorizing
+// it again: the email's content must be unchanged, and the result must not come from a fallback.
+func reusable(stored *models.StoredCategorization, email *models.Email) bool {
+	return !stored.Result.Degraded && sameEmailContent(&stored.Email, email)
+}
+
+// reusedResult returns the stored result of an email, identified by its client-supplied ID. An email
+// the user corrected is filed under the corrected category alone, since the user's decision wins over
+// the prediction it corrected.
+func reusedResult(stored *models.StoredCategorization, email *models.Email, storageID string) models.CategoryResult {
+	result := stored.Result
+	result.EmailID = responseEmailID(email, storageID)
+	if stored.CorrectedCategory == "" {
+		return result
+	}
+
+	result.Categories = []string{stored.CorrectedCategory}
+	result.ConfidenceScore = 1
+	result.Labels = []models.Label{{Category: stored.CorrectedCategory, ConfidenceScore: 1}}
+	result.Alternatives = slices.DeleteFunc(slices.Clone(result.Alternatives), func(alt models.Alternative) bool {
+		return alt.Category == stored.CorrectedCategory
+	})
+	result.NeedsReview = false
+	result.PredictedCategory = ""
+	result.Provenance.DecisionPath = models.DecisionFeedback
+	return result
+}
+
+// sameEmailContent reports whether two emails have identical content, meaning a stored
+// categorization of one is still valid for the other.
+func sameEmailContent(a, b *models.Email) bool {
+	return a.Subject == b.Subject &&
+		a.Body == b.Body &&
+		a.Sender == b.Sender &&
+		slices.Equal(a.Recipients, b.Recipients) &&
+		maps.Equal(a.Headers, b.Headers)
+}
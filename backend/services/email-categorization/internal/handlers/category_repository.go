@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
+)
+
+var (
+	// ErrCategoryNotFound is returned when a user category does not exist or belongs to another user.
+	ErrCategoryNotFound = errors.New("user category not found")
+
+	// ErrCategoryExists is returned when a user already has a category with the same name.
+	ErrCategoryExists = errors.New("user category already exists")
+)
+
+// userCategoriesSchema creates the table holding users' custom categories if it does not exist yet.
+// Category names are unique per user, since they are what the user's rules assign emails to.
+const userCategoriesSchema = `
+	CREATE TABLE IF NOT EXISTS user_categories (
+		id UUID PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		rules JSONB NOT NULL DEFAULT '[]',
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL,
+		UNIQUE (user_id, name)
+	)
+`
+
+// CategoryRepository provides methods to manage users' custom categories and their rules in the database.
+type CategoryRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository with the given database connection pool.
+func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
+	return &CategoryRepository{DB: db}
+}
+
+// EnsureSchema creates the user_categories table if it does not exist yet.
+func (r *CategoryRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, userCategoriesSchema); err != nil {
+		log.Printf("Failed to create user_categories table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// CreateCategory inserts a new custom category for the category's user. The category's ID
+// must already be set. It returns ErrCategoryExists if the user has a category with the same name.
+func (r *CategoryRepository) CreateCategory(ctx context.Context, category models.UserCategory) error {
+	categoryDB, err := converter.ToUserCategoryDB(category)
+	if err != nil {
+		log.Printf("Failed to serialize category rules: %v", err)
+		return err
+	}
+
+	query := `
+		INSERT INTO user_categories (id, user_id, name, description, rules, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+	`
+
+	_, err = r.DB.Exec(ctx, query,
+		categoryDB.ID,
+		categoryDB.UserID,
+		categoryDB.Name,
+		categoryDB.Description,
+		categoryDB.Rules,
+		time.Now(),
+	)
+	if err != nil {
+		log.Printf("Failed to save user category: %v", err)
+		return translateCategoryError(err)
+	}
+
+	return nil
+}
+
+// ListCategories retrieves all custom categories of a user, ordered by name.
+func (r *CategoryRepository) ListCategories(ctx context.Context, userID string) ([]models.UserCategory, error) {
+	query := `
+		SELECT id, user_id, name, description, rules, created_at, updated_at
+		FROM user_categories
+		WHERE user_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.DB.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("Failed to retrieve user categories: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.UserCategory
+	for rows.Next() {
+		var categoryDB db.UserCategoryDB
+		err := rows.Scan(
+			&categoryDB.ID,
+			&categoryDB.UserID,
+			&categoryDB.Name,
+			&categoryDB.Description,
+			&categoryDB.Rules,
+			&categoryDB.CreatedAt,
+			&categoryDB.UpdatedAt,
+		)
+		if err != nil {
+			log.Printf("Failed to scan user category: %v", err)
+			continue
+		}
+
+		categories = append(categories, converter.FromUserCategoryDB(&categoryDB))
+	}
+
+	return categories, rows.Err()
+}
+
+// UpdateCategory replaces the name, description, and rules of an existing category. It returns
+// ErrCategoryNotFound if the category does not exist for the category's user, and ErrCategoryExists
+// if the new name is already used by another of the user's categories.
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category models.UserCategory) error {
+	categoryDB, err := converter.ToUserCategoryDB(category)
+	if err != nil {
+		log.Printf("Failed to serialize category rules: %v", err)
+		return err
+	}
+
+	query := `
+		UPDATE user_categories
+		SET name = $3, description = $4, rules = $5, updated_at = $6
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := r.DB.Exec(ctx, query,
+		categoryDB.ID,
+		categoryDB.UserID,
+		categoryDB.Name,
+		categoryDB.Description,
+		categoryDB.Rules,
+		time.Now(),
+	)
+	if err != nil {
+		log.Printf("Failed to update user category: %v", err)
+		return translateCategoryError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// DeleteCategory removes a user's category. It returns ErrCategoryNotFound if the category
+// does not exist for that user.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, userID, id string) error {
+	query := `
+		DELETE FROM user_categories
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := r.DB.Exec(ctx, query, id, userID)
+	if err != nil {
+		log.Printf("Failed to delete user category: %v", err)
+		return translateCategoryError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// translateCategoryError maps database errors to the repository's sentinel errors where possible.
+// A malformed category ID cannot match any stored category, so it is reported as not found.
+func translateCategoryError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return ErrCategoryExists
+		case "22P02": // invalid_text_representation
+			return ErrCategoryNotFound
+		}
+	}
+	return err
+}
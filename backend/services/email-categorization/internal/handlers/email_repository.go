@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models/db"
@@ -24,19 +25,120 @@ func NewEmailRepository(db *pgxpool.Pool) *EmailRepository {
 	return &EmailRepository{DB: db}
 }
 
-// SaveEmail inserts a new email record into the database. It first converts the in-memory Email model
+// ErrEmailNotCategorized is returned when feedback is submitted for an email that has no stored categorization.
+var ErrEmailNotCategorized = errors.New("email has not been categorized")
+
+// feedbackSchema adds the columns holding user corrections to the categories table if they do not
+// exist yet. The corrections live next to the original prediction and confidence score, and are cleared
+// when an email is re-categorized, since they were made for the previous prediction.
+const feedbackSchema = `
+	ALTER TABLE categories
+		ADD COLUMN IF NOT EXISTS corrected_category TEXT,
+		ADD COLUMN IF NOT EXISTS feedback_user_id TEXT,
+		ADD COLUMN IF NOT EXISTS corrected_at TIMESTAMPTZ
+`
+
+// reviewSchema adds the columns used to list the emails awaiting review if they do not exist yet.
+// The review flag is kept in its own column so unreviewed emails can be queried efficiently, and
+// the client-supplied ID and mailbox are kept so those emails can be reported back to the client.
+const reviewSchema = `
+	ALTER TABLE emails
+		ADD COLUMN IF NOT EXISTS client_id TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS mailbox TEXT NOT NULL DEFAULT '';
+	ALTER TABLE categories
+		ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT false;
+	CREATE INDEX IF NOT EXISTS categories_needs_review_idx
+		ON categories (email_id) WHERE needs_review AND corrected_category IS NULL
+`
+
+// provenanceSchema adds the columns recording what produced every categorization if they do not exist
+// yet. They are kept in columns of their own, so the results of a given model or rule set can be found
+// and recomputed after an upgrade.
+const provenanceSchema = `
+	ALTER TABLE categories
+		ADD COLUMN IF NOT EXISTS model_name TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS model_version TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS rule_set_hash TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS service_version TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS decision_path TEXT NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS categories_model_version_idx ON categories (model_name, model_version)
+`
+
+// ownerSchema adds the column recording the user every email was categorized for if it does not exist
+// yet, so the emails of a mailbox can be listed and counted for their user only. Emails stored before
+// users were authenticated belong to no user.
+const ownerSchema = `
+	ALTER TABLE emails
+		ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+	CREATE INDEX IF NOT EXISTS emails_user_id_mailbox_idx ON emails (user_id, mailbox)
+`
+
+// saveEmailQuery inserts an email, or updates its content if an email with the same ID
+// was already stored. The original created_at timestamp and user are kept on update.
+const saveEmailQuery = `
+	INSERT INTO emails (id, headers, subject, sender, recipients, body, created_at, client_id, mailbox, user_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (id) DO UPDATE SET
+		headers = EXCLUDED.headers,
+		subject = EXCLUDED.subject,
+		sender = EXCLUDED.sender,
+		recipients = EXCLUDED.recipients,
+		body = EXCLUDED.body
+`
+
+// saveCategoryQuery inserts a categorization record, or replaces the stored categories, confidence
+// score, review flag, and provenance if a record with the same ID already exists. Replacing a record
+// clears the user's correction of it, which no longer applies to the new prediction.
+const saveCategoryQuery = `
+	INSERT INTO categories (id, email_id, categories, confidence_score, created_at, needs_review,
+		model_name, model_version, rule_set_hash, service_version, decision_path)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (id) DO UPDATE SET
+		categories = EXCLUDED.categories,
+		confidence_score = EXCLUDED.confidence_score,
+		created_at = EXCLUDED.created_at,
+		needs_review = EXCLUDED.needs_review,
+		model_name = EXCLUDED.model_name,
+		model_version = EXCLUDED.model_version,
+		rule_set_hash = EXCLUDED.rule_set_hash,
+		service_version = EXCLUDED.service_version,
+		decision_path = EXCLUDED.decision_path,
+		corrected_category = NULL,
+		feedback_user_id = NULL,
+		corrected_at = NULL
+`
+
+// EnsureSchema adds the columns used for user feedback, for reviewing unconfident predictions,
+// for recording the provenance of categorizations, and for scoping emails to their users to the
+// emails and categories tables if they do not exist yet.
+func (r *EmailRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, feedbackSchema); err != nil {
+		log.Printf("Failed to add feedback columns to categories table: %v", err)
+		return err
+	}
+	if _, err := r.DB.Exec(ctx, reviewSchema); err != nil {
+		log.Printf("Failed to add review columns: %v", err)
+		return err
+	}
+	if _, err := r.DB.Exec(ctx, provenanceSchema); err != nil {
+		log.Printf("Failed to add provenance columns: %v", err)
+		return err
+	}
+	if _, err := r.DB.Exec(ctx, ownerSchema); err != nil {
+		log.Printf("Failed to add owner column to emails table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SaveEmail upserts an email record into the database. It first converts the in-memory Email model
 // into a database-specific model structure. If successful, the email is stored along with a timestamp
-// indicating when it was created.
+// indicating when it was created. Saving an email whose ID already exists updates its content.
 func (r *EmailRepository) SaveEmail(ctx context.Context, email models.Email) error {
 	// Convert from service-level model to database-level model
 	emailDB := converter.FromServiceModel(email)
 
-	query := `
-		INSERT INTO emails (id, headers, subject, sender, recipients, body, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
-
-	_, err := r.DB.Exec(ctx, query,
+	_, err := r.DB.Exec(ctx, saveEmailQuery,
 		emailDB.ID,
 		emailDB.Headers,
 		emailDB.Subject,
@@ -44,6 +146,9 @@ func (r *EmailRepository) SaveEmail(ctx context.Context, email models.Email) err
 		emailDB.Recipients,
 		emailDB.Body,
 		time.Now(),
+		emailDB.ClientID,
+		emailDB.Mailbox,
+		emailDB.UserID,
 	)
 	if err != nil {
 		log.Printf("Failed to save email: %v", err)
@@ -54,38 +159,390 @@ func (r *EmailRepository) SaveEmail(ctx context.Context, email models.Email) err
 	return nil
 }
 
-// SaveCategory stores a categorization record in the database. The categories are converted to JSON
-// before insertion. The record includes the email ID, the categorized labels, the confidence score,
-// and a timestamp of when it was created.
+// SaveCategory upserts a categorization record in the database. The record's categories are expected
+// to already be JSON-encoded. The record includes the email ID, the categorized labels, the confidence
+// score, and a timestamp of when it was created. Saving a record whose ID already exists replaces it.
 func (r *EmailRepository) SaveCategory(ctx context.Context, record db.CatgegoryRecord) error {
-	query := `
-		INSERT INTO categories (id, email_id, categories, confidence_score, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-
-	// Serialize the categories map to JSON for storage
-	categoriesJSON, err := json.Marshal(record.Categories)
-	if err != nil {
-		log.Printf("Failed to serialize categories: %v", err)
-		return err
-	}
-
-	_, dbErr := r.DB.Exec(ctx, query,
+	_, err := r.DB.Exec(ctx, saveCategoryQuery,
 		record.ID,
 		record.EmailID,
-		categoriesJSON,
+		record.Categories,
 		record.ConfidenceScore,
 		time.Now(),
+		record.NeedsReview,
+		record.ModelName,
+		record.ModelVersion,
+		record.RuleSetHash,
+		record.ServiceVersion,
+		record.DecisionPath,
 	)
-	if dbErr != nil {
-		log.Printf("Failed to save categorization record: %v", dbErr)
-		return dbErr
+	if err != nil {
+		log.Printf("Failed to save categorization record: %v", err)
+		return err
 	}
 
 	log.Println("Categorization record saved successfully.")
 	return nil
 }
 
+// SaveBatch upserts a batch of emails together with their categorization records in a single
+// transaction. All statements are queued into one pgx batch, so the batch is sent to the database
+// in a single round-trip. If any statement fails, the transaction is rolled back and nothing from
+// the batch is stored.
+func (r *EmailRepository) SaveBatch(ctx context.Context, emails []models.Email, records []db.CatgegoryRecord) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		log.Printf("Failed to begin batch transaction: %v", err)
+		return err
+	}
+	// Rollback is a no-op once the transaction has been committed.
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+
+	// Queue the emails first so the categorization records can reference them.
+	for _, email := range emails {
+		emailDB := converter.FromServiceModel(email)
+		batch.Queue(saveEmailQuery,
+			emailDB.ID,
+			emailDB.Headers,
+			emailDB.Subject,
+			emailDB.Sender,
+			emailDB.Recipients,
+			emailDB.Body,
+			now,
+			emailDB.ClientID,
+			emailDB.Mailbox,
+			emailDB.UserID,
+		)
+	}
+
+	for _, record := range records {
+		batch.Queue(saveCategoryQuery,
+			record.ID,
+			record.EmailID,
+			record.Categories,
+			record.ConfidenceScore,
+			now,
+			record.NeedsReview,
+			record.ModelName,
+			record.ModelVersion,
+			record.RuleSetHash,
+			record.ServiceVersion,
+			record.DecisionPath,
+		)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		log.Printf("Failed to save batch: %v", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("Failed to commit batch transaction: %v", err)
+		return err
+	}
+
+	log.Printf("Batch of %d emails saved successfully", len(emails))
+	return nil
+}
+
+// SaveFeedback records a user's corrected category on the most recent categorization record of an email,
+// keeping the original prediction and confidence score. It returns the updated record, or
+// ErrEmailNotCategorized if the email has no categorization record.
+func (r *EmailRepository) SaveFeedback(ctx context.Context, feedback models.Feedback) (db.CatgegoryRecord, error) {
+	query := `
+		UPDATE categories
+		SET corrected_category = $2, feedback_user_id = $3, corrected_at = $4
+		WHERE id = (
+			SELECT id FROM categories
+			WHERE email_id = $1
+			ORDER BY created_at DESC
+			LIMIT 1
+		)
+		RETURNING id, email_id, categories, confidence_score, created_at,
+			corrected_category, feedback_user_id, corrected_at
+	`
+
+	var record db.CatgegoryRecord
+	err := r.DB.QueryRow(ctx, query,
+		feedback.EmailID,
+		feedback.CorrectedCategory,
+		feedback.UserID,
+		time.Now(),
+	).Scan(
+		&record.ID,
+		&record.EmailID,
+		&record.Categories,
+		&record.ConfidenceScore,
+		&record.CreatedAt,
+		&record.CorrectedCategory,
+		&record.FeedbackUserID,
+		&record.CorrectedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.CatgegoryRecord{}, ErrEmailNotCategorized
+	}
+	if err != nil {
+		log.Printf("Failed to save feedback: %v", err)
+		return db.CatgegoryRecord{}, err
+	}
+
+	log.Println("Feedback saved successfully.")
+	return record, nil
+}
+
+// GetCategorizedEmails retrieves the stored emails with the given IDs together with their most
+// recent categorization record and the user's correction of it, if any. Emails that have not been
+// categorized yet are not returned. The result is keyed by email ID.
+func (r *EmailRepository) GetCategorizedEmails(ctx context.Context, ids []string) (map[string]models.StoredCategorization, error) {
+	query := `
+		SELECT DISTINCT ON (e.id)
+			e.id, e.headers, e.subject, e.sender, e.recipients, e.body, e.created_at,
+			c.id, c.email_id, c.categories, c.confidence_score, c.created_at,
+			c.model_name, c.model_version, c.rule_set_hash, c.service_version, c.decision_path,
+			c.corrected_category
+		FROM emails e
+		JOIN categories c ON c.email_id = e.id
+		WHERE e.id = ANY($1)
+		ORDER BY e.id, c.created_at DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, ids)
+	if err != nil {
+		log.Printf("Failed to retrieve categorized emails: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stored := make(map[string]models.StoredCategorization, len(ids))
+	for rows.Next() {
+		var emailDB db.EmailDB
+		var record db.CatgegoryRecord
+		err := rows.Scan(
+			&emailDB.ID,
+			&emailDB.Headers,
+			&emailDB.Subject,
+			&emailDB.Sender,
+			&emailDB.Recipients,
+			&emailDB.Body,
+			&emailDB.CreatedAt,
+			&record.ID,
+			&record.EmailID,
+			&record.Categories,
+			&record.ConfidenceScore,
+			&record.CreatedAt,
+			&record.ModelName,
+			&record.ModelVersion,
+			&record.RuleSetHash,
+			&record.ServiceVersion,
+			&record.DecisionPath,
+			&record.CorrectedCategory,
+		)
+		if err != nil {
+			log.Printf("Failed to scan categorized email: %v", err)
+			continue
+		}
+
+		categorization := models.StoredCategorization{
+			Email:  converter.ToServiceModel(&emailDB),
+			Result: converter.FromCategoryRecord(&record),
+		}
+		if record.CorrectedCategory != nil {
+			categorization.CorrectedCategory = *record.CorrectedCategory
+		}
+		stored[emailDB.ID] = categorization
+	}
+
+	return stored, rows.Err()
+}
+
+// GetLabeledEmails retrieves the stored emails matching the filter together with their most recent
+// categorization record. Uncorrected records made by a fallback while the ML service was down are
+// left out, so the guesses of the rules or the embedded model do not become training labels. The
+// result is ordered by the time the emails were stored.
+func (r *EmailRepository) GetLabeledEmails(ctx context.Context, filter models.LabeledEmailFilter) ([]models.LabeledEmail, error) {
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (e.id)
+				e.id, e.headers, e.subject, e.sender, e.recipients, e.body,
+				e.created_at AS email_created_at,
+				c.id AS category_id, c.email_id, c.categories, c.confidence_score,
+				c.created_at AS category_created_at,
+				c.corrected_category, c.feedback_user_id, c.corrected_at, c.decision_path
+			FROM emails e
+			JOIN categories c ON c.email_id = e.id
+			WHERE ($1::timestamptz IS NULL OR e.created_at >= $1)
+				AND ($2::timestamptz IS NULL OR e.created_at < $2)
+			ORDER BY e.id, c.created_at DESC
+		) latest
+		WHERE latest.corrected_category IS NOT NULL
+			OR (latest.confidence_score >= $3 AND latest.decision_path <> 'fallback')
+		ORDER BY latest.email_created_at
+	`
+
+	rows, err := r.DB.Query(ctx, query, filter.From, filter.To, filter.MinConfidence)
+	if err != nil {
+		log.Printf("Failed to retrieve labeled emails: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labeled []models.LabeledEmail
+	for rows.Next() {
+		var emailDB db.EmailDB
+		var record db.CatgegoryRecord
+		err := rows.Scan(
+			&emailDB.ID,
+			&emailDB.Headers,
+			&emailDB.Subject,
+			&emailDB.Sender,
+			&emailDB.Recipients,
+			&emailDB.Body,
+			&emailDB.CreatedAt,
+			&record.ID,
+			&record.EmailID,
+			&record.Categories,
+			&record.ConfidenceScore,
+			&record.CreatedAt,
+			&record.CorrectedCategory,
+			&record.FeedbackUserID,
+			&record.CorrectedAt,
+			&record.DecisionPath,
+		)
+		if err != nil {
+			log.Printf("Failed to scan labeled email: %v", err)
+			continue
+		}
+
+		labeledEmail := models.LabeledEmail{
+			Email:     converter.ToServiceModel(&emailDB),
+			Result:    converter.FromCategoryRecord(&record),
+			CreatedAt: emailDB.CreatedAt,
+		}
+		if record.CorrectedCategory != nil {
+			labeledEmail.CorrectedCategory = *record.CorrectedCategory
+		}
+		labeled = append(labeled, labeledEmail)
+	}
+
+	return labeled, rows.Err()
+}
+
+// GetReviewEmails retrieves the emails of a user's mailbox whose most recent categorization needs review
+// and has not been corrected by the user yet, newest first. At most limit emails are returned.
+func (r *EmailRepository) GetReviewEmails(ctx context.Context, userID, mailbox string, limit int) ([]models.StoredCategorization, error) {
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (e.id)
+				e.id, e.client_id, e.mailbox, e.user_id, e.headers, e.subject, e.sender, e.recipients, e.body,
+				e.created_at AS email_created_at,
+				c.id AS category_id, c.email_id, c.categories, c.confidence_score,
+				c.created_at AS category_created_at,
+				c.needs_review, c.corrected_category,
+				c.model_name, c.model_version, c.rule_set_hash, c.service_version, c.decision_path
+			FROM emails e
+			JOIN categories c ON c.email_id = e.id
+			WHERE e.user_id = $1 AND e.mailbox = $2
+			ORDER BY e.id, c.created_at DESC
+		) latest
+		WHERE latest.needs_review AND latest.corrected_category IS NULL
+		ORDER BY latest.category_created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.DB.Query(ctx, query, userID, mailbox, limit)
+	if err != nil {
+		log.Printf("Failed to retrieve emails awaiting review: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []models.StoredCategorization
+	for rows.Next() {
+		var emailDB db.EmailDB
+		var record db.CatgegoryRecord
+		err := rows.Scan(
+			&emailDB.ID,
+			&emailDB.ClientID,
+			&emailDB.Mailbox,
+			&emailDB.UserID,
+			&emailDB.Headers,
+			&emailDB.Subject,
+			&emailDB.Sender,
+			&emailDB.Recipients,
+			&emailDB.Body,
+			&emailDB.CreatedAt,
+			&record.ID,
+			&record.EmailID,
+			&record.Categories,
+			&record.ConfidenceScore,
+			&record.CreatedAt,
+			&record.NeedsReview,
+			&record.CorrectedCategory,
+			&record.ModelName,
+			&record.ModelVersion,
+			&record.RuleSetHash,
+			&record.ServiceVersion,
+			&record.DecisionPath,
+		)
+		if err != nil {
+			log.Printf("Failed to scan email awaiting review: %v", err)
+			continue
+		}
+
+		emails = append(emails, models.StoredCategorization{
+			Email:  converter.ToServiceModel(&emailDB),
+			Result: converter.FromCategoryRecord(&record),
+		})
+	}
+
+	return emails, rows.Err()
+}
+
+// CountCategories counts the emails of a user's mailbox by their current category: the category the user
+// corrected the email to, or otherwise the primary category of its most recent categorization.
+// The result is keyed by category.
+func (r *EmailRepository) CountCategories(ctx context.Context, userID, mailbox string) (map[string]int, error) {
+	query := `
+		SELECT category, COUNT(*) FROM (
+			SELECT DISTINCT ON (e.id)
+				COALESCE(
+					c.corrected_category,
+					c.categories::jsonb -> 'categories' ->> 0,
+					c.categories::jsonb ->> 0
+				) AS category
+			FROM emails e
+			JOIN categories c ON c.email_id = e.id
+			WHERE e.user_id = $1 AND e.mailbox = $2
+			ORDER BY e.id, c.created_at DESC
+		) latest
+		WHERE category IS NOT NULL
+		GROUP BY category
+	`
+
+	rows, err := r.DB.Query(ctx, query, userID, mailbox)
+	if err != nil {
+		log.Printf("Failed to count categories: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			log.Printf("Failed to scan category count: %v", err)
+			continue
+		}
+		counts[category] = count
+	}
+
+	return counts, rows.Err()
+}
+
 // GetEmails retrieves all email records from the database and converts them into service-level Email models.
 // It returns a slice of Emails and an error if something goes wrong during query or row scanning.
 func (r *EmailRepository) GetEmails(ctx context.Context) ([]models.Email, error) {
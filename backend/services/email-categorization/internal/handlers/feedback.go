@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
+
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// SubmitFeedback records that a user moved an email to a different category than the one predicted.
+// The email is identified by the same client-supplied ID, mailbox, and user used to categorize it. The
+// correction is stored on the email's categorization record next to the original prediction and
+// confidence score, which are returned along with the correction.
+func (h *CategorizationHandler) SubmitFeedback(ctx context.Context, req *pb.SubmitFeedbackRequest) (*pb.SubmitFeedbackResponse, error) {
+	if req.EmailId == "" {
+		return nil, status.Error(codes.InvalidArgument, "email_id is required")
+	}
+	if req.CorrectedCategory == "" {
+		return nil, status.Error(codes.InvalidArgument, "corrected_category is required")
+	}
+
+	userID, err := requestUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	storageID := storageEmailID(&models.Email{ID: req.EmailId, Mailbox: req.Mailbox, UserID: userID})
+
+	record, err := h.emailRepo.SaveFeedback(ctx, models.Feedback{
+		EmailID:           storageID,
+		UserID:            userID,
+		CorrectedCategory: req.CorrectedCategory,
+	})
+	if errors.Is(err, ErrEmailNotCategorized) {
+		return nil, status.Errorf(codes.NotFound, "email %s has not been categorized", req.EmailId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save feedback: %v", err)
+	}
+
+	prediction := converter.FromCategoryRecord(&record)
+
+	return &pb.SubmitFeedbackResponse{
+		EmailId:             req.EmailId,
+		PredictedCategories: prediction.Categories,
+		ConfidenceScore:     prediction.ConfidenceScore,
+		CorrectedCategory:   req.CorrectedCategory,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import "github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+
+// selectLabels assigns the labels of a categorization result, following the configured
+// LabelSelection. The primary category is always the first label; further labels are taken
+// from the ranked alternatives:
+//   - LabelsTopK takes the most confident alternatives until MaxLabels labels are assigned.
+//   - LabelsCumulative also stops once the combined score of the labels reaches CumulativeLabelScore.
+//
+// Alternatives scoring below MinLabelScore never become labels. The chosen alternatives are moved
+// to the labels, and Categories lists the labels' categories. An email awaiting review is only
+// labeled as NeedsReviewCategory, since none of its predictions were trusted to file it.
+func (h *CategorizationHandler) selectLabels(result *models.CategoryResult) {
+	if len(result.Categories) == 0 {
+		return
+	}
+
+	result.Labels = []models.Label{{Category: result.Categories[0], ConfidenceScore: result.ConfidenceScore}}
+	result.Categories = []string{result.Categories[0]}
+	if result.NeedsReview {
+		return
+	}
+
+	total := result.ConfidenceScore
+	remaining := make([]models.Alternative, 0, len(result.Alternatives))
+	for _, alt := range result.Alternatives {
+		if !h.acceptsLabel(len(result.Labels), total, alt.ConfidenceScore) {
+			remaining = append(remaining, alt)
+			continue
+		}
+		result.Labels = append(result.Labels, models.Label{Category: alt.Category, ConfidenceScore: alt.ConfidenceScore})
+		result.Categories = append(result.Categories, alt.Category)
+		total += alt.ConfidenceScore
+	}
+	result.Alternatives = remaining
+}
+
+// acceptsLabel reports whether an alternative with the given score becomes another label,
+// given the number of labels assigned so far and their combined score.
+func (h *CategorizationHandler) acceptsLabel(labels int, total, score float32) bool {
+	if labels >= h.config.MaxLabels || score < h.config.MinLabelScore {
+		return false
+	}
+	if h.config.LabelSelection == models.LabelsCumulative {
+		return total < h.config.CumulativeLabelScore
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models/db"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
+)
+
+// modelPredictionsSchema creates the table holding the predictions of the models emails are routed to
+// if it does not exist yet. Every prediction is a row of its own, so an email re-categorized after a
+// rollout keeps the predictions of the earlier models.
+const modelPredictionsSchema = `
+	CREATE TABLE IF NOT EXISTS model_predictions (
+		id BIGSERIAL PRIMARY KEY,
+		email_id TEXT NOT NULL,
+		model TEXT NOT NULL,
+		shadow BOOLEAN NOT NULL,
+		category TEXT NOT NULL,
+		confidence REAL NOT NULL,
+		served_model TEXT NOT NULL,
+		served_category TEXT NOT NULL,
+		served_confidence REAL NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS model_predictions_created_at_idx ON model_predictions (created_at)
+`
+
+// saveModelPredictionQuery inserts the prediction of a single model.
+const saveModelPredictionQuery = `
+	INSERT INTO model_predictions (email_id, model, shadow, category, confidence, served_model,
+		served_category, served_confidence, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+// PredictionRepository provides methods to store and read the predictions of the models emails are
+// routed to, served and shadow alike, so the models can be compared.
+type PredictionRepository struct {
+	// DB is the pooled database connection used for all queries.
+	DB *pgxpool.Pool
+}
+
+// NewPredictionRepository creates a new instance of PredictionRepository with the given database connection pool.
+func NewPredictionRepository(db *pgxpool.Pool) *PredictionRepository {
+	return &PredictionRepository{DB: db}
+}
+
+// EnsureSchema creates the model_predictions table if it does not exist yet.
+func (r *PredictionRepository) EnsureSchema(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, modelPredictionsSchema); err != nil {
+		log.Printf("Failed to create model_predictions table: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SavePredictions inserts the predictions the models made for an email, queued into one pgx batch
+// so they are sent to the database in a single round-trip.
+func (r *PredictionRepository) SavePredictions(ctx context.Context, predictions []models.ModelPrediction) error {
+	batch := &pgx.Batch{}
+	for _, prediction := range predictions {
+		record := converter.ToModelPredictionDB(prediction)
+		batch.Queue(saveModelPredictionQuery,
+			record.EmailID,
+			record.Model,
+			record.Shadow,
+			record.Category,
+			record.Confidence,
+			record.ServedModel,
+			record.ServedCategory,
+			record.ServedConfidence,
+			record.CreatedAt,
+		)
+	}
+
+	if err := r.DB.SendBatch(ctx, batch).Close(); err != nil {
+		log.Printf("Failed to save model predictions: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetPredictions retrieves the predictions made within [from, to), oldest first, along with the
+// category the user corrected each email to, if any. A nil from or to leaves that end of the range open.
+func (r *PredictionRepository) GetPredictions(ctx context.Context, from, to *time.Time) ([]models.ModelPrediction, error) {
+	query := `
+		SELECT p.id, p.email_id, p.model, p.shadow, p.category, p.confidence, p.served_model,
+			p.served_category, p.served_confidence, p.created_at, correction.corrected_category
+		FROM model_predictions p
+		LEFT JOIN LATERAL (
+			SELECT c.corrected_category
+			FROM categories c
+			WHERE c.email_id = p.email_id AND c.corrected_category IS NOT NULL
+			ORDER BY c.corrected_at DESC
+			LIMIT 1
+		) correction ON true
+		WHERE ($1::timestamptz IS NULL OR p.created_at >= $1)
+			AND ($2::timestamptz IS NULL OR p.created_at < $2)
+		ORDER BY p.id
+	`
+
+	rows, err := r.DB.Query(ctx, query, from, to)
+	if err != nil {
+		log.Printf("Failed to retrieve model predictions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var predictions []models.ModelPrediction
+	for rows.Next() {
+		var record db.ModelPredictionDB
+		err := rows.Scan(
+			&record.ID,
+			&record.EmailID,
+			&record.Model,
+			&record.Shadow,
+			&record.Category,
+			&record.Confidence,
+			&record.ServedModel,
+			&record.ServedCategory,
+			&record.ServedConfidence,
+			&record.CreatedAt,
+			&record.CorrectedCategory,
+		)
+		if err != nil {
+			log.Printf("Failed to scan model prediction: %v", err)
+			continue
+		}
+		predictions = append(predictions, converter.FromModelPredictionDB(&record))
+	}
+
+	return predictions, rows.Err()
+}
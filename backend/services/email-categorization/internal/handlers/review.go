@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
+
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// Limits on the number of emails returned by ListReviewEmails.
+const (
+	defaultReviewLimit = 50
+	maxReviewLimit     = 500
+)
+
+// ListReviewEmails returns the emails of the requesting user's mailbox whose prediction was not
+// confident enough to file them automatically and that the user has not corrected yet, newest first.
+// Each email is returned with its client-supplied ID along with the predicted category and
+// alternatives to choose from. Submitting feedback for an email removes it from the list.
+func (h *CategorizationHandler) ListReviewEmails(ctx context.Context, req *pb.ListReviewEmailsRequest) (*pb.ListReviewEmailsResponse, error) {
+	limit := int(req.Limit)
+	if limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	if limit == 0 {
+		limit = defaultReviewLimit
+	}
+	limit = min(limit, maxReviewLimit)
+
+	userID, err := requestUser(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	stored, err := h.emailRepo.GetReviewEmails(ctx, userID, req.Mailbox, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list emails awaiting review: %v", err)
+	}
+
+	response := &pb.ListReviewEmailsResponse{
+		Emails: make([]*pb.ReviewEmail, 0, len(stored)),
+	}
+	for _, entry := range stored {
+		// Report the emails by the ID the client knows them by.
+		email := entry.Email
+		email.ID = responseEmailID(&models.Email{ID: email.ClientID}, email.ID)
+		result := entry.Result
+		result.EmailID = email.ID
+
+		response.Emails = append(response.Emails, &pb.ReviewEmail{
+			Email:  converter.ToProtoEmail(&email),
+			Result: converter.ToProtoCategoryResult(&result),
+		})
+	}
+
+	return response, nil
+}
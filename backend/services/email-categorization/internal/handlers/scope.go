@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/common/utils"
+)
+
+// requestUser returns the user a request is made for, whose emails and categories it may access.
+// Calls through the API Gateway carry the authenticated user's ID as metadata, which takes precedence
+// over the user ID in the request itself: a request naming another user is rejected with PermissionDenied,
+// and a request naming none is made for the authenticated user. Calls without the metadata, from trusted
+// internal callers or a gateway with authentication disabled, are made for the user the request names, if any.
+func requestUser(ctx context.Context, requested string) (string, error) {
+	authenticated := utils.UserIDFromContext(ctx)
+	if authenticated == "" {
+		return requested, nil
+	}
+	if requested != "" && requested != authenticated {
+		return "", status.Error(codes.PermissionDenied, "request is not allowed for another user")
+	}
+	return authenticated, nil
+}
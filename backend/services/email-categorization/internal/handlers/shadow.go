@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+
+	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
+)
+
+// shadowTimeout bounds how long the shadow models of an email may take, so a hanging shadow
+// model never holds a slot of the shadow pool for long.
+const shadowTimeout = 30 * time.Second
+
+// recordPredictions sends an email to the shadow models of the model that served it and stores
+// their predictions along with the served one, in the background so the caller does not wait for
+// them. Shadow models are called once, without retries, and their failures are only logged.
+// While NumWorkers emails are already being recorded, further emails are not, so a slow shadow
+// model or database never builds up a backlog.
+func (h *CategorizationHandler) recordPredictions(ctx context.Context, storageID string, request *mlpb.EmailRequest, served string, response *mlpb.CategoryResponse) {
+	select {
+	case h.shadowPool <- struct{}{}:
+	default:
+		log.Printf("Skipping shadow predictions of email %s: too many in flight", request.Id)
+		return
+	}
+
+	// The request may complete before the shadow models do, so they must not share its cancellation
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowTimeout)
+	go func() {
+		defer func() { <-h.shadowPool }()
+		defer cancel()
+
+		now := time.Now()
+		servedPrediction := models.ModelPrediction{
+			EmailID:          storageID,
+			Model:            served,
+			Category:         response.Category,
+			Confidence:       response.Confidence,
+			ServedModel:      served,
+			ServedCategory:   response.Category,
+			ServedConfidence: response.Confidence,
+			CreatedAt:        now,
+		}
+
+		shadows := h.router.Shadows(served)
+		predictions := make([]*models.ModelPrediction, len(shadows))
+		var wg sync.WaitGroup
+		for i, shadow := range shadows {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				shadowResponse, err := shadow.Service.CategorizeEmail(ctx, request)
+				if err != nil {
+					log.Printf("Shadow model %s failed to categorize email %s: %v", shadow.Name, request.Id, err)
+					return
+				}
+				log.Printf("Shadow model %s predicted %s (%.2f) for email %s, served model %s predicted %s (%.2f)",
+					shadow.Name, shadowResponse.Category, shadowResponse.Confidence, request.Id,
+					served, response.Category, response.Confidence)
+
+				prediction := servedPrediction
+				prediction.Model = shadow.Name
+				prediction.Shadow = true
+				prediction.Category = shadowResponse.Category
+				prediction.Confidence = shadowResponse.Confidence
+				predictions[i] = &prediction
+			}()
+		}
+		wg.Wait()
+
+		records := []models.ModelPrediction{servedPrediction}
+		for _, prediction := range predictions {
+			if prediction != nil {
+				records = append(records, *prediction)
+			}
+		}
+		if err := h.predictionRepo.SavePredictions(ctx, records); err != nil {
+			log.Printf("Failed to save model predictions of email %s: %v", request.Id, err)
+		}
+	}()
+}
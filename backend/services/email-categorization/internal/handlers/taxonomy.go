@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// GetTaxonomy returns the category taxonomy as a tree, with the top-level categories first and every
+// category's subcategories nested under it, in the order they were defined. Each category carries the
+// number of emails of the requesting user's mailbox filed directly under it (count) and under it or
+// any of its subcategories (total).
+func (h *CategorizationHandler) GetTaxonomy(ctx context.Context, req *pb.GetTaxonomyRequest) (*pb.GetTaxonomyResponse, error) {
+	userID, err := requestUser(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	counts, err := h.emailRepo.CountCategories(ctx, userID, req.Mailbox)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count categories: %v", err)
+	}
+	totals := h.taxonomy.RollUp(counts)
+
+	// buildNode converts a category and, recursively, its subcategories into protobuf nodes.
+	var buildNode func(id string) *pb.CategoryNode
+	buildNode = func(id string) *pb.CategoryNode {
+		node, _ := h.taxonomy.Node(id)
+		pbNode := &pb.CategoryNode{
+			Id:       node.ID,
+			Name:     node.Name,
+			ParentId: node.Parent,
+			Count:    int32(counts[id]),
+			Total:    int32(totals[id]),
+		}
+		for _, child := range h.taxonomy.Children(id) {
+			pbNode.Children = append(pbNode.Children, buildNode(child))
+		}
+		return pbNode
+	}
+
+	response := &pb.GetTaxonomyResponse{}
+	for _, root := range h.taxonomy.Roots() {
+		response.Categories = append(response.Categories, buildNode(root))
+	}
+	return response, nil
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/rules"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/utils/converter"
+
+	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
+)
+
+// CreateUserCategory stores a new custom category for a user. The category is assigned a new ID,
+// and its rules are validated before it is saved. Users authenticated by the API Gateway may only
+// manage their own categories.
+func (h *CategorizationHandler) CreateUserCategory(ctx context.Context, req *pb.CreateUserCategoryRequest) (*pb.UserCategoryResponse, error) {
+	category := converter.FromProtoUserCategory(req.Category)
+	if err := validateUserCategory(category); err != nil {
+		return nil, err
+	}
+	if _, err := requestUser(ctx, category.UserID); err != nil {
+		return nil, err
+	}
+	category.ID = uuid.New().String()
+
+	if err := h.categoryRepo.CreateCategory(ctx, *category); err != nil {
+		return nil, categoryStatusError(err)
+	}
+
+	return &pb.UserCategoryResponse{Category: converter.ToProtoUserCategory(category)}, nil
+}
+
+// ListUserCategories returns all custom categories of a user.
+func (h *CategorizationHandler) ListUserCategories(ctx context.Context, req *pb.ListUserCategoriesRequest) (*pb.ListUserCategoriesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if _, err := requestUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	categories, err := h.categoryRepo.ListCategories(ctx, req.UserId)
+	if err != nil {
+		return nil, categoryStatusError(err)
+	}
+
+	response := &pb.ListUserCategoriesResponse{
+		Categories: make([]*pb.UserCategory, len(categories)),
+	}
+	for i := range categories {
+		response.Categories[i] = converter.ToProtoUserCategory(&categories[i])
+	}
+	return response, nil
+}
+
+// UpdateUserCategory replaces the name, description, and rules of one of a user's categories.
+func (h *CategorizationHandler) UpdateUserCategory(ctx context.Context, req *pb.UpdateUserCategoryRequest) (*pb.UserCategoryResponse, error) {
+	category := converter.FromProtoUserCategory(req.Category)
+	if err := validateUserCategory(category); err != nil {
+		return nil, err
+	}
+	if _, err := requestUser(ctx, category.UserID); err != nil {
+		return nil, err
+	}
+	if category.ID == "" {
+		return nil, status.Error(codes.InvalidArgument, "category id is required")
+	}
+
+	if err := h.categoryRepo.UpdateCategory(ctx, *category); err != nil {
+		return nil, categoryStatusError(err)
+	}
+
+	return &pb.UserCategoryResponse{Category: converter.ToProtoUserCategory(category)}, nil
+}
+
+// DeleteUserCategory removes one of a user's categories.
+func (h *CategorizationHandler) DeleteUserCategory(ctx context.Context, req *pb.DeleteUserCategoryRequest) (*pb.DeleteUserCategoryResponse, error) {
+	if req.UserId == "" || req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and id are required")
+	}
+	if _, err := requestUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := h.categoryRepo.DeleteCategory(ctx, req.UserId, req.Id); err != nil {
+		return nil, categoryStatusError(err)
+	}
+
+	return &pb.DeleteUserCategoryResponse{}, nil
+}
+
+// loadUserRules builds the rule engine for a user's custom categories. It returns nil when the
+// request is not made on behalf of a user or the user has no categories. Failing to load the
+// categories is logged and treated as if the user had none, so categorization still succeeds.
+func (h *CategorizationHandler) loadUserRules(ctx context.Context, userID string) *rules.Engine {
+	if userID == "" {
+		return nil
+	}
+
+	categories, err := h.categoryRepo.ListCategories(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to load categories for user %s: %v", userID, err)
+		return nil
+	}
+	if len(categories) == 0 {
+		return nil
+	}
+
+	engine, err := rules.NewEngine(rules.FromUserCategories(categories))
+	if err != nil {
+		log.Printf("Failed to compile rules for user %s: %v", userID, err)
+		return nil
+	}
+	return engine
+}
+
+// validateUserCategory checks that a category has an owner and a name, and that every rule
+// has a name, at least one signal, and valid subject patterns.
+func validateUserCategory(category *models.UserCategory) error {
+	if category == nil {
+		return status.Error(codes.InvalidArgument, "category is required")
+	}
+	if category.UserID == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if category.Name == "" {
+		return status.Error(codes.InvalidArgument, "category name is required")
+	}
+
+	for _, rule := range category.Rules {
+		if rule.Name == "" {
+			return status.Errorf(codes.InvalidArgument, "every rule of category %q needs a name", category.Name)
+		}
+		if len(rule.Senders)+len(rule.StrongKeywords)+len(rule.SubjectPatterns)+len(rule.HeaderIndicators) == 0 {
+			return status.Errorf(codes.InvalidArgument, "rule %q has no signals", rule.Name)
+		}
+	}
+
+	if _, err := rules.NewEngine(rules.FromUserCategories([]models.UserCategory{*category})); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// categoryStatusError converts a CategoryRepository error into a gRPC status error.
+func categoryStatusError(err error) error {
+	switch {
+	case errors.Is(err, ErrCategoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrCategoryExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("failed to access user categories: %v", err))
+	}
+}
@@ -1,15 +1,117 @@
 package models
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
+)
 
 // Config holds configuration data for the email categorization service.
 // This includes server settings, connection details for the ML service,
 // processing parameters, retry policies, and a database connection pool.
 type Config struct {
-	GRPCPort      string       // gRPC server port
-	MLServerAddr  string       // ML service address
-	MaxBatchSize  int          // Max emails in a single batch
-	NumWorkers    int          // Concurrent worker count
-	RetryAttempts int          // Retry count for failed operations
-	DBPool        *pgxpool.Pool
+	GRPCPort           string        // gRPC server port
+	MLServerAddrs      []string      // ML service replica addresses; dns:///host:port expands to every IP of host
+	MaxBatchSize       int           // Max emails in a single batch
+	NumWorkers         int           // Concurrent worker count
+	RetryAttempts      int           // Retry count for failed operations
+	RetryBackoff       time.Duration // Delay before the first retry of an ML request, doubled on every further retry
+	BatchPersistPolicy PersistPolicy // How partially failed batches are persisted
+	RulesFile          string        // Path to the YAML or JSON rules file; empty disables rules
+	TaxonomyFile       string        // Path to the YAML or JSON category taxonomy; empty uses the ML categories
+	RoutingFile        string        // Path to the YAML or JSON shadow and A/B model routing; empty serves every email by the primary model
+
+	ModelVersion      string        // Version of the model behind the ML service; changing it invalidates cached predictions
+	ServiceVersion    string        // Version of this service, recorded with every categorization
+	MLCacheSize       int           // Predictions kept in the in-process cache; 0 disables caching
+	MLCacheTTL        time.Duration // How long a cached prediction stays valid
+	MLCachePersistent bool          // Whether cached predictions are also stored in Postgres
+	MLBatchSize       int           // Most emails coalesced into one ML batch request
+	MLBatchWait       time.Duration // How long single-email ML requests wait to be coalesced; 0 disables coalescing
+
+	MLBackend   MLBackend // Where emails are classified: the ML server or the embedded model
+	MLModelFile string    // Path to the embedded model, used by the embedded backend and the model fallback
+
+	MLBalancingPolicy     mlclient.BalancingPolicy // How ML requests are spread across the replicas
+	MLHealthCheckInterval time.Duration            // How often the ML replicas are health checked
+
+	BreakerFailureThreshold int           // Consecutive ML failures that open the circuit breaker
+	BreakerOpenTimeout      time.Duration // How long the circuit stays open before probing the ML service again
+	BreakerHalfOpenRequests int           // Trial requests that must succeed to close the circuit again
+	MLFallback              FallbackMode  // How emails are categorized while the circuit is open
+
+	ConfidenceThreshold float32            // Minimum confidence to accept a prediction; lower ones need review
+	CategoryThresholds  map[string]float32 // Per-category thresholds overriding ConfidenceThreshold
+
+	LabelSelection       LabelSelection // How the labels of an email are picked from its ranked categories
+	MaxLabels            int            // Maximum number of labels per email
+	CumulativeLabelScore float32        // Combined score at which LabelsCumulative stops adding labels
+	MinLabelScore        float32        // Minimum score of every label besides the primary one
+
+	DBPool *pgxpool.Pool
+}
+
+// LabelSelection controls how many of the ranked categories of a prediction become labels
+// of the email. The primary category is always a label.
+type LabelSelection string
+
+const (
+	// LabelsTopK labels an email with its MaxLabels most confident categories.
+	LabelsTopK LabelSelection = "top_k"
+
+	// LabelsCumulative labels an email with its most confident categories until their combined
+	// score reaches CumulativeLabelScore, up to MaxLabels categories.
+	LabelsCumulative LabelSelection = "cumulative"
+)
+
+// FallbackMode controls how emails are categorized while the ML service is unavailable,
+// i.e. while the circuit breaker in front of it is open.
+type FallbackMode string
+
+const (
+	// FallbackNone fails the categorization of emails, as if there was no fallback.
+	FallbackNone FallbackMode = "none"
+
+	// FallbackRules categorizes emails with the matching override and boost rules alone. Emails
+	// no rule matches need review. Results are marked as degraded.
+	FallbackRules FallbackMode = "rules"
+
+	// FallbackModel categorizes emails with the embedded model of MLModelFile, applying the rules
+	// as usual. Results are marked as degraded.
+	FallbackModel FallbackMode = "model"
+)
+
+// MLBackend selects what classifies emails.
+type MLBackend string
+
+const (
+	// BackendGRPC sends emails to the replicas of the Python ML server.
+	BackendGRPC MLBackend = "grpc"
+
+	// BackendEmbedded classifies emails in-process with the Naive Bayes model of MLModelFile,
+	// so the service runs without the Python ML server.
+	BackendEmbedded MLBackend = "embedded"
+)
+
+// ThresholdFor returns the minimum confidence a prediction of the given category needs
+// to be accepted without review.
+func (c *Config) ThresholdFor(category string) float32 {
+	if threshold, ok := c.CategoryThresholds[category]; ok {
+		return threshold
+	}
+	return c.ConfidenceThreshold
 }
+
+// PersistPolicy controls how a batch of categorized emails is persisted
+// when only some of the emails in it were categorized successfully.
+type PersistPolicy string
+
+const (
+	// PersistAllOrNothing stores a batch only if every email in it was categorized successfully.
+	PersistAllOrNothing PersistPolicy = "all_or_nothing"
+
+	// PersistSucceeded stores the emails that were categorized successfully and skips the rest.
+	PersistSucceeded PersistPolicy = "save_succeeded"
+)
@@ -5,6 +5,9 @@ import "time"
 // EmailDB represents the database schema for storing emails.
 type EmailDB struct {
 	ID         string    `db:"id"`         // Unique identifier for the email (UUID).
+	ClientID   string    `db:"client_id"`  // The client-supplied ID the storage ID was derived from.
+	Mailbox    string    `db:"mailbox"`    // The mailbox the client-supplied ID belongs to.
+	UserID     string    `db:"user_id"`    // The user the email belongs to, or empty for anonymous requests.
 	Sender     string    `db:"sender"`     // The email sender address.
 	Subject    string    `db:"subject"`    // The subject line of the email.
 	Body       string    `db:"content"`    // The body/content of the email.
@@ -15,9 +18,86 @@ type EmailDB struct {
 
 // CatgegoryRecord represents a record of categorization results for a given email.
 type CatgegoryRecord struct {
-	ID              string    `db:"id"`              // Unique identifier for this record (UUID).
-	EmailID         string    `db:"email_id"`        // The associated email's unique identifier.
-	Categories      string    `db:"categories"`      // JSON-encoded categorization results.
+	ID              string    `db:"id"`               // Unique identifier for this record (UUID).
+	EmailID         string    `db:"email_id"`         // The associated email's unique identifier.
+	Categories      string    `db:"categories"`       // JSON-encoded CategoryDetails.
 	ConfidenceScore float32   `db:"confidence_score"` // The model’s confidence score for the categorization.
-	CreatedAt       time.Time `db:"created_at"`      // Timestamp indicating when the record was created.
+	CreatedAt       time.Time `db:"created_at"`       // Timestamp indicating when the record was created.
+	NeedsReview     bool      `db:"needs_review"`     // Whether the prediction was too unconfident to file the email.
+
+	ModelName      string `db:"model_name"`      // The model that made the prediction, if any.
+	ModelVersion   string `db:"model_version"`   // The version of that model.
+	RuleSetHash    string `db:"rule_set_hash"`   // The hash of the rule set applied around the prediction.
+	ServiceVersion string `db:"service_version"` // The version of the service that categorized the email.
+	DecisionPath   string `db:"decision_path"`   // How the category was decided: rule, ml, cache, or fallback.
+
+	CorrectedCategory *string    `db:"corrected_category"` // The category the user moved the email to, if any.
+	FeedbackUserID    *string    `db:"feedback_user_id"`   // The user who submitted the correction.
+	CorrectedAt       *time.Time `db:"corrected_at"`       // Timestamp indicating when the correction was submitted.
+}
+
+// CategoryDetails is the JSON document stored in the categories column of a CatgegoryRecord.
+// Besides the assigned categories and their per-label scores, it keeps the ranked alternatives
+// and explanatory keywords returned with the prediction, the rule that decided the category,
+// for emails needing review, the category that was predicted, and whether the result came from
+// a fallback because the ML service was unavailable.
+type CategoryDetails struct {
+	Categories        []string            `json:"categories"`
+	Labels            []LabelDetail       `json:"labels,omitempty"`
+	Alternatives      []AlternativeDetail `json:"alternatives,omitempty"`
+	Keywords          []string            `json:"keywords,omitempty"`
+	MatchedRule       string              `json:"matched_rule,omitempty"`
+	NeedsReview       bool                `json:"needs_review,omitempty"`
+	PredictedCategory string              `json:"predicted_category,omitempty"`
+	Degraded          bool                `json:"degraded,omitempty"`
+}
+
+// LabelDetail is a category assigned to the email and its confidence score within CategoryDetails.
+type LabelDetail struct {
+	Category        string  `json:"category"`
+	ConfidenceScore float32 `json:"confidence_score"`
+}
+
+// AlternativeDetail is a runner-up category and its confidence score within CategoryDetails.
+type AlternativeDetail struct {
+	Category        string  `json:"category"`
+	ConfidenceScore float32 `json:"confidence_score"`
+}
+
+// UserCategoryDB represents the database schema for storing a user's custom category.
+type UserCategoryDB struct {
+	ID          string    `db:"id"`          // Unique identifier for the category (UUID).
+	UserID      string    `db:"user_id"`     // The user who owns the category.
+	Name        string    `db:"name"`        // The category name shown to the user.
+	Description string    `db:"description"` // Optional description of the category.
+	Rules       string    `db:"rules"`       // JSON-encoded list of CategoryRuleDetail.
+	CreatedAt   time.Time `db:"created_at"`  // Timestamp indicating when the category was created.
+	UpdatedAt   time.Time `db:"updated_at"`  // Timestamp indicating when the category was last updated.
+}
+
+// CategoryRuleDetail is a single rule within the rules column of a UserCategoryDB.
+type CategoryRuleDetail struct {
+	Name             string   `json:"name"`
+	Senders          []string `json:"senders,omitempty"`
+	StrongKeywords   []string `json:"strong_keywords,omitempty"`
+	SubjectPatterns  []string `json:"subject_patterns,omitempty"`
+	HeaderIndicators []string `json:"header_indicators,omitempty"`
+	MinScore         int      `json:"min_score,omitempty"`
+}
+
+// ModelPredictionDB represents the database schema for storing the prediction of a single model,
+// served or shadow, for an email.
+type ModelPredictionDB struct {
+	ID               int64     `db:"id"`                // Sequential identifier of the prediction.
+	EmailID          string    `db:"email_id"`          // The storage ID of the categorized email.
+	Model            string    `db:"model"`             // The model that made the prediction.
+	Shadow           bool      `db:"shadow"`            // Whether the prediction was only stored, not returned.
+	Category         string    `db:"category"`          // The category predicted by the model.
+	Confidence       float32   `db:"confidence"`        // The model's confidence in the category.
+	ServedModel      string    `db:"served_model"`      // The model whose prediction was returned.
+	ServedCategory   string    `db:"served_category"`   // The category predicted by the served model.
+	ServedConfidence float32   `db:"served_confidence"` // The served model's confidence in its category.
+	CreatedAt        time.Time `db:"created_at"`        // Timestamp indicating when the prediction was made.
+
+	CorrectedCategory *string `db:"corrected_category"` // The category the user corrected the email to, if any.
 }
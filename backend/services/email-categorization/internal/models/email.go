@@ -1,8 +1,18 @@
 package models
 
+import "time"
+
 // Email represents the essential properties of an email.
+// Mailbox scopes the ID, since client-supplied IDs are only unique within a mailbox.
+// ClientID is only set on stored emails, whose ID is the storage ID derived from the
+// mailbox and the client-supplied ID; it keeps the client-supplied ID.
+// UserID is the user the email was categorized for, scoping it along with the mailbox,
+// or empty for anonymous requests.
 type Email struct {
 	ID         string
+	ClientID   string
+	Mailbox    string
+	UserID     string
 	Subject    string
 	Body       string
 	Sender     string
@@ -11,9 +21,104 @@ type Email struct {
 }
 
 // CategoryResult contains categorization information for a single email.
+// Labels are the categories assigned to the email with their scores, ranked from most to least
+// confident. Categories lists the same categories, and ConfidenceScore is the score of the primary one.
+// Alternatives are the runner-up categories, Keywords the terms explaining the prediction, and
+// MatchedRule the rule that decided the category, if any.
+// NeedsReview is set when the prediction was not confident enough: the email is then categorized as
+// NeedsReviewCategory, and PredictedCategory keeps the category that was predicted.
+// Provenance records the model and rules behind the result.
+// Error is set instead of the categories when the email could not be categorized.
 type CategoryResult struct {
-	EmailID         string
-	Categories      []string
+	EmailID           string
+	Categories        []string
+	ConfidenceScore   float32
+	Labels            []Label
+	Alternatives      []Alternative
+	Keywords          []string
+	MatchedRule       string
+	NeedsReview       bool
+	PredictedCategory string
+	Degraded          bool // categorized by a fallback because the ML service was unavailable
+	Provenance        Provenance
+	Error             string
+}
+
+// Provenance records what produced a categorization result: the model that made the prediction,
+// by the name and version reported by the ML server or else configured, the hash of the rule set
+// applied around it, the version of the service, and the path that decided the category. The model
+// is empty when no model was involved, e.g. when a rule decided the category on its own.
+type Provenance struct {
+	ModelName      string
+	ModelVersion   string
+	RuleSetHash    string
+	ServiceVersion string
+	DecisionPath   DecisionPath
+}
+
+// DecisionPath is the way the category of an email was decided.
+type DecisionPath string
+
+const (
+	// DecisionRule is a short_circuit rule deciding the category without calling the ML service.
+	DecisionRule DecisionPath = "rule"
+
+	// DecisionML is a prediction of the ML service, adjusted by the rules.
+	DecisionML DecisionPath = "ml"
+
+	// DecisionCache is a cached prediction of the ML service for an email of the same content,
+	// adjusted by the rules.
+	DecisionCache DecisionPath = "cache"
+
+	// DecisionFallback is the rules or the embedded model categorizing the email while the ML
+	// service was unavailable.
+	DecisionFallback DecisionPath = "fallback"
+
+	// DecisionFeedback is the category the user corrected a previous categorization to.
+	DecisionFeedback DecisionPath = "feedback"
+)
+
+// NeedsReviewCategory is the category of emails whose prediction was not confident enough
+// to file them automatically, so the user has to review them.
+const NeedsReviewCategory = "UNCATEGORIZED"
+
+// StoredCategorization pairs a previously stored email with its latest categorization result,
+// and the category the user corrected that result to, if any.
+type StoredCategorization struct {
+	Email             Email
+	Result            CategoryResult
+	CorrectedCategory string
+}
+
+// Feedback is a user's correction of the category assigned to a stored email.
+// EmailID is the email's storage ID.
+type Feedback struct {
+	EmailID           string
+	UserID            string
+	CorrectedCategory string
+}
+
+// LabeledEmail is a stored email together with its latest categorization and, if the user
+// corrected it, the corrected category. It is the raw material for exporting training data.
+type LabeledEmail struct {
+	Email             Email
+	Result            CategoryResult
+	CorrectedCategory string
+	CreatedAt         time.Time
+}
+
+// LabeledEmailFilter selects which stored emails are exported as labeled data. Emails are included
+// when they were stored within [From, To) and either carry a user correction or were predicted with
+// at least MinConfidence. A nil From or To leaves that end of the range open.
+type LabeledEmailFilter struct {
+	From          *time.Time
+	To            *time.Time
+	MinConfidence float32
+}
+
+// Label is a category assigned to an email together with its own confidence score.
+type Label struct {
+	Category        string
 	ConfidenceScore float32
 }
 
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // MLRequest represents the data sent to the machine learning service for categorization.
 type MLRequest struct {
 	ID        string
@@ -12,11 +14,31 @@ type MLRequest struct {
 }
 
 // MLResponse represents the response returned by the machine learning service,
-// including the primary category, confidence score, and any alternative categorizations.
+// including the primary category, confidence score, any alternative categorizations,
+// and the keywords that influenced the prediction.
 type MLResponse struct {
 	ID              string
 	Category        string
 	ConfidenceScore float32
 	Alternatives    []Alternative
+	Keywords        []string
 	Error           string
 }
+
+// ModelPrediction is the prediction a model made for an email, as returned by the model before any
+// rule or threshold was applied, stored to compare models routed to side by side. Shadow predictions
+// were only stored, while the served model's prediction was the one returned; every prediction keeps
+// the served model's category and confidence for comparison. CorrectedCategory is the category the
+// user corrected the email to, if any, and is only filled in when predictions are read back.
+type ModelPrediction struct {
+	EmailID           string
+	Model             string
+	Shadow            bool
+	Category          string
+	Confidence        float32
+	ServedModel       string
+	ServedCategory    string
+	ServedConfidence  float32
+	CorrectedCategory string
+	CreatedAt         time.Time
+}
@@ -0,0 +1,24 @@
+package models
+
+// UserCategory is a custom category defined by a user, such as "Client A" or "Invoices",
+// together with the rules that assign emails to it.
+type UserCategory struct {
+	ID          string
+	UserID      string
+	Name        string
+	Description string
+	Rules       []CategoryRule
+}
+
+// CategoryRule describes the signals that assign an email to a UserCategory. The signals
+// follow the same structure as the service-wide rules: sender substrings, whole-word keywords,
+// subject regular expressions, and header value substrings. The rule matches once the weighted
+// score of its signals reaches MinScore.
+type CategoryRule struct {
+	Name             string
+	Senders          []string
+	StrongKeywords   []string
+	SubjectPatterns  []string
+	HeaderIndicators []string
+	MinScore         int
+}
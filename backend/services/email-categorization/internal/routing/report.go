@@ -0,0 +1,215 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// Report compares the models emails were routed to. Served and shadow predictions alike make up
+// the confidence distribution of a model, while agreement pairs every shadow prediction with the
+// prediction served for the same email. A/B variants never see the same emails, so they are compared
+// through their confidence distributions and their agreement with user corrections instead.
+type Report struct {
+	Predictions int           `json:"predictions"`
+	Emails      int           `json:"emails"`
+	Models      []ModelReport `json:"models"`
+	Agreement   []Agreement   `json:"agreement"`
+}
+
+// ModelReport summarizes the predictions of a single model. Corrected is the number of its
+// predictions for emails the user corrected, and CorrectionAgreement the share of them that
+// predicted the corrected category.
+type ModelReport struct {
+	Model               string             `json:"model"`
+	Served              int                `json:"served"`
+	Shadow              int                `json:"shadow"`
+	MeanConfidence      float64            `json:"mean_confidence"`
+	Confidence          []ConfidenceBucket `json:"confidence"`
+	Categories          []CategoryCount    `json:"categories"`
+	Corrected           int                `json:"corrected"`
+	CorrectionAgreement float64            `json:"correction_agreement"`
+}
+
+// ConfidenceBucket counts the predictions whose confidence falls within [Lower, Upper)
+// (the last bucket includes 1.0). Share is the fraction of the model's predictions in the bucket.
+type ConfidenceBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// CategoryCount is the number of predictions of a category by a model.
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// Agreement compares a shadow model with the model served in its place on the same emails.
+// Rate is the share of the emails both models put in the same category, and ConfidenceGap the
+// mean confidence of the shadow model minus the mean confidence of the served one.
+type Agreement struct {
+	Model         string  `json:"model"`
+	ServedModel   string  `json:"served_model"`
+	Compared      int     `json:"compared"`
+	Agreed        int     `json:"agreed"`
+	Rate          float64 `json:"rate"`
+	ConfidenceGap float64 `json:"confidence_gap"`
+}
+
+// modelTally accumulates the predictions of a model while building a report.
+type modelTally struct {
+	report     ModelReport
+	confidence float64
+	agreed     int
+	categories map[string]int
+}
+
+// BuildReport builds a report from stored predictions, using the given number of equal-width
+// confidence buckets. Values below 1 mean 10. Models and agreement pairs are sorted by name,
+// so the report of the same predictions is always the same.
+func BuildReport(predictions []models.ModelPrediction, buckets int) *Report {
+	if buckets < 1 {
+		buckets = 10
+	}
+
+	report := &Report{Predictions: len(predictions)}
+	emails := make(map[string]bool)
+	tallies := make(map[string]*modelTally)
+	agreements := make(map[[2]string]*Agreement)
+
+	for _, prediction := range predictions {
+		emails[prediction.EmailID] = true
+
+		tally, exists := tallies[prediction.Model]
+		if !exists {
+			tally = &modelTally{
+				report:     ModelReport{Model: prediction.Model, Confidence: newBuckets(buckets)},
+				categories: make(map[string]int),
+			}
+			tallies[prediction.Model] = tally
+		}
+		if prediction.Shadow {
+			tally.report.Shadow++
+		} else {
+			tally.report.Served++
+		}
+		tally.confidence += float64(prediction.Confidence)
+		tally.report.Confidence[bucketOf(prediction.Confidence, buckets)].Count++
+		tally.categories[prediction.Category]++
+		if prediction.CorrectedCategory != "" {
+			tally.report.Corrected++
+			if prediction.Category == prediction.CorrectedCategory {
+				tally.agreed++
+			}
+		}
+
+		if !prediction.Shadow {
+			continue
+		}
+		pair := [2]string{prediction.Model, prediction.ServedModel}
+		agreement, exists := agreements[pair]
+		if !exists {
+			agreement = &Agreement{Model: prediction.Model, ServedModel: prediction.ServedModel}
+			agreements[pair] = agreement
+		}
+		agreement.Compared++
+		if prediction.Category == prediction.ServedCategory {
+			agreement.Agreed++
+		}
+		agreement.ConfidenceGap += float64(prediction.Confidence - prediction.ServedConfidence)
+	}
+	report.Emails = len(emails)
+
+	for _, tally := range tallies {
+		total := tally.report.Served + tally.report.Shadow
+		tally.report.MeanConfidence = tally.confidence / float64(total)
+		for i := range tally.report.Confidence {
+			tally.report.Confidence[i].Share = float64(tally.report.Confidence[i].Count) / float64(total)
+		}
+		for category, count := range tally.categories {
+			tally.report.Categories = append(tally.report.Categories, CategoryCount{Category: category, Count: count})
+		}
+		sort.Slice(tally.report.Categories, func(i, j int) bool {
+			return tally.report.Categories[i].Category < tally.report.Categories[j].Category
+		})
+		if tally.report.Corrected > 0 {
+			tally.report.CorrectionAgreement = float64(tally.agreed) / float64(tally.report.Corrected)
+		}
+		report.Models = append(report.Models, tally.report)
+	}
+	sort.Slice(report.Models, func(i, j int) bool {
+		return report.Models[i].Model < report.Models[j].Model
+	})
+
+	for _, agreement := range agreements {
+		agreement.Rate = float64(agreement.Agreed) / float64(agreement.Compared)
+		agreement.ConfidenceGap /= float64(agreement.Compared)
+		report.Agreement = append(report.Agreement, *agreement)
+	}
+	sort.Slice(report.Agreement, func(i, j int) bool {
+		if report.Agreement[i].Model != report.Agreement[j].Model {
+			return report.Agreement[i].Model < report.Agreement[j].Model
+		}
+		return report.Agreement[i].ServedModel < report.Agreement[j].ServedModel
+	})
+
+	return report
+}
+
+// newBuckets returns n empty equal-width confidence buckets covering [0, 1].
+func newBuckets(n int) []ConfidenceBucket {
+	buckets := make([]ConfidenceBucket, n)
+	for i := range buckets {
+		buckets[i].Lower = float64(i) / float64(n)
+		buckets[i].Upper = float64(i+1) / float64(n)
+	}
+	return buckets
+}
+
+// bucketOf returns the index of the bucket a confidence score falls into.
+func bucketOf(confidence float32, n int) int {
+	return max(min(int(float64(confidence)*float64(n)), n-1), 0)
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteText writes the report as human-readable tables: a summary, the per-model metrics,
+// the agreement of the shadow models, and the confidence distribution of every model.
+func WriteText(w io.Writer, report *Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintf(tw, "Predictions\t%d\t\n", report.Predictions)
+	fmt.Fprintf(tw, "Emails\t%d\t\n", report.Emails)
+
+	fmt.Fprintln(tw, "\nModel\tServed\tShadow\tMean confidence\tCorrected\tCorrection agreement\t")
+	for _, m := range report.Models {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.4f\t%d\t%.4f\t\n", m.Model, m.Served, m.Shadow, m.MeanConfidence, m.Corrected, m.CorrectionAgreement)
+	}
+
+	if len(report.Agreement) > 0 {
+		fmt.Fprintln(tw, "\nShadow model\tServed model\tCompared\tAgreed\tRate\tConfidence gap\t")
+		for _, a := range report.Agreement {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.4f\t%+.4f\t\n", a.Model, a.ServedModel, a.Compared, a.Agreed, a.Rate, a.ConfidenceGap)
+		}
+	}
+
+	for _, m := range report.Models {
+		fmt.Fprintf(tw, "\n%s confidence\tCount\tShare\t\n", m.Model)
+		for _, bucket := range m.Confidence {
+			fmt.Fprintf(tw, "%.2f-%.2f\t%d\t%.4f\t\n", bucket.Lower, bucket.Upper, bucket.Count, bucket.Share)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,259 @@
+package routing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
+)
+
+// SplitKey selects what assigns an email to an A/B variant. Hashing a stable key means the same
+// user, or the same email, is always served by the same model while the split is unchanged.
+type SplitKey string
+
+const (
+	// SplitByUser assigns every email of a user to the same model. Emails categorized without a
+	// user ID are assigned by their email ID instead.
+	SplitByUser SplitKey = "user"
+
+	// SplitByEmail assigns every email to a model independently of its user.
+	SplitByEmail SplitKey = "email"
+)
+
+// Config describes the models besides the primary one, and how emails are routed to them.
+// Shadow models categorize every email the primary model or a variant served, but their
+// predictions are only logged and stored, never returned. Split variants serve the given
+// percentage of the emails instead of the primary model, which serves the remainder.
+type Config struct {
+	Models []ModelSpec `json:"models" yaml:"models"`
+	Shadow []string    `json:"shadow" yaml:"shadow"`
+	Split  Split       `json:"split" yaml:"split"`
+}
+
+// ModelSpec describes a model that can be shadowed or split to. A model is served either by
+// replicas of the ML server at Addresses, or in-process by the embedded model of ModelFile.
+type ModelSpec struct {
+	Name      string   `json:"name" yaml:"name"`
+	Addresses []string `json:"addresses" yaml:"addresses"`
+	ModelFile string   `json:"model_file" yaml:"model_file"`
+}
+
+// Split is a percentage-based A/B split between the primary model and the variants.
+type Split struct {
+	Key      SplitKey  `json:"key" yaml:"key"`
+	Variants []Variant `json:"variants" yaml:"variants"`
+}
+
+// Variant is a model serving Percent percent of the emails.
+type Variant struct {
+	Model   string  `json:"model" yaml:"model"`
+	Percent float64 `json:"percent" yaml:"percent"`
+}
+
+// buckets is the number of buckets the keys of the split are hashed into, so percentages
+// can have two decimal places.
+const buckets = 10000
+
+// LoadFile reads a routing configuration from a YAML or JSON file, chosen by the file extension,
+// and validates it. An empty path returns an empty configuration, which routes every email to
+// the primary model.
+func LoadFile(path string) (*Config, error) {
+	if path == "" {
+		return &Config{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing file: %w", err)
+	}
+
+	var config Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &config)
+	default:
+		return nil, fmt.Errorf("unsupported routing file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse routing file: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate checks that every model is served from exactly one source under a unique name, that
+// the shadow models and variants refer to those models, and that the split adds up to at most 100%.
+func (c *Config) Validate() error {
+	names := make(map[string]bool)
+	for _, model := range c.Models {
+		if model.Name == "" {
+			return errors.New("routing: model without a name")
+		}
+		if names[model.Name] {
+			return fmt.Errorf("routing: duplicate model %q", model.Name)
+		}
+		names[model.Name] = true
+		if (len(model.Addresses) == 0) == (model.ModelFile == "") {
+			return fmt.Errorf("routing: model %q needs either addresses or a model_file", model.Name)
+		}
+	}
+
+	shadowed := make(map[string]bool)
+	for _, name := range c.Shadow {
+		if !names[name] {
+			return fmt.Errorf("routing: shadow model %q is not defined", name)
+		}
+		if shadowed[name] {
+			return fmt.Errorf("routing: model %q is shadowed twice", name)
+		}
+		shadowed[name] = true
+	}
+
+	switch c.Split.Key {
+	case "":
+		c.Split.Key = SplitByUser
+	case SplitByUser, SplitByEmail:
+	default:
+		return fmt.Errorf("routing: unknown split key %q, expected %s or %s", c.Split.Key, SplitByUser, SplitByEmail)
+	}
+
+	var total float64
+	split := make(map[string]bool)
+	for _, variant := range c.Split.Variants {
+		if !names[variant.Model] {
+			return fmt.Errorf("routing: variant model %q is not defined", variant.Model)
+		}
+		if split[variant.Model] {
+			return fmt.Errorf("routing: model %q is split to twice", variant.Model)
+		}
+		split[variant.Model] = true
+		if variant.Percent <= 0 || variant.Percent > 100 {
+			return fmt.Errorf("routing: percent of variant %q must be within (0, 100]", variant.Model)
+		}
+		total += variant.Percent
+	}
+	if total > 100 {
+		return fmt.Errorf("routing: variants add up to %g%%, more than 100%%", total)
+	}
+	return nil
+}
+
+// Model is a named model and the service classifying emails with it.
+type Model struct {
+	Name    string
+	Service mlclient.Service
+}
+
+// variantRoute is a variant and the upper bound of the hash buckets it serves.
+type variantRoute struct {
+	model Model
+	upTo  uint64
+}
+
+// Router picks the model serving an email, and the shadow models it is also sent to.
+// It is safe for concurrent use, since it is never modified after construction.
+type Router struct {
+	primary  Model
+	models   []Model
+	shadows  []Model
+	key      SplitKey
+	variants []variantRoute
+}
+
+// NewRouter creates a Router from a validated configuration. The primary model serves every email
+// no variant does, and services holds the service of every model of the configuration by name.
+func NewRouter(primary Model, config *Config, services map[string]mlclient.Service) (*Router, error) {
+	router := &Router{primary: primary, key: config.Split.Key}
+
+	byName := make(map[string]Model, len(config.Models))
+	for _, spec := range config.Models {
+		service, ok := services[spec.Name]
+		if !ok {
+			return nil, fmt.Errorf("routing: no service for model %q", spec.Name)
+		}
+		if spec.Name == primary.Name {
+			return nil, fmt.Errorf("routing: model %q has the name of the primary model", spec.Name)
+		}
+		model := Model{Name: spec.Name, Service: service}
+		byName[spec.Name] = model
+		router.models = append(router.models, model)
+	}
+
+	for _, name := range config.Shadow {
+		router.shadows = append(router.shadows, byName[name])
+	}
+
+	var upTo uint64
+	for _, variant := range config.Split.Variants {
+		upTo += uint64(variant.Percent * buckets / 100)
+		router.variants = append(router.variants, variantRoute{model: byName[variant.Model], upTo: upTo})
+	}
+	return router, nil
+}
+
+// Primary returns the primary model.
+func (r *Router) Primary() Model {
+	return r.primary
+}
+
+// Route returns the model serving an email of a user. The user ID or the email ID, depending on
+// the split key, is hashed into a bucket, and the variant owning that bucket serves the email.
+// Buckets no variant owns are served by the primary model.
+func (r *Router) Route(userID, emailID string) Model {
+	if len(r.variants) == 0 {
+		return r.primary
+	}
+
+	key := emailID
+	if r.key == SplitByUser && userID != "" {
+		key = "user\x00" + userID
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	bucket := hash.Sum64() % buckets
+
+	for _, variant := range r.variants {
+		if bucket < variant.upTo {
+			return variant.model
+		}
+	}
+	return r.primary
+}
+
+// Shadows returns the shadow models of an email served by the given model. A shadow model that
+// served the email itself is left out.
+func (r *Router) Shadows(served string) []Model {
+	shadows := make([]Model, 0, len(r.shadows))
+	for _, shadow := range r.shadows {
+		if shadow.Name != served {
+			shadows = append(shadows, shadow)
+		}
+	}
+	return shadows
+}
+
+// Enabled reports whether any email is sent to a model other than the primary one.
+func (r *Router) Enabled() bool {
+	return len(r.shadows) > 0 || len(r.variants) > 0
+}
+
+// Close closes the services of every model besides the primary one, which is owned by the caller.
+func (r *Router) Close() error {
+	var errs []error
+	for _, model := range r.models {
+		errs = append(errs, model.Service.Close())
+	}
+	return errors.Join(errs...)
+}
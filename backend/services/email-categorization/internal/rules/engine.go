@@ -0,0 +1,305 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// Engine evaluates a set of rules against emails. It is safe for concurrent use,
+// since its rules are never modified after construction.
+type Engine struct {
+	rules []*compiledRule
+	hash  string
+}
+
+// Match is a rule that matched an email, along with the score of its matching signals.
+type Match struct {
+	Rule  Rule
+	Score int
+}
+
+// NewEngine validates and compiles the rules of a rule set into an Engine.
+func NewEngine(ruleSet RuleSet) (*Engine, error) {
+	engine := &Engine{}
+	for _, rule := range ruleSet.Rules {
+		compiled, err := compile(rule)
+		if err != nil {
+			return nil, err
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+
+	// Hash the rules with their defaults applied, so equivalent rule sets hash the same
+	if len(engine.rules) > 0 {
+		data, err := json.Marshal(engine.Rules())
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		engine.hash = hex.EncodeToString(sum[:])
+	}
+	return engine, nil
+}
+
+// Hash returns the SHA-256 hash of the engine's rules, identifying the rule set results were
+// categorized with. It is empty for an engine without rules.
+func (e *Engine) Hash() string {
+	return e.hash
+}
+
+// Len returns the number of rules in the engine.
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Rules returns the rules of the engine, in the order they were defined.
+func (e *Engine) Rules() []Rule {
+	rules := make([]Rule, len(e.rules))
+	for i, rule := range e.rules {
+		rules[i] = rule.Rule
+	}
+	return rules
+}
+
+// Evaluate scores every rule against the email and returns the matching rules,
+// ordered from the highest to the lowest score. Rules with equal scores keep the
+// order in which they were defined.
+func (e *Engine) Evaluate(email *models.Email) []Match {
+	if len(e.rules) == 0 {
+		return nil
+	}
+
+	sender := strings.ToLower(email.Sender)
+	text := email.Subject + " " + email.Body
+
+	headerValues := make([]string, 0, len(email.Headers))
+	for _, value := range email.Headers {
+		headerValues = append(headerValues, strings.ToLower(value))
+	}
+
+	var matches []Match
+	for _, rule := range e.rules {
+		score := 0
+
+		for _, domain := range rule.senders {
+			if strings.Contains(sender, domain) {
+				score += senderWeight
+			}
+		}
+
+		for _, keyword := range rule.keywords {
+			if keyword.MatchString(text) {
+				score += keywordWeight
+			}
+		}
+
+		for _, pattern := range rule.subjectPatterns {
+			if pattern.MatchString(email.Subject) {
+				score += subjectWeight
+			}
+		}
+
+		for _, indicator := range rule.headerIndicators {
+			if slices.ContainsFunc(headerValues, func(value string) bool {
+				return strings.Contains(value, indicator)
+			}) {
+				score += headerWeight
+			}
+		}
+
+		if score >= rule.MinScore {
+			matches = append(matches, Match{Rule: rule.Rule, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// ShortCircuit returns the result of the highest scoring short_circuit rule among the matches,
+// so the email can be categorized without calling the ML service. It returns false if no
+// short_circuit rule matched.
+func ShortCircuit(email *models.Email, matches []Match) (*models.CategoryResult, bool) {
+	for _, match := range matches {
+		if match.Rule.Action != ActionShortCircuit {
+			continue
+		}
+		return &models.CategoryResult{
+			EmailID:         email.ID,
+			Categories:      []string{match.Rule.Category},
+			ConfidenceScore: match.Rule.Confidence,
+			MatchedRule:     match.Rule.Name,
+		}, true
+	}
+	return nil, false
+}
+
+// Fallback categorizes an email from the matching rules alone, for when the ML service is unavailable.
+// The highest scoring override rule decides the category with its confidence. Otherwise, every boosted
+// category is scored by the sum of its boosts, the most confident one becomes the primary category and
+// the others its alternatives. It returns false if no override or boost rule matched.
+func Fallback(email *models.Email, matches []Match) (*models.CategoryResult, bool) {
+	for _, match := range matches {
+		if match.Rule.Action == ActionOverride {
+			return &models.CategoryResult{
+				EmailID:         email.ID,
+				Categories:      []string{match.Rule.Category},
+				ConfidenceScore: match.Rule.Confidence,
+				MatchedRule:     match.Rule.Name,
+			}, true
+		}
+	}
+
+	var boosted []models.Alternative
+	positions := make(map[string]int)
+	boostedBy := make(map[string]string)
+	for _, match := range matches {
+		if match.Rule.Action != ActionBoost {
+			continue
+		}
+		category := match.Rule.Category
+		if _, exists := positions[category]; !exists {
+			positions[category] = len(boosted)
+			boosted = append(boosted, models.Alternative{Category: category})
+			boostedBy[category] = match.Rule.Name
+		}
+		score := &boosted[positions[category]].ConfidenceScore
+		*score = min(*score+match.Rule.Boost, 1)
+	}
+	if len(boosted) == 0 {
+		return nil, false
+	}
+
+	// Rank the boosted categories; ties keep the order of the highest scoring matches.
+	sort.SliceStable(boosted, func(i, j int) bool {
+		return boosted[i].ConfidenceScore > boosted[j].ConfidenceScore
+	})
+	return &models.CategoryResult{
+		EmailID:         email.ID,
+		Categories:      []string{boosted[0].Category},
+		ConfidenceScore: boosted[0].ConfidenceScore,
+		Alternatives:    boosted[1:],
+		MatchedRule:     boostedBy[boosted[0].Category],
+	}, true
+}
+
+// Apply adjusts an ML categorization result using the matching override and boost rules.
+//   - The highest scoring override rule replaces the primary category, and the ML prediction
+//     becomes the top alternative.
+//   - Otherwise, boost rules add their boost to the confidence of their category, whether it is
+//     the primary prediction or one of the alternatives. If a boosted alternative ends up more
+//     confident than the primary prediction, the two swap places.
+//
+// The name of the rule that decided the final category is recorded in the result.
+func Apply(result *models.CategoryResult, matches []Match) {
+	for _, match := range matches {
+		if match.Rule.Action == ActionOverride {
+			override(result, match.Rule)
+			return
+		}
+	}
+
+	// Collect every candidate category in ranked order, starting with the primary prediction.
+	type candidate struct {
+		category string
+		score    float32
+	}
+	var candidates []candidate
+	positions := make(map[string]int)
+	add := func(category string, score float32) {
+		if _, exists := positions[category]; !exists {
+			positions[category] = len(candidates)
+			candidates = append(candidates, candidate{category: category, score: score})
+		}
+	}
+	if len(result.Categories) > 0 {
+		add(result.Categories[0], result.ConfidenceScore)
+	}
+	for _, alt := range result.Alternatives {
+		add(alt.Category, alt.ConfidenceScore)
+	}
+
+	boostedBy := make(map[string]string)
+	for _, match := range matches {
+		if match.Rule.Action != ActionBoost {
+			continue
+		}
+		category := match.Rule.Category
+		add(category, 0)
+		boosted := &candidates[positions[category]]
+		boosted.score = min(boosted.score+match.Rule.Boost, 1)
+		if _, exists := boostedBy[category]; !exists {
+			boostedBy[category] = match.Rule.Name
+		}
+	}
+	if len(boostedBy) == 0 {
+		return
+	}
+
+	// Pick the most confident category. Ties keep the earlier candidate, so the ML prediction wins them.
+	winner := 0
+	for i := range candidates {
+		if candidates[i].score > candidates[winner].score {
+			winner = i
+		}
+	}
+
+	// Rebuild the ranked alternatives from every candidate other than the winner.
+	alternatives := make([]models.Alternative, 0, len(candidates)-1)
+	for i, c := range candidates {
+		if i != winner {
+			alternatives = append(alternatives, models.Alternative{Category: c.category, ConfidenceScore: c.score})
+		}
+	}
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		return alternatives[i].ConfidenceScore > alternatives[j].ConfidenceScore
+	})
+
+	result.Categories = ReplacePrimary(result.Categories, candidates[winner].category)
+	result.ConfidenceScore = candidates[winner].score
+	result.Alternatives = alternatives
+	if rule, boosted := boostedBy[candidates[winner].category]; boosted {
+		result.MatchedRule = rule
+	}
+}
+
+// override replaces the primary category of a result with the category of an override rule.
+func override(result *models.CategoryResult, rule Rule) {
+	alternatives := make([]models.Alternative, 0, len(result.Alternatives)+1)
+	if len(result.Categories) > 0 && result.Categories[0] != rule.Category {
+		alternatives = append(alternatives, models.Alternative{
+			Category:        result.Categories[0],
+			ConfidenceScore: result.ConfidenceScore,
+		})
+	}
+	for _, alt := range result.Alternatives {
+		if alt.Category != rule.Category {
+			alternatives = append(alternatives, alt)
+		}
+	}
+
+	result.Categories = ReplacePrimary(result.Categories, rule.Category)
+	result.ConfidenceScore = rule.Confidence
+	result.Alternatives = alternatives
+	result.MatchedRule = rule.Name
+}
+
+// ReplacePrimary returns the categories with the first entry replaced by category,
+// removing any other occurrence of it. The categories are not modified.
+func ReplacePrimary(categories []string, category string) []string {
+	replaced := []string{category}
+	for i, existing := range categories {
+		if i > 0 && existing != category {
+			replaced = append(replaced, existing)
+		}
+	}
+	return replaced
+}
@@ -0,0 +1,325 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// match returns a match of a rule with the given action, whose value is its boost for boost rules
+// and its confidence otherwise.
+func match(name, category string, action Action, value float32) Match {
+	rule := Rule{Name: name, Category: category, Action: action}
+	if action == ActionBoost {
+		rule.Boost = value
+	} else {
+		rule.Confidence = value
+	}
+	return Match{Rule: rule}
+}
+
+func TestEvaluate(t *testing.T) {
+	engine, err := NewEngine(RuleSet{Rules: []Rule{
+		{Name: "shop-sender", Category: "PROMOTIONS", Senders: []string{"@shop.example.com"}},
+		{Name: "invoice", Category: "FINANCE", StrongKeywords: []string{"invoice", "payment"}},
+		{Name: "strict-invoice", Category: "FINANCE", StrongKeywords: []string{"invoice", "payment"}, MinScore: 6},
+		{Name: "receipt-subject", Category: "FINANCE", SubjectPatterns: []string{`^receipt\b`}},
+		{Name: "bulk", Category: "PROMOTIONS", HeaderIndicators: []string{"bulk"}},
+	}})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		email models.Email
+		want  []string // matching rules with their scores, highest first
+	}{
+		{
+			name:  "nothing matches",
+			email: models.Email{Sender: "friend@example.com", Subject: "Lunch?", Body: "Are you free tomorrow?"},
+		},
+		{
+			name:  "scores every kind of signal",
+			email: models.Email{Sender: "Shop <Orders@Shop.Example.com>", Subject: "Receipt for your order", Headers: map[string]string{"Precedence": "BULK"}},
+			want:  []string{"shop-sender:10", "receipt-subject:5", "bulk:4"},
+		},
+		{
+			name:  "requires the minimum score",
+			email: models.Email{Subject: "Your invoice", Body: "Thanks for shopping with us."},
+			want:  []string{"invoice:3"},
+		},
+		{
+			name:  "keeps the order of rules with equal scores",
+			email: models.Email{Subject: "Your invoice", Body: "Your payment was received."},
+			want:  []string{"invoice:6", "strict-invoice:6"},
+		},
+		{
+			name:  "matches keywords as whole words only",
+			email: models.Email{Subject: "Invoices", Body: "prepayment"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, m := range engine.Evaluate(&test.email) {
+				got = append(got, fmt.Sprintf("%s:%d", m.Rule.Name, m.Score))
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShortCircuit(t *testing.T) {
+	email := &models.Email{ID: "email-1"}
+
+	tests := []struct {
+		name    string
+		matches []Match
+		want    *models.CategoryResult
+	}{
+		{
+			name: "takes the highest scoring short_circuit rule",
+			matches: []Match{
+				match("work-override", "WORK", ActionOverride, 0.9),
+				match("promo-boost", "PROMOTIONS", ActionBoost, 0.5),
+				match("newsletter", "SUBSCRIPTIONS", ActionShortCircuit, 0.95),
+				match("social", "SOCIAL", ActionShortCircuit, 1),
+			},
+			want: &models.CategoryResult{EmailID: "email-1", Categories: []string{"SUBSCRIPTIONS"}, ConfidenceScore: 0.95, MatchedRule: "newsletter"},
+		},
+		{
+			name: "ignores override and boost rules",
+			matches: []Match{
+				match("work-override", "WORK", ActionOverride, 0.9),
+				match("promo-boost", "PROMOTIONS", ActionBoost, 0.5),
+			},
+		},
+		{
+			name: "no matches",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, ok := ShortCircuit(email, test.matches)
+			if ok != (test.want != nil) {
+				t.Fatalf("ShortCircuit() ok = %t, want %t", ok, test.want != nil)
+			}
+			if ok && fmt.Sprintf("%+v", result) != fmt.Sprintf("%+v", test.want) {
+				t.Errorf("ShortCircuit() = %+v, want %+v", result, test.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  models.CategoryResult
+		matches []Match
+		want    models.CategoryResult
+	}{
+		{
+			name: "override wins over boosts and demotes the prediction",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.75,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.25}},
+			},
+			matches: []Match{
+				match("finance-boost", "FINANCE", ActionBoost, 0.75),
+				match("promo-override", "PROMOTIONS", ActionOverride, 0.9),
+			},
+			want: models.CategoryResult{
+				Categories:      []string{"PROMOTIONS"},
+				ConfidenceScore: 0.9,
+				Alternatives:    []models.Alternative{{Category: "WORK", ConfidenceScore: 0.75}, {Category: "FINANCE", ConfidenceScore: 0.25}},
+				MatchedRule:     "promo-override",
+			},
+		},
+		{
+			name: "highest scoring override wins",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.75,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.25}},
+			},
+			matches: []Match{
+				match("finance-override", "FINANCE", ActionOverride, 0.8),
+				match("promo-override", "PROMOTIONS", ActionOverride, 0.9),
+			},
+			want: models.CategoryResult{
+				Categories:      []string{"FINANCE"},
+				ConfidenceScore: 0.8,
+				Alternatives:    []models.Alternative{{Category: "WORK", ConfidenceScore: 0.75}},
+				MatchedRule:     "finance-override",
+			},
+		},
+		{
+			name: "boost promotes an alternative",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.5,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.25}},
+			},
+			matches: []Match{match("finance-boost", "FINANCE", ActionBoost, 0.5)},
+			want: models.CategoryResult{
+				Categories:      []string{"FINANCE"},
+				ConfidenceScore: 0.75,
+				Alternatives:    []models.Alternative{{Category: "WORK", ConfidenceScore: 0.5}},
+				MatchedRule:     "finance-boost",
+			},
+		},
+		{
+			name: "boost adds a category the model did not predict",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.25,
+			},
+			matches: []Match{match("promo-boost", "PROMOTIONS", ActionBoost, 0.5)},
+			want: models.CategoryResult{
+				Categories:      []string{"PROMOTIONS"},
+				ConfidenceScore: 0.5,
+				Alternatives:    []models.Alternative{{Category: "WORK", ConfidenceScore: 0.25}},
+				MatchedRule:     "promo-boost",
+			},
+		},
+		{
+			name: "boosts clamp at 1",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.75,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.25}},
+			},
+			matches: []Match{
+				match("work-boost", "WORK", ActionBoost, 0.5),
+				match("finance-boost", "FINANCE", ActionBoost, 0.5),
+				match("finance-boost-again", "FINANCE", ActionBoost, 0.5),
+			},
+			want: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 1,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 1}},
+				MatchedRule:     "work-boost",
+			},
+		},
+		{
+			name: "ML prediction wins ties",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.5,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.25}},
+			},
+			matches: []Match{match("finance-boost", "FINANCE", ActionBoost, 0.25)},
+			want: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.5,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.5}},
+			},
+		},
+		{
+			name: "short_circuit rules do not apply",
+			result: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.5,
+			},
+			matches: []Match{match("newsletter", "SUBSCRIPTIONS", ActionShortCircuit, 0.95)},
+			want: models.CategoryResult{
+				Categories:      []string{"WORK"},
+				ConfidenceScore: 0.5,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.result
+			Apply(&result, test.matches)
+			if fmt.Sprintf("%+v", result) != fmt.Sprintf("%+v", test.want) {
+				t.Errorf("Apply() = %+v, want %+v", result, test.want)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	email := &models.Email{ID: "email-1"}
+
+	tests := []struct {
+		name    string
+		matches []Match
+		want    *models.CategoryResult
+	}{
+		{
+			name: "override wins over boosts",
+			matches: []Match{
+				match("finance-boost", "FINANCE", ActionBoost, 0.75),
+				match("work-override", "WORK", ActionOverride, 0.9),
+				match("promo-override", "PROMOTIONS", ActionOverride, 0.8),
+			},
+			want: &models.CategoryResult{EmailID: "email-1", Categories: []string{"WORK"}, ConfidenceScore: 0.9, MatchedRule: "work-override"},
+		},
+		{
+			name: "ranks boosted categories by their summed boosts",
+			matches: []Match{
+				match("promo-boost", "PROMOTIONS", ActionBoost, 0.5),
+				match("finance-boost", "FINANCE", ActionBoost, 0.25),
+				match("finance-boost-again", "FINANCE", ActionBoost, 0.5),
+			},
+			want: &models.CategoryResult{
+				EmailID:         "email-1",
+				Categories:      []string{"FINANCE"},
+				ConfidenceScore: 0.75,
+				Alternatives:    []models.Alternative{{Category: "PROMOTIONS", ConfidenceScore: 0.5}},
+				MatchedRule:     "finance-boost",
+			},
+		},
+		{
+			name: "boosts clamp at 1",
+			matches: []Match{
+				match("finance-boost", "FINANCE", ActionBoost, 0.75),
+				match("finance-boost-again", "FINANCE", ActionBoost, 0.75),
+			},
+			want: &models.CategoryResult{EmailID: "email-1", Categories: []string{"FINANCE"}, ConfidenceScore: 1, MatchedRule: "finance-boost"},
+		},
+		{
+			name: "ties keep the order of the matches",
+			matches: []Match{
+				match("promo-boost", "PROMOTIONS", ActionBoost, 0.5),
+				match("finance-boost", "FINANCE", ActionBoost, 0.5),
+			},
+			want: &models.CategoryResult{
+				EmailID:         "email-1",
+				Categories:      []string{"PROMOTIONS"},
+				ConfidenceScore: 0.5,
+				Alternatives:    []models.Alternative{{Category: "FINANCE", ConfidenceScore: 0.5}},
+				MatchedRule:     "promo-boost",
+			},
+		},
+		{
+			name:    "short_circuit rules alone do not categorize",
+			matches: []Match{match("newsletter", "SUBSCRIPTIONS", ActionShortCircuit, 0.95)},
+		},
+		{
+			name: "no matches",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, ok := Fallback(email, test.matches)
+			if ok != (test.want != nil) {
+				t.Fatalf("Fallback() ok = %t, want %t", ok, test.want != nil)
+			}
+			if ok && fmt.Sprintf("%+v", result) != fmt.Sprintf("%+v", test.want) {
+				t.Errorf("Fallback() = %+v, want %+v", result, test.want)
+			}
+		})
+	}
+}
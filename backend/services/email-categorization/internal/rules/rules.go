@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action determines what a matching rule does to the categorization of an email.
+type Action string
+
+const (
+	// ActionShortCircuit assigns the rule's category without calling the ML service.
+	ActionShortCircuit Action = "short_circuit"
+
+	// ActionOverride replaces the ML prediction with the rule's category.
+	ActionOverride Action = "override"
+
+	// ActionBoost adds the rule's boost to the ML confidence of the rule's category,
+	// which may promote it over the ML service's primary prediction.
+	ActionBoost Action = "boost"
+)
+
+// Scoring weights for each kind of classification signal. They mirror the weights
+// used by the ML server's EmailLabeler so rules and labeled training data agree.
+const (
+	senderWeight  = 10
+	keywordWeight = 3
+	subjectWeight = 5
+	headerWeight  = 4
+)
+
+// RuleSet is the top-level structure of a rules file.
+type RuleSet struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Rule describes the signals that identify a category and what to do when they match.
+// The signal lists follow the structure of the ML server's category_data.py patterns:
+//   - Senders: substrings of the sender address, e.g. "@company.com" or "@billing."
+//   - StrongKeywords: words or phrases looked up in the subject and body
+//   - SubjectPatterns: regular expressions matched against the subject
+//   - HeaderIndicators: substrings looked up in the header values
+//
+// A rule matches when the weighted score of its signals reaches MinScore.
+type Rule struct {
+	Name             string   `json:"name" yaml:"name"`
+	Category         string   `json:"category" yaml:"category"`
+	Action           Action   `json:"action" yaml:"action"`
+	Confidence       float32  `json:"confidence" yaml:"confidence"` // Confidence assigned by short_circuit and override rules
+	Boost            float32  `json:"boost" yaml:"boost"`           // Confidence added by boost rules
+	MinScore         int      `json:"min_score" yaml:"min_score"`   // Minimum signal score for the rule to match
+	Senders          []string `json:"senders" yaml:"senders"`
+	StrongKeywords   []string `json:"strong_keywords" yaml:"strong_keywords"`
+	SubjectPatterns  []string `json:"subject_patterns" yaml:"subject_patterns"`
+	HeaderIndicators []string `json:"header_indicators" yaml:"header_indicators"`
+}
+
+// compiledRule is a Rule with its patterns compiled and its values normalized for matching.
+type compiledRule struct {
+	Rule
+	senders          []string
+	keywords         []*regexp.Regexp
+	subjectPatterns  []*regexp.Regexp
+	headerIndicators []string
+}
+
+// LoadFile reads a rule set from a YAML or JSON file, chosen by the file extension,
+// and builds an Engine from it. An empty path returns an Engine without any rules.
+func LoadFile(path string) (*Engine, error) {
+	if path == "" {
+		return NewEngine(RuleSet{})
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var ruleSet RuleSet
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &ruleSet)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &ruleSet)
+	default:
+		return nil, fmt.Errorf("unsupported rules file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+
+	return NewEngine(ruleSet)
+}
+
+// compile validates a rule, applies its defaults, and prepares it for matching.
+func compile(rule Rule) (*compiledRule, error) {
+	if rule.Name == "" {
+		return nil, fmt.Errorf("rule for category %q has no name", rule.Category)
+	}
+	if rule.Category == "" {
+		return nil, fmt.Errorf("rule %q has no category", rule.Name)
+	}
+
+	switch rule.Action {
+	case ActionShortCircuit, ActionOverride, ActionBoost:
+	case "":
+		rule.Action = ActionBoost
+	default:
+		return nil, fmt.Errorf("rule %q has unknown action %q", rule.Name, rule.Action)
+	}
+
+	if rule.Confidence <= 0 || rule.Confidence > 1 {
+		rule.Confidence = 1
+	}
+	if rule.MinScore <= 0 {
+		rule.MinScore = 1
+	}
+
+	compiled := &compiledRule{Rule: rule}
+
+	for _, sender := range rule.Senders {
+		compiled.senders = append(compiled.senders, strings.ToLower(sender))
+	}
+
+	// Keywords only match whole words, so "cv" does not match inside "cvs".
+	for _, keyword := range rule.StrongKeywords {
+		pattern := `(?i)\b` + regexp.QuoteMeta(keyword) + `\b`
+		compiled.keywords = append(compiled.keywords, regexp.MustCompile(pattern))
+	}
+
+	for _, pattern := range rule.SubjectPatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q has invalid subject pattern %q: %w", rule.Name, pattern, err)
+		}
+		compiled.subjectPatterns = append(compiled.subjectPatterns, re)
+	}
+
+	for _, indicator := range rule.HeaderIndicators {
+		compiled.headerIndicators = append(compiled.headerIndicators, strings.ToLower(indicator))
+	}
+
+	return compiled, nil
+}
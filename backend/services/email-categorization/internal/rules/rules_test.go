@@ -0,0 +1,48 @@
+package rules
+
+import "testing"
+
+func TestNewEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{name: "valid", rule: Rule{Name: "invoice", Category: "FINANCE", Action: ActionOverride}},
+		{name: "no name", rule: Rule{Category: "FINANCE"}, wantErr: true},
+		{name: "no category", rule: Rule{Name: "invoice"}, wantErr: true},
+		{name: "unknown action", rule: Rule{Name: "invoice", Category: "FINANCE", Action: "replace"}, wantErr: true},
+		{name: "invalid subject pattern", rule: Rule{Name: "invoice", Category: "FINANCE", SubjectPatterns: []string{"(invoice"}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewEngine(RuleSet{Rules: []Rule{test.rule}})
+			if (err != nil) != test.wantErr {
+				t.Errorf("NewEngine() error = %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewEngineDefaults(t *testing.T) {
+	engine, err := NewEngine(RuleSet{Rules: []Rule{{Name: "invoice", Category: "FINANCE", Confidence: 1.5}}})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	rule := engine.Rules()[0]
+	if rule.Action != ActionBoost || rule.Confidence != 1 || rule.MinScore != 1 {
+		t.Errorf("rule = %+v, want a boost rule with confidence 1 and minimum score 1", rule)
+	}
+
+	explicit, err := NewEngine(RuleSet{Rules: []Rule{{Name: "invoice", Category: "FINANCE", Action: ActionBoost, Confidence: 1, MinScore: 1}}})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	if engine.Hash() != explicit.Hash() {
+		t.Error("rule sets differing only in their defaults hash differently")
+	}
+	if empty, _ := NewEngine(RuleSet{}); empty.Hash() != "" {
+		t.Errorf("Hash() of an engine without rules = %q, want it empty", empty.Hash())
+	}
+}
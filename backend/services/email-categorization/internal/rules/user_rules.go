@@ -0,0 +1,26 @@
+package rules
+
+import "github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+
+// FromUserCategories builds a rule set from a user's custom categories. Every rule of a category
+// becomes an override rule for that category, so a matching user rule always wins over the ML
+// prediction and the service-wide rules. Rule names are prefixed with the category name to keep
+// them unique across categories.
+func FromUserCategories(categories []models.UserCategory) RuleSet {
+	var ruleSet RuleSet
+	for _, category := range categories {
+		for _, rule := range category.Rules {
+			ruleSet.Rules = append(ruleSet.Rules, Rule{
+				Name:             category.Name + "/" + rule.Name,
+				Category:         category.Name,
+				Action:           ActionOverride,
+				MinScore:         rule.MinScore,
+				Senders:          rule.Senders,
+				StrongKeywords:   rule.StrongKeywords,
+				SubjectPatterns:  rule.SubjectPatterns,
+				HeaderIndicators: rule.HeaderIndicators,
+			})
+		}
+	}
+	return ruleSet
+}
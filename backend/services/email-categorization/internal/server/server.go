@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -8,40 +9,172 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/cache"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/classifier"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/handlers"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/routing"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/rules"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/taxonomy"
 
 	mlclient "github.com/samiransarii/inboXpert/services/common/ml_client"
 	pb "github.com/samiransarii/inboXpert/services/email-categorization/proto"
 )
 
 // Server initializes and runs a gRPC server for email categorization.
-// It sets up the ML client, email repository, and categorization handler,
-// then registers the gRPC service and manages startup/shutdown.
+// It sets up the ML client or embedded model, email repository, rules, taxonomy,
+// model routing, and categorization handler, then registers the gRPC service and
+// manages startup/shutdown.
 type Server struct {
-	config       *models.Config
-	mlClient     mlclient.Service
-	grpcServer   *grpc.Server
-	categHandler *handlers.CategorizationHandler
-	emailRepo    *handlers.EmailRepository
+	config          *models.Config
+	mlClient        mlclient.Service
+	mlReplicas      *mlclient.MLPredictionClient
+	mlBreaker       *mlclient.Breaker
+	router          *routing.Router
+	predictionCache *cache.Client
+	grpcServer      *grpc.Server
+	categHandler    *handlers.CategorizationHandler
+	emailRepo       *handlers.EmailRepository
 }
 
-// NewServer creates a new Server instance, configuring the ML client, repository, handlers,
+// NewServer creates a new Server instance, configuring the ML client, repository, rules, taxonomy, handlers,
 // and the gRPC server. It returns an error if any of the components fail to initialize.
 func NewServer(config *models.Config) (*Server, error) {
-	// Initialize the machine learning client
-	mlClient, err := mlclient.NewClient(mlclient.ClientConfig{
-		Address: config.MLServerAddr,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create ML client: %w", err)
+	// Load the embedded model if it classifies emails or serves as the fallback of the ML service
+	var model *classifier.Model
+	if config.MLBackend == models.BackendEmbedded || config.MLFallback == models.FallbackModel {
+		if config.MLModelFile == "" {
+			return nil, fmt.Errorf("ML_MODEL_FILE is required by the %s backend or the %s fallback", models.BackendEmbedded, models.FallbackModel)
+		}
+		var err error
+		model, err = classifier.Load(config.MLModelFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load embedded model: %w", err)
+		}
+		log.Printf("Loaded embedded model %s of %d categories", model.Version, len(model.Classes))
+	}
+
+	var service mlclient.Service
+	var mlClient *mlclient.MLPredictionClient
+	var breaker *mlclient.Breaker
+	modelVersion := config.ModelVersion
+	if config.MLBackend == models.BackendEmbedded {
+		// Classify emails in-process, without the ML server
+		service = classifier.NewClient(model)
+		if model.Version != "" {
+			modelVersion = model.Version
+		}
+	} else {
+		// Initialize the machine learning client
+		var err error
+		mlClient, err = mlclient.NewClient(mlclient.ClientConfig{
+			Addresses:           config.MLServerAddrs,
+			Policy:              config.MLBalancingPolicy,
+			HealthCheckInterval: config.MLHealthCheckInterval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ML client: %w", err)
+		}
+
+		// Stop calling the ML service while it keeps failing, so requests fail fast or fall back
+		breaker = mlclient.NewBreaker(mlClient, mlclient.BreakerConfig{
+			FailureThreshold: config.BreakerFailureThreshold,
+			OpenTimeout:      config.BreakerOpenTimeout,
+			HalfOpenRequests: config.BreakerHalfOpenRequests,
+		})
+		service = breaker
+
+		// Coalesce concurrent single-email requests into batch requests to the ML service. The
+		// coalescer wraps the breaker, so a failed batch counts as a single failure
+		if config.MLBatchWait > 0 {
+			service = mlclient.NewCoalescer(breaker, mlclient.CoalescerConfig{
+				MaxBatchSize: config.MLBatchSize,
+				MaxWait:      config.MLBatchWait,
+			})
+		}
+	}
+
+	// Put the prediction cache in front of the ML client, with its optional Postgres tier
+	var predictionCache *cache.Client
+	if config.MLCacheSize > 0 {
+		var store *cache.PostgresStore
+		if config.MLCachePersistent {
+			store = cache.NewPostgresStore(config.DBPool)
+			if err := store.EnsureSchema(context.Background()); err != nil {
+				return nil, fmt.Errorf("failed to prepare prediction cache table: %w", err)
+			}
+			if purged, err := store.Purge(context.Background(), modelVersion); err == nil && purged > 0 {
+				log.Printf("Purged %d stale cached predictions", purged)
+			}
+		}
+		predictionCache = cache.NewClient(service, cache.Options{
+			Size:         config.MLCacheSize,
+			TTL:          config.MLCacheTTL,
+			ModelVersion: modelVersion,
+			Store:        store,
+		})
+		service = predictionCache
 	}
 
-	// Initialize the email repository for database operations
+	// Initialize the email repository for database operations, adding the feedback columns if needed
 	emailRepo := handlers.NewEmailRepository(config.DBPool)
+	if err := emailRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare categories table: %w", err)
+	}
+
+	// Initialize the repository for users' custom categories, creating its table if needed
+	categoryRepo := handlers.NewCategoryRepository(config.DBPool)
+	if err := categoryRepo.EnsureSchema(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to prepare user categories table: %w", err)
+	}
+
+	// Load the rules applied around the ML prediction
+	ruleEngine, err := rules.LoadFile(config.RulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+	log.Printf("Loaded %d categorization rules", ruleEngine.Len())
+
+	// Load the taxonomy of categories and make sure every rule assigns one of them
+	tax, err := taxonomy.LoadFile(config.TaxonomyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load taxonomy: %w", err)
+	}
+	if err := checkRuleCategories(ruleEngine, tax); err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded taxonomy of %d categories", tax.Len())
+
+	// Connect to the models emails are shadowed to or split between besides the primary one
+	routingConfig, err := routing.LoadFile(config.RoutingFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load routing: %w", err)
+	}
+	services := make(map[string]mlclient.Service, len(routingConfig.Models))
+	for _, spec := range routingConfig.Models {
+		services[spec.Name], err = newModelService(spec, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to model %q: %w", spec.Name, err)
+		}
+	}
+	router, err := routing.NewRouter(routing.Model{Name: modelVersion, Service: service}, routingConfig, services)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create router: %w", err)
+	}
 
 	// Create the categorization handler that ties everything together
-	handler := handlers.NewCategorizationHandler(mlClient, config, emailRepo)
+	handler := handlers.NewCategorizationHandler(service, config, emailRepo, categoryRepo, ruleEngine, tax)
+	if config.MLFallback == models.FallbackModel && config.MLBackend != models.BackendEmbedded {
+		handler.SetFallbackClient(classifier.NewClient(model))
+	}
+	if router.Enabled() {
+		predictionRepo := handlers.NewPredictionRepository(config.DBPool)
+		if err := predictionRepo.EnsureSchema(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to prepare model predictions table: %w", err)
+		}
+		handler.SetRouter(router, predictionRepo)
+		log.Printf("Routing emails to %d models besides %s", len(routingConfig.Models), modelVersion)
+	}
 
 	// Create and register the gRPC server and reflection service
 	grpcServer := grpc.NewServer()
@@ -49,11 +182,15 @@ func NewServer(config *models.Config) (*Server, error) {
 	reflection.Register(grpcServer)
 
 	return &Server{
-		config:       config,
-		mlClient:     mlClient,
-		grpcServer:   grpcServer,
-		categHandler: handler,
-		emailRepo:    emailRepo,
+		config:          config,
+		mlClient:        service,
+		mlReplicas:      mlClient,
+		mlBreaker:       breaker,
+		router:          router,
+		predictionCache: predictionCache,
+		grpcServer:      grpcServer,
+		categHandler:    handler,
+		emailRepo:       emailRepo,
 	}, nil
 }
 
@@ -69,13 +206,70 @@ func (s *Server) Start() error {
 	return s.grpcServer.Serve(listener)
 }
 
-// Stop gracefully stops the gRPC server and closes the ML client, ensuring no new
-// requests are accepted and ongoing requests are completed before shutdown.
+// Stop gracefully stops the gRPC server and closes the ML clients, ensuring no new
+// requests are accepted and ongoing requests are completed before shutdown. The prediction
+// cache, circuit breaker, and ML replica state are logged once every request has finished.
 func (s *Server) Stop() {
+	s.grpcServer.GracefulStop()
+
+	if s.predictionCache != nil {
+		stats := s.predictionCache.Stats()
+		log.Printf("Prediction cache: %d hits, %d persistent hits, %d misses", stats.Hits, stats.PersistentHits, stats.Misses)
+	}
+	if s.mlBreaker != nil {
+		log.Printf("ML circuit breaker: %s", s.mlBreaker.State())
+	}
+	if s.mlReplicas != nil {
+		for _, replica := range s.mlReplicas.Stats() {
+			log.Printf("ML replica %s: healthy=%t, %d requests, %d failures", replica.Address, replica.Healthy, replica.Requests, replica.Failures)
+		}
+	}
 	if s.mlClient != nil {
 		if err := s.mlClient.Close(); err != nil {
 			log.Printf("Error closing ML client: %v", err)
 		}
 	}
-	s.grpcServer.GracefulStop()
+	if s.router != nil {
+		if err := s.router.Close(); err != nil {
+			log.Printf("Error closing routed models: %v", err)
+		}
+	}
+}
+
+// newModelService connects to a model emails are shadowed to or split to: the replicas of the ML server
+// at its addresses, behind a circuit breaker of their own so a failing model fails fast, or its embedded
+// model. Its predictions are not cached, since the cache is kept for the primary model.
+func newModelService(spec routing.ModelSpec, config *models.Config) (mlclient.Service, error) {
+	if spec.ModelFile != "" {
+		model, err := classifier.Load(spec.ModelFile)
+		if err != nil {
+			return nil, err
+		}
+		return classifier.NewClient(model), nil
+	}
+
+	client, err := mlclient.NewClient(mlclient.ClientConfig{
+		Addresses:           spec.Addresses,
+		Policy:              config.MLBalancingPolicy,
+		HealthCheckInterval: config.MLHealthCheckInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mlclient.NewBreaker(client, mlclient.BreakerConfig{
+		FailureThreshold: config.BreakerFailureThreshold,
+		OpenTimeout:      config.BreakerOpenTimeout,
+		HalfOpenRequests: config.BreakerHalfOpenRequests,
+	}), nil
+}
+
+// checkRuleCategories makes sure every rule assigns a category of the taxonomy, so rules cannot
+// categorize emails under categories the taxonomy does not know about.
+func checkRuleCategories(engine *rules.Engine, tax *taxonomy.Taxonomy) error {
+	for _, rule := range engine.Rules() {
+		if !tax.Contains(rule.Category) {
+			return fmt.Errorf("rule %q assigns category %q, which is not in the taxonomy", rule.Name, rule.Category)
+		}
+	}
+	return nil
 }
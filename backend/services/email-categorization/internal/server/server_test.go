@@ -0,0 +1,49 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/rules"
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/taxonomy"
+)
+
+// TestExampleConfigs loads the example rules with the example and default taxonomies, as the
+// service does at startup, so the documented configuration keeps starting the service.
+func TestExampleConfigs(t *testing.T) {
+	engine, err := rules.LoadFile("../../rules.example.yaml")
+	if err != nil {
+		t.Fatalf("failed to load example rules: %v", err)
+	}
+	if engine.Len() == 0 {
+		t.Fatal("example rules file defines no rules")
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{name: "example taxonomy", file: "../../taxonomy.example.yaml"},
+		{name: "default taxonomy"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tax, err := taxonomy.LoadFile(test.file)
+			if err != nil {
+				t.Fatalf("failed to load taxonomy: %v", err)
+			}
+			if err := checkRuleCategories(engine, tax); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestCheckRuleCategoriesRejectsUnknownCategories(t *testing.T) {
+	engine, err := rules.NewEngine(rules.RuleSet{Rules: []rules.Rule{{Name: "work-domain", Category: "WORK", Senders: []string{"@company.com"}}}})
+	if err != nil {
+		t.Fatalf("failed to create rules: %v", err)
+	}
+	if err := checkRuleCategories(engine, taxonomy.Default()); err == nil {
+		t.Error("checkRuleCategories() error = nil, want an error for a category outside the taxonomy")
+	}
+}
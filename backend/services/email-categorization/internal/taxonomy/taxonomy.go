@@ -0,0 +1,188 @@
+package taxonomy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
+)
+
+// Node is a category of the taxonomy. ID is what the ML service, the rules, and the stored results
+// use to refer to the category, Name is what users see, and Parent is the ID of the enclosing
+// category, or empty for a top-level category.
+type Node struct {
+	ID     string `json:"id" yaml:"id"`
+	Name   string `json:"name" yaml:"name"`
+	Parent string `json:"parent" yaml:"parent"`
+}
+
+// Definition is the top-level structure of a taxonomy file. Categories may be listed in any
+// order, as long as every parent is defined somewhere in the file.
+type Definition struct {
+	Categories []Node `json:"categories" yaml:"categories"`
+}
+
+// Taxonomy is a validated tree of categories. It is safe for concurrent use, since it is never
+// modified after construction.
+type Taxonomy struct {
+	nodes    map[string]Node
+	roots    []string
+	children map[string][]string
+}
+
+// defaultCategories are the categories the ML service predicts, used when no taxonomy file is configured.
+var defaultCategories = []Node{
+	{ID: "PERSONAL", Name: "Personal"},
+	{ID: "CAREER", Name: "Career"},
+	{ID: "FINANCE", Name: "Finance"},
+	{ID: "SHOPPING", Name: "Shopping"},
+	{ID: "HEALTH", Name: "Health"},
+	{ID: "SUBSCRIPTIONS", Name: "Subscriptions"},
+}
+
+// New validates the categories of a definition and builds a Taxonomy from them. Every category
+// needs a unique ID, and every parent must be another category of the definition, without cycles.
+// A top-level category for models.NeedsReviewCategory is added if the definition has none, so
+// emails awaiting review always have a category to be shown under.
+func New(definition Definition) (*Taxonomy, error) {
+	t := &Taxonomy{
+		nodes:    make(map[string]Node, len(definition.Categories)+1),
+		children: make(map[string][]string),
+	}
+
+	for _, node := range definition.Categories {
+		if node.ID == "" {
+			return nil, fmt.Errorf("category %q has no id", node.Name)
+		}
+		if _, exists := t.nodes[node.ID]; exists {
+			return nil, fmt.Errorf("category %q is defined more than once", node.ID)
+		}
+		if node.Name == "" {
+			node.Name = node.ID
+		}
+		t.nodes[node.ID] = node
+	}
+	if _, exists := t.nodes[models.NeedsReviewCategory]; !exists {
+		t.nodes[models.NeedsReviewCategory] = Node{ID: models.NeedsReviewCategory, Name: "Needs review"}
+		definition.Categories = append(definition.Categories, t.nodes[models.NeedsReviewCategory])
+	}
+
+	// Link the categories in the order they were defined, so siblings keep that order.
+	for _, node := range definition.Categories {
+		node = t.nodes[node.ID]
+		if node.Parent == "" {
+			t.roots = append(t.roots, node.ID)
+			continue
+		}
+		if _, exists := t.nodes[node.Parent]; !exists {
+			return nil, fmt.Errorf("category %q has unknown parent %q", node.ID, node.Parent)
+		}
+		t.children[node.Parent] = append(t.children[node.Parent], node.ID)
+	}
+
+	// Every category must lead to a top-level category; otherwise its parents form a cycle.
+	for id := range t.nodes {
+		if len(t.Path(id)) > len(t.nodes) {
+			return nil, fmt.Errorf("category %q is part of a parent cycle", id)
+		}
+	}
+
+	return t, nil
+}
+
+// Default returns the flat taxonomy of the categories predicted by the ML service.
+func Default() *Taxonomy {
+	t, err := New(Definition{Categories: defaultCategories})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default taxonomy: %v", err))
+	}
+	return t
+}
+
+// LoadFile reads a taxonomy from a YAML or JSON file, chosen by the file extension.
+// An empty path returns the default taxonomy.
+func LoadFile(path string) (*Taxonomy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read taxonomy file: %w", err)
+	}
+
+	var definition Definition
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &definition)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &definition)
+	default:
+		return nil, fmt.Errorf("unsupported taxonomy file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse taxonomy file: %w", err)
+	}
+
+	return New(definition)
+}
+
+// Len returns the number of categories in the taxonomy.
+func (t *Taxonomy) Len() int {
+	return len(t.nodes)
+}
+
+// Contains reports whether id is a category of the taxonomy.
+func (t *Taxonomy) Contains(id string) bool {
+	_, exists := t.nodes[id]
+	return exists
+}
+
+// Node returns the category with the given ID.
+func (t *Taxonomy) Node(id string) (Node, bool) {
+	node, exists := t.nodes[id]
+	return node, exists
+}
+
+// Roots returns the IDs of the top-level categories, in the order they were defined.
+func (t *Taxonomy) Roots() []string {
+	return t.roots
+}
+
+// Children returns the IDs of the direct subcategories of a category, in the order they were defined.
+func (t *Taxonomy) Children(id string) []string {
+	return t.children[id]
+}
+
+// Path returns the IDs of a category's ancestors followed by the category itself, starting with
+// its top-level category, e.g. [SHOPPING SHOPPING_RECEIPTS]. It returns nil for unknown categories.
+func (t *Taxonomy) Path(id string) []string {
+	var path []string
+	for current, exists := t.nodes[id]; exists; current, exists = t.nodes[current.Parent] {
+		path = append(path, current.ID)
+		if len(path) > len(t.nodes) {
+			break // a parent cycle; only possible while New validates the taxonomy
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// RollUp aggregates per-category counts up the tree: the total of every category is its own count
+// plus the totals of its subcategories. Counts of unknown categories are ignored.
+func (t *Taxonomy) RollUp(counts map[string]int) map[string]int {
+	totals := make(map[string]int, len(t.nodes))
+	for id, count := range counts {
+		for _, ancestor := range t.Path(id) {
+			totals[ancestor] += count
+		}
+	}
+	return totals
+}
@@ -28,6 +28,9 @@ func ToServiceModel(e *db.EmailDB) models.Email {
 
 	return models.Email{
 		ID:         e.ID,
+		ClientID:   e.ClientID,
+		Mailbox:    e.Mailbox,
+		UserID:     e.UserID,
 		Sender:     e.Sender,
 		Subject:    e.Subject,
 		Body:       e.Body,
@@ -53,6 +56,9 @@ func FromServiceModel(email models.Email) db.EmailDB {
 
 	return db.EmailDB{
 		ID:         email.ID,
+		ClientID:   email.ClientID,
+		Mailbox:    email.Mailbox,
+		UserID:     email.UserID,
 		Headers:    string(headerJSON),
 		Subject:    email.Subject,
 		Sender:     email.Sender,
@@ -60,3 +66,198 @@ func FromServiceModel(email models.Email) db.EmailDB {
 		Body:       email.Body,
 	}
 }
+
+// ToCategoryDetailsJSON serializes the categories, labels, alternatives, keywords, and review state
+// of a CategoryResult into the JSON document stored in a CatgegoryRecord.
+func ToCategoryDetailsJSON(result *models.CategoryResult) (string, error) {
+	details := db.CategoryDetails{
+		Categories:        result.Categories,
+		Keywords:          result.Keywords,
+		MatchedRule:       result.MatchedRule,
+		NeedsReview:       result.NeedsReview,
+		PredictedCategory: result.PredictedCategory,
+		Degraded:          result.Degraded,
+	}
+	for _, label := range result.Labels {
+		details.Labels = append(details.Labels, db.LabelDetail{
+			Category:        label.Category,
+			ConfidenceScore: label.ConfidenceScore,
+		})
+	}
+	for _, alt := range result.Alternatives {
+		details.Alternatives = append(details.Alternatives, db.AlternativeDetail{
+			Category:        alt.Category,
+			ConfidenceScore: alt.ConfidenceScore,
+		})
+	}
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return "", err
+	}
+	return string(detailsJSON), nil
+}
+
+// FromCategoryRecord converts a stored categorization record into a service-level CategoryResult.
+// The categories are decoded from the CategoryDetails JSON document, including the older forms
+// holding only a list of categories, and the provenance is read from the record's own columns,
+// which are empty for records stored before provenance was kept.
+func FromCategoryRecord(record *db.CatgegoryRecord) models.CategoryResult {
+	details := decodeCategoryDetails(record.Categories)
+
+	alternatives := make([]models.Alternative, len(details.Alternatives))
+	for i, alt := range details.Alternatives {
+		alternatives[i] = models.Alternative{
+			Category:        alt.Category,
+			ConfidenceScore: alt.ConfidenceScore,
+		}
+	}
+
+	// Records stored before labels carried their own scores only know the primary category's score.
+	labels := make([]models.Label, len(details.Labels))
+	for i, label := range details.Labels {
+		labels[i] = models.Label{
+			Category:        label.Category,
+			ConfidenceScore: label.ConfidenceScore,
+		}
+	}
+	if len(labels) == 0 && len(details.Categories) > 0 {
+		labels = []models.Label{{Category: details.Categories[0], ConfidenceScore: record.ConfidenceScore}}
+	}
+
+	return models.CategoryResult{
+		EmailID:           record.EmailID,
+		Categories:        details.Categories,
+		ConfidenceScore:   record.ConfidenceScore,
+		Labels:            labels,
+		Alternatives:      alternatives,
+		Keywords:          details.Keywords,
+		MatchedRule:       details.MatchedRule,
+		NeedsReview:       details.NeedsReview,
+		PredictedCategory: details.PredictedCategory,
+		Degraded:          details.Degraded,
+		Provenance: models.Provenance{
+			ModelName:      record.ModelName,
+			ModelVersion:   record.ModelVersion,
+			RuleSetHash:    record.RuleSetHash,
+			ServiceVersion: record.ServiceVersion,
+			DecisionPath:   models.DecisionPath(record.DecisionPath),
+		},
+	}
+}
+
+// decodeCategoryDetails parses the categories column in any of the formats it has been stored in.
+func decodeCategoryDetails(raw string) db.CategoryDetails {
+	var details db.CategoryDetails
+	if raw == "" {
+		return details
+	}
+
+	// Legacy records hold the JSON array encoded once more as a JSON string
+	var encoded string
+	if err := json.Unmarshal([]byte(raw), &encoded); err == nil {
+		raw = encoded
+	}
+
+	if err := json.Unmarshal([]byte(raw), &details); err == nil {
+		return details
+	}
+	if err := json.Unmarshal([]byte(raw), &details.Categories); err != nil {
+		log.Printf("Failed to deserialize categories: %v", err)
+	}
+	return details
+}
+
+// ToUserCategoryDB converts a service-level UserCategory into a database-friendly UserCategoryDB
+// structure. The category's rules are serialized into a JSON string for storage.
+func ToUserCategoryDB(category models.UserCategory) (db.UserCategoryDB, error) {
+	rules := make([]db.CategoryRuleDetail, len(category.Rules))
+	for i, rule := range category.Rules {
+		rules[i] = db.CategoryRuleDetail{
+			Name:             rule.Name,
+			Senders:          rule.Senders,
+			StrongKeywords:   rule.StrongKeywords,
+			SubjectPatterns:  rule.SubjectPatterns,
+			HeaderIndicators: rule.HeaderIndicators,
+			MinScore:         rule.MinScore,
+		}
+	}
+
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return db.UserCategoryDB{}, err
+	}
+
+	return db.UserCategoryDB{
+		ID:          category.ID,
+		UserID:      category.UserID,
+		Name:        category.Name,
+		Description: category.Description,
+		Rules:       string(rulesJSON),
+	}, nil
+}
+
+// FromUserCategoryDB converts a database UserCategoryDB record into a service-level UserCategory.
+// It handles JSON deserialization of the category's rules.
+func FromUserCategoryDB(c *db.UserCategoryDB) models.UserCategory {
+	var rules []db.CategoryRuleDetail
+	if c.Rules != "" {
+		if err := json.Unmarshal([]byte(c.Rules), &rules); err != nil {
+			log.Printf("Failed to deserialize category rules: %v", err)
+		}
+	}
+
+	category := models.UserCategory{
+		ID:          c.ID,
+		UserID:      c.UserID,
+		Name:        c.Name,
+		Description: c.Description,
+		Rules:       make([]models.CategoryRule, len(rules)),
+	}
+	for i, rule := range rules {
+		category.Rules[i] = models.CategoryRule{
+			Name:             rule.Name,
+			Senders:          rule.Senders,
+			StrongKeywords:   rule.StrongKeywords,
+			SubjectPatterns:  rule.SubjectPatterns,
+			HeaderIndicators: rule.HeaderIndicators,
+			MinScore:         rule.MinScore,
+		}
+	}
+	return category
+}
+
+// ToModelPredictionDB converts a service-level ModelPrediction into a database-friendly ModelPredictionDB.
+// The user's correction is not part of a prediction record, so it is left out.
+func ToModelPredictionDB(prediction models.ModelPrediction) db.ModelPredictionDB {
+	return db.ModelPredictionDB{
+		EmailID:          prediction.EmailID,
+		Model:            prediction.Model,
+		Shadow:           prediction.Shadow,
+		Category:         prediction.Category,
+		Confidence:       prediction.Confidence,
+		ServedModel:      prediction.ServedModel,
+		ServedCategory:   prediction.ServedCategory,
+		ServedConfidence: prediction.ServedConfidence,
+		CreatedAt:        prediction.CreatedAt,
+	}
+}
+
+// FromModelPredictionDB converts a database ModelPredictionDB record into a service-level ModelPrediction.
+func FromModelPredictionDB(p *db.ModelPredictionDB) models.ModelPrediction {
+	prediction := models.ModelPrediction{
+		EmailID:          p.EmailID,
+		Model:            p.Model,
+		Shadow:           p.Shadow,
+		Category:         p.Category,
+		Confidence:       p.Confidence,
+		ServedModel:      p.ServedModel,
+		ServedCategory:   p.ServedCategory,
+		ServedConfidence: p.ServedConfidence,
+		CreatedAt:        p.CreatedAt,
+	}
+	if p.CorrectedCategory != nil {
+		prediction.CorrectedCategory = *p.CorrectedCategory
+	}
+	return prediction
+}
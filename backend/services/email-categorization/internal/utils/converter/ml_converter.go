@@ -1,6 +1,8 @@
 package converter
 
 import (
+	"sort"
+
 	mlpb "github.com/samiransarii/inboXpert/services/common/ml_server_protogen"
 	"github.com/samiransarii/inboXpert/services/email-categorization/internal/models"
 )
@@ -19,7 +21,8 @@ func ToMLRequest(req *models.MLRequest) *mlpb.EmailRequest {
 }
 
 // FromMLResponse converts a CategoryResponse from the ML service (protobuf form)
-// into the internal MLResponse model. It also maps any alternative categories.
+// into the internal MLResponse model. It also maps any alternative categories, ranked
+// from most to least confident, and the explanatory keywords.
 func FromMLResponse(resp *mlpb.CategoryResponse) *models.MLResponse {
 	alternatives := make([]models.Alternative, len(resp.Alternatives))
 	for i, alt := range resp.Alternatives {
@@ -28,11 +31,15 @@ func FromMLResponse(resp *mlpb.CategoryResponse) *models.MLResponse {
 			ConfidenceScore: alt.Confindence,
 		}
 	}
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		return alternatives[i].ConfidenceScore > alternatives[j].ConfidenceScore
+	})
 
 	return &models.MLResponse{
 		ID:              resp.Id,
 		Category:        resp.Category,
 		ConfidenceScore: resp.Confidence,
 		Alternatives:    alternatives,
+		Keywords:        resp.Keywords,
 	}
 }
@@ -12,6 +12,7 @@ func ToProtoEmail(email *models.Email) *pb.Email {
 	}
 	return &pb.Email{
 		Id:         email.ID,
+		Mailbox:    email.Mailbox,
 		Subject:    email.Subject,
 		Body:       email.Body,
 		Sender:     email.Sender,
@@ -27,6 +28,7 @@ func FromProtoEmail(pbEmail *pb.Email) *models.Email {
 	}
 	return &models.Email{
 		ID:         pbEmail.Id,
+		Mailbox:    pbEmail.Mailbox,
 		Subject:    pbEmail.Subject,
 		Body:       pbEmail.Body,
 		Sender:     pbEmail.Sender,
@@ -40,10 +42,39 @@ func ToProtoCategoryResult(result *models.CategoryResult) *pb.CategoryResult {
 	if result == nil {
 		return nil
 	}
+	alternatives := make([]*pb.Alternative, len(result.Alternatives))
+	for i, alt := range result.Alternatives {
+		alternatives[i] = &pb.Alternative{
+			Category:        alt.Category,
+			ConfidenceScore: alt.ConfidenceScore,
+		}
+	}
+	labels := make([]*pb.Label, len(result.Labels))
+	for i, label := range result.Labels {
+		labels[i] = &pb.Label{
+			Category:        label.Category,
+			ConfidenceScore: label.ConfidenceScore,
+		}
+	}
 	return &pb.CategoryResult{
-		Id:              result.EmailID,
-		Categories:      result.Categories,
-		ConfidenceScore: result.ConfidenceScore,
+		Id:                result.EmailID,
+		Categories:        result.Categories,
+		ConfidenceScore:   result.ConfidenceScore,
+		Labels:            labels,
+		Error:             result.Error,
+		Alternatives:      alternatives,
+		Keywords:          result.Keywords,
+		MatchedRule:       result.MatchedRule,
+		NeedsReview:       result.NeedsReview,
+		PredictedCategory: result.PredictedCategory,
+		Degraded:          result.Degraded,
+		Provenance: &pb.Provenance{
+			ModelName:      result.Provenance.ModelName,
+			ModelVersion:   result.Provenance.ModelVersion,
+			RuleSetHash:    result.Provenance.RuleSetHash,
+			ServiceVersion: result.Provenance.ServiceVersion,
+			DecisionPath:   string(result.Provenance.DecisionPath),
+		},
 	}
 }
 
@@ -52,9 +83,104 @@ func FromProtoCategoryResult(pbResult *pb.CategoryResult) *models.CategoryResult
 	if pbResult == nil {
 		return nil
 	}
+	alternatives := make([]models.Alternative, len(pbResult.Alternatives))
+	for i, alt := range pbResult.Alternatives {
+		alternatives[i] = models.Alternative{
+			Category:        alt.Category,
+			ConfidenceScore: alt.ConfidenceScore,
+		}
+	}
+	labels := make([]models.Label, len(pbResult.Labels))
+	for i, label := range pbResult.Labels {
+		labels[i] = models.Label{
+			Category:        label.Category,
+			ConfidenceScore: label.ConfidenceScore,
+		}
+	}
 	return &models.CategoryResult{
-		EmailID:         pbResult.Id,
-		Categories:      pbResult.Categories,
-		ConfidenceScore: pbResult.ConfidenceScore,
+		EmailID:           pbResult.Id,
+		Categories:        pbResult.Categories,
+		ConfidenceScore:   pbResult.ConfidenceScore,
+		Labels:            labels,
+		Error:             pbResult.Error,
+		Alternatives:      alternatives,
+		Keywords:          pbResult.Keywords,
+		MatchedRule:       pbResult.MatchedRule,
+		NeedsReview:       pbResult.NeedsReview,
+		PredictedCategory: pbResult.PredictedCategory,
+		Degraded:          pbResult.Degraded,
+		Provenance: models.Provenance{
+			ModelName:      pbResult.GetProvenance().GetModelName(),
+			ModelVersion:   pbResult.GetProvenance().GetModelVersion(),
+			RuleSetHash:    pbResult.GetProvenance().GetRuleSetHash(),
+			ServiceVersion: pbResult.GetProvenance().GetServiceVersion(),
+			DecisionPath:   models.DecisionPath(pbResult.GetProvenance().GetDecisionPath()),
+		},
+	}
+}
+
+// ToProtoBatchResponse converts an internal BatchEmailResponse model into a protobuf BatchCategorizeResponse message.
+func ToProtoBatchResponse(batch *models.BatchEmailResponse) *pb.BatchCategorizeResponse {
+	if batch == nil {
+		return nil
+	}
+	results := make([]*pb.CategoryResult, len(batch.Results))
+	for i := range batch.Results {
+		results[i] = ToProtoCategoryResult(&batch.Results[i])
+	}
+	return &pb.BatchCategorizeResponse{
+		Results: results,
+		Total:   batch.Total,
+		Failed:  batch.Failed,
+	}
+}
+
+// ToProtoUserCategory converts an internal UserCategory model into a protobuf UserCategory message.
+func ToProtoUserCategory(category *models.UserCategory) *pb.UserCategory {
+	if category == nil {
+		return nil
+	}
+	rules := make([]*pb.CategoryRule, len(category.Rules))
+	for i, rule := range category.Rules {
+		rules[i] = &pb.CategoryRule{
+			Name:             rule.Name,
+			Senders:          rule.Senders,
+			StrongKeywords:   rule.StrongKeywords,
+			SubjectPatterns:  rule.SubjectPatterns,
+			HeaderIndicators: rule.HeaderIndicators,
+			MinScore:         int32(rule.MinScore),
+		}
+	}
+	return &pb.UserCategory{
+		Id:          category.ID,
+		UserId:      category.UserID,
+		Name:        category.Name,
+		Description: category.Description,
+		Rules:       rules,
+	}
+}
+
+// FromProtoUserCategory converts a protobuf UserCategory message into an internal UserCategory model.
+func FromProtoUserCategory(pbCategory *pb.UserCategory) *models.UserCategory {
+	if pbCategory == nil {
+		return nil
+	}
+	rules := make([]models.CategoryRule, len(pbCategory.Rules))
+	for i, rule := range pbCategory.Rules {
+		rules[i] = models.CategoryRule{
+			Name:             rule.Name,
+			Senders:          rule.Senders,
+			StrongKeywords:   rule.StrongKeywords,
+			SubjectPatterns:  rule.SubjectPatterns,
+			HeaderIndicators: rule.HeaderIndicators,
+			MinScore:         int(rule.MinScore),
+		}
+	}
+	return &models.UserCategory{
+		ID:          pbCategory.Id,
+		UserID:      pbCategory.UserId,
+		Name:        pbCategory.Name,
+		Description: pbCategory.Description,
+		Rules:       rules,
 	}
 }
@@ -21,6 +21,13 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	EmailCategorizationService_CategorizeEmail_FullMethodName       = "/inboxpert.services.categorization.v1.EmailCategorizationService/CategorizeEmail"
 	EmailCategorizationService_BatchCategorizeEmails_FullMethodName = "/inboxpert.services.categorization.v1.EmailCategorizationService/BatchCategorizeEmails"
+	EmailCategorizationService_CreateUserCategory_FullMethodName    = "/inboxpert.services.categorization.v1.EmailCategorizationService/CreateUserCategory"
+	EmailCategorizationService_ListUserCategories_FullMethodName    = "/inboxpert.services.categorization.v1.EmailCategorizationService/ListUserCategories"
+	EmailCategorizationService_UpdateUserCategory_FullMethodName    = "/inboxpert.services.categorization.v1.EmailCategorizationService/UpdateUserCategory"
+	EmailCategorizationService_DeleteUserCategory_FullMethodName    = "/inboxpert.services.categorization.v1.EmailCategorizationService/DeleteUserCategory"
+	EmailCategorizationService_SubmitFeedback_FullMethodName        = "/inboxpert.services.categorization.v1.EmailCategorizationService/SubmitFeedback"
+	EmailCategorizationService_ListReviewEmails_FullMethodName      = "/inboxpert.services.categorization.v1.EmailCategorizationService/ListReviewEmails"
+	EmailCategorizationService_GetTaxonomy_FullMethodName           = "/inboxpert.services.categorization.v1.EmailCategorizationService/GetTaxonomy"
 )
 
 // EmailCategorizationServiceClient is the client API for EmailCategorizationService service.
@@ -29,6 +36,13 @@ const (
 type EmailCategorizationServiceClient interface {
 	CategorizeEmail(ctx context.Context, in *CategorizeRequest, opts ...grpc.CallOption) (*CategorizeResponse, error)
 	BatchCategorizeEmails(ctx context.Context, in *BatchCategorizeRequest, opts ...grpc.CallOption) (*BatchCategorizeResponse, error)
+	CreateUserCategory(ctx context.Context, in *CreateUserCategoryRequest, opts ...grpc.CallOption) (*UserCategoryResponse, error)
+	ListUserCategories(ctx context.Context, in *ListUserCategoriesRequest, opts ...grpc.CallOption) (*ListUserCategoriesResponse, error)
+	UpdateUserCategory(ctx context.Context, in *UpdateUserCategoryRequest, opts ...grpc.CallOption) (*UserCategoryResponse, error)
+	DeleteUserCategory(ctx context.Context, in *DeleteUserCategoryRequest, opts ...grpc.CallOption) (*DeleteUserCategoryResponse, error)
+	SubmitFeedback(ctx context.Context, in *SubmitFeedbackRequest, opts ...grpc.CallOption) (*SubmitFeedbackResponse, error)
+	ListReviewEmails(ctx context.Context, in *ListReviewEmailsRequest, opts ...grpc.CallOption) (*ListReviewEmailsResponse, error)
+	GetTaxonomy(ctx context.Context, in *GetTaxonomyRequest, opts ...grpc.CallOption) (*GetTaxonomyResponse, error)
 }
 
 type emailCategorizationServiceClient struct {
@@ -59,12 +73,89 @@ func (c *emailCategorizationServiceClient) BatchCategorizeEmails(ctx context.Con
 	return out, nil
 }
 
+func (c *emailCategorizationServiceClient) CreateUserCategory(ctx context.Context, in *CreateUserCategoryRequest, opts ...grpc.CallOption) (*UserCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserCategoryResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_CreateUserCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) ListUserCategories(ctx context.Context, in *ListUserCategoriesRequest, opts ...grpc.CallOption) (*ListUserCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserCategoriesResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_ListUserCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) UpdateUserCategory(ctx context.Context, in *UpdateUserCategoryRequest, opts ...grpc.CallOption) (*UserCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserCategoryResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_UpdateUserCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) DeleteUserCategory(ctx context.Context, in *DeleteUserCategoryRequest, opts ...grpc.CallOption) (*DeleteUserCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserCategoryResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_DeleteUserCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) SubmitFeedback(ctx context.Context, in *SubmitFeedbackRequest, opts ...grpc.CallOption) (*SubmitFeedbackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitFeedbackResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_SubmitFeedback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) ListReviewEmails(ctx context.Context, in *ListReviewEmailsRequest, opts ...grpc.CallOption) (*ListReviewEmailsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewEmailsResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_ListReviewEmails_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emailCategorizationServiceClient) GetTaxonomy(ctx context.Context, in *GetTaxonomyRequest, opts ...grpc.CallOption) (*GetTaxonomyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTaxonomyResponse)
+	err := c.cc.Invoke(ctx, EmailCategorizationService_GetTaxonomy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EmailCategorizationServiceServer is the server API for EmailCategorizationService service.
 // All implementations must embed UnimplementedEmailCategorizationServiceServer
 // for forward compatibility.
 type EmailCategorizationServiceServer interface {
 	CategorizeEmail(context.Context, *CategorizeRequest) (*CategorizeResponse, error)
 	BatchCategorizeEmails(context.Context, *BatchCategorizeRequest) (*BatchCategorizeResponse, error)
+	CreateUserCategory(context.Context, *CreateUserCategoryRequest) (*UserCategoryResponse, error)
+	ListUserCategories(context.Context, *ListUserCategoriesRequest) (*ListUserCategoriesResponse, error)
+	UpdateUserCategory(context.Context, *UpdateUserCategoryRequest) (*UserCategoryResponse, error)
+	DeleteUserCategory(context.Context, *DeleteUserCategoryRequest) (*DeleteUserCategoryResponse, error)
+	SubmitFeedback(context.Context, *SubmitFeedbackRequest) (*SubmitFeedbackResponse, error)
+	ListReviewEmails(context.Context, *ListReviewEmailsRequest) (*ListReviewEmailsResponse, error)
+	GetTaxonomy(context.Context, *GetTaxonomyRequest) (*GetTaxonomyResponse, error)
 	mustEmbedUnimplementedEmailCategorizationServiceServer()
 }
 
@@ -81,6 +172,27 @@ func (UnimplementedEmailCategorizationServiceServer) CategorizeEmail(context.Con
 func (UnimplementedEmailCategorizationServiceServer) BatchCategorizeEmails(context.Context, *BatchCategorizeRequest) (*BatchCategorizeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchCategorizeEmails not implemented")
 }
+func (UnimplementedEmailCategorizationServiceServer) CreateUserCategory(context.Context, *CreateUserCategoryRequest) (*UserCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUserCategory not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) ListUserCategories(context.Context, *ListUserCategoriesRequest) (*ListUserCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserCategories not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) UpdateUserCategory(context.Context, *UpdateUserCategoryRequest) (*UserCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUserCategory not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) DeleteUserCategory(context.Context, *DeleteUserCategoryRequest) (*DeleteUserCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserCategory not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) SubmitFeedback(context.Context, *SubmitFeedbackRequest) (*SubmitFeedbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitFeedback not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) ListReviewEmails(context.Context, *ListReviewEmailsRequest) (*ListReviewEmailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewEmails not implemented")
+}
+func (UnimplementedEmailCategorizationServiceServer) GetTaxonomy(context.Context, *GetTaxonomyRequest) (*GetTaxonomyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaxonomy not implemented")
+}
 func (UnimplementedEmailCategorizationServiceServer) mustEmbedUnimplementedEmailCategorizationServiceServer() {
 }
 func (UnimplementedEmailCategorizationServiceServer) testEmbeddedByValue() {}
@@ -139,6 +251,132 @@ func _EmailCategorizationService_BatchCategorizeEmails_Handler(srv interface{},
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EmailCategorizationService_CreateUserCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).CreateUserCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_CreateUserCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).CreateUserCategory(ctx, req.(*CreateUserCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_ListUserCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).ListUserCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_ListUserCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).ListUserCategories(ctx, req.(*ListUserCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_UpdateUserCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).UpdateUserCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_UpdateUserCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).UpdateUserCategory(ctx, req.(*UpdateUserCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_DeleteUserCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).DeleteUserCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_DeleteUserCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).DeleteUserCategory(ctx, req.(*DeleteUserCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_SubmitFeedback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitFeedbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).SubmitFeedback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_SubmitFeedback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).SubmitFeedback(ctx, req.(*SubmitFeedbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_ListReviewEmails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewEmailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).ListReviewEmails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_ListReviewEmails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).ListReviewEmails(ctx, req.(*ListReviewEmailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmailCategorizationService_GetTaxonomy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaxonomyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmailCategorizationServiceServer).GetTaxonomy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmailCategorizationService_GetTaxonomy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmailCategorizationServiceServer).GetTaxonomy(ctx, req.(*GetTaxonomyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EmailCategorizationService_ServiceDesc is the grpc.ServiceDesc for EmailCategorizationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,6 +392,34 @@ var EmailCategorizationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchCategorizeEmails",
 			Handler:    _EmailCategorizationService_BatchCategorizeEmails_Handler,
 		},
+		{
+			MethodName: "CreateUserCategory",
+			Handler:    _EmailCategorizationService_CreateUserCategory_Handler,
+		},
+		{
+			MethodName: "ListUserCategories",
+			Handler:    _EmailCategorizationService_ListUserCategories_Handler,
+		},
+		{
+			MethodName: "UpdateUserCategory",
+			Handler:    _EmailCategorizationService_UpdateUserCategory_Handler,
+		},
+		{
+			MethodName: "DeleteUserCategory",
+			Handler:    _EmailCategorizationService_DeleteUserCategory_Handler,
+		},
+		{
+			MethodName: "SubmitFeedback",
+			Handler:    _EmailCategorizationService_SubmitFeedback_Handler,
+		},
+		{
+			MethodName: "ListReviewEmails",
+			Handler:    _EmailCategorizationService_ListReviewEmails_Handler,
+		},
+		{
+			MethodName: "GetTaxonomy",
+			Handler:    _EmailCategorizationService_GetTaxonomy_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "email_categorization_service.proto",
@@ -7,6 +7,7 @@ import protos.ml_service_pb2 as svcpb
 import protos.ml_service_pb2_grpc as svcpb_grpc
 
 from concurrent import futures
+from grpc_health.v1 import health, health_pb2, health_pb2_grpc
 from grpc_reflection.v1alpha import reflection
 from .predict_category import EmailCategoryPredictor
 
@@ -23,6 +24,10 @@ class EmailPredictionServicer(svcpb_grpc.EmailPredictionServicer):
         self.model_path = model_file_path
         self.category_predictor = EmailCategoryPredictor(self.model_path)
 
+        # Report the model behind every prediction, so stored results can be traced back to it
+        self.model_name = os.getenv("MODEL_NAME", "email_classifier")
+        self.model_version = os.getenv("MODEL_VERSION", "")
+
     def CategorizeEmail(self, request, context):
         try:
             # Extract email body and subject and make prediction
@@ -37,27 +42,67 @@ class EmailPredictionServicer(svcpb_grpc.EmailPredictionServicer):
                 confidence=ml_confidence,
                 keywords=[],
                 alternatives=[msgpb.AlternativeCategory()],
+                model_name=self.model_name,
+                model_version=self.model_version,
             )
         except Exception as e:
             context.set_code(grpc.StatusCode.INTERNAL)
             context.set_details(f"Error during prediction: {str(e)}")
             return msgpb.CategoryResponse()
 
+    def BatchCategorizeEmail(self, request, context):
+        try:
+            # Predict every email of the batch, keeping the order of the request
+            results = []
+            for email in request.emails:
+                email_text = email.subject + " " + email.body
+                ml_category, ml_confidence = self.category_predictor.predict_email(
+                    email_text
+                )
+
+                results.append(
+                    msgpb.CategoryResponse(
+                        id=email.id,
+                        category=ml_category,
+                        confidence=ml_confidence,
+                        keywords=[],
+                        alternatives=[msgpb.AlternativeCategory()],
+                        model_name=self.model_name,
+                        model_version=self.model_version,
+                    )
+                )
+
+            return msgpb.BatchCategoryResponse(results=results)
+        except Exception as e:
+            context.set_code(grpc.StatusCode.INTERNAL)
+            context.set_details(f"Error during batch prediction: {str(e)}")
+            return msgpb.BatchCategoryResponse()
+
 
 def serve():
     server = grpc.server(futures.ThreadPoolExecutor(max_workers=10))
     svcpb_grpc.add_EmailPredictionServicer_to_server(EmailPredictionServicer(), server)
 
+    # Report the server as healthy, so clients balancing across replicas can eject unhealthy ones
+    service_name = svcpb.DESCRIPTOR.services_by_name["EmailPrediction"].full_name
+    health_servicer = health.HealthServicer()
+    health_pb2_grpc.add_HealthServicer_to_server(health_servicer, server)
+    health_servicer.set("", health_pb2.HealthCheckResponse.SERVING)
+    health_servicer.set(service_name, health_pb2.HealthCheckResponse.SERVING)
+
     # Enable reflection
     SERVICE_NAMES = (
-        svcpb.DESCRIPTOR.services_by_name["EmailPrediction"].full_name,
+        service_name,
+        health.SERVICE_NAME,
         reflection.SERVICE_NAME,
     )
     reflection.enable_server_reflection(SERVICE_NAMES, server)
 
-    server.add_insecure_port("[::]:50055")
+    # The port can be overridden to run several replicas on the same host
+    port = os.getenv("ML_SERVER_PORT", "50055")
+    server.add_insecure_port(f"[::]:{port}")
     server.start()
-    print("ML Prediction Server started on port 50055")
+    print(f"ML Prediction Server started on port {port}")
     server.wait_for_termination()
 
 
@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10ml_message.proto\x12\x14inboxpert.ml.service\"\xcf\x01\n\x0c\x45mailRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0f\n\x07subject\x18\x02 \x01(\t\x12\x0c\n\x04\x62ody\x18\x03 \x01(\t\x12\x0e\n\x06sender\x18\x04 \x01(\t\x12\x12\n\nrecipients\x18\x05 \x03(\t\x12@\n\x07headers\x18\x06 \x03(\x0b\x32/.inboxpert.ml.service.EmailRequest.HeadersEntry\x1a.\n\x0cHeadersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"G\n\x11\x42\x61tchEmailRequest\x12\x32\n\x06\x65mails\x18\x01 \x03(\x0b\x32\".inboxpert.ml.service.EmailRequest\"\x97\x01\n\x10\x43\x61tegoryResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\x10\n\x08\x63\x61tegory\x18\x02 \x01(\t\x12\x12\n\nconfidence\x18\x03 \x01(\x02\x12\x10\n\x08keywords\x18\x04 \x03(\t\x12?\n\x0c\x61lternatives\x18\x05 \x03(\x0b\x32).inboxpert.ml.service.AlternativeCategory\"P\n\x15\x42\x61tchCategoryResponse\x12\x37\n\x07results\x18\x01 \x03(\x0b\x32&.inboxpert.ml.service.CategoryResponse\"<\n\x13\x41lternativeCategory\x12\x10\n\x08\x63\x61tegory\x18\x01 \x01(\t\x12\x13\n\x0b\x63onfindence\x18\x02 \x01(\x02\x42\x36Z4github.com/samiransarii/inboXpert/services/ml_serverb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10ml_message.proto\x12\x14inboxpert.ml.service\"\xcf\x01\n\x0c\x45mailRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0f\n\x07subject\x18\x02 \x01(\t\x12\x0c\n\x04\x62ody\x18\x03 \x01(\t\x12\x0e\n\x06sender\x18\x04 \x01(\t\x12\x12\n\nrecipients\x18\x05 \x03(\t\x12@\n\x07headers\x18\x06 \x03(\x0b\x32/.inboxpert.ml.service.EmailRequest.HeadersEntry\x1a.\n\x0cHeadersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"G\n\x11\x42\x61tchEmailRequest\x12\x32\n\x06\x65mails\x18\x01 \x03(\x0b\x32\".inboxpert.ml.service.EmailRequest\"\xc2\x01\n\x10\x43\x61tegoryResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\x10\n\x08\x63\x61tegory\x18\x02 \x01(\t\x12\x12\n\nconfidence\x18\x03 \x01(\x02\x12\x10\n\x08keywords\x18\x04 \x03(\t\x12?\n\x0c\x61lternatives\x18\x05 \x03(\x0b\x32).inboxpert.ml.service.AlternativeCategory\x12\x12\n\nmodel_name\x18\x06 \x01(\t\x12\x15\n\rmodel_version\x18\x07 \x01(\t\"P\n\x15\x42\x61tchCategoryResponse\x12\x37\n\x07results\x18\x01 \x03(\x0b\x32&.inboxpert.ml.service.CategoryResponse\"<\n\x13\x41lternativeCategory\x12\x10\n\x08\x63\x61tegory\x18\x01 \x01(\t\x12\x13\n\x0b\x63onfindence\x18\x02 \x01(\x02\x42\x36Z4github.com/samiransarii/inboXpert/services/ml_serverb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -41,9 +41,9 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_BATCHEMAILREQUEST']._serialized_start=252
   _globals['_BATCHEMAILREQUEST']._serialized_end=323
   _globals['_CATEGORYRESPONSE']._serialized_start=326
-  _globals['_CATEGORYRESPONSE']._serialized_end=477
-  _globals['_BATCHCATEGORYRESPONSE']._serialized_start=479
-  _globals['_BATCHCATEGORYRESPONSE']._serialized_end=559
-  _globals['_ALTERNATIVECATEGORY']._serialized_start=561
-  _globals['_ALTERNATIVECATEGORY']._serialized_end=621
+  _globals['_CATEGORYRESPONSE']._serialized_end=520
+  _globals['_BATCHCATEGORYRESPONSE']._serialized_start=522
+  _globals['_BATCHCATEGORYRESPONSE']._serialized_end=602
+  _globals['_ALTERNATIVECATEGORY']._serialized_start=604
+  _globals['_ALTERNATIVECATEGORY']._serialized_end=664
 # @@protoc_insertion_point(module_scope)